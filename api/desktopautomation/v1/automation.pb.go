@@ -0,0 +1,1679 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: desktopautomation/v1/automation.proto
+
+// Remote control of a desktop: mouse, keyboard, screen capture and windows.
+//
+// All coordinates are in the coordinate space the server was started with
+// (--coords), logical units by default. Every RPC honours the call's deadline
+// and cancellation; a cancelled smooth movement or typing run stops between steps.
+
+package desktopautomationv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PathKind int32
+
+const (
+	PathKind_PATH_KIND_UNSPECIFIED PathKind = 0
+	PathKind_PATH_KIND_LINEAR      PathKind = 1
+	PathKind_PATH_KIND_BEZIER      PathKind = 2
+	PathKind_PATH_KIND_WIND        PathKind = 3
+)
+
+// Enum value maps for PathKind.
+var (
+	PathKind_name = map[int32]string{
+		0: "PATH_KIND_UNSPECIFIED",
+		1: "PATH_KIND_LINEAR",
+		2: "PATH_KIND_BEZIER",
+		3: "PATH_KIND_WIND",
+	}
+	PathKind_value = map[string]int32{
+		"PATH_KIND_UNSPECIFIED": 0,
+		"PATH_KIND_LINEAR":      1,
+		"PATH_KIND_BEZIER":      2,
+		"PATH_KIND_WIND":        3,
+	}
+)
+
+func (x PathKind) Enum() *PathKind {
+	p := new(PathKind)
+	*p = x
+	return p
+}
+
+func (x PathKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PathKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_desktopautomation_v1_automation_proto_enumTypes[0].Descriptor()
+}
+
+func (PathKind) Type() protoreflect.EnumType {
+	return &file_desktopautomation_v1_automation_proto_enumTypes[0]
+}
+
+func (x PathKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PathKind.Descriptor instead.
+func (PathKind) EnumDescriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{0}
+}
+
+type Easing int32
+
+const (
+	Easing_EASING_UNSPECIFIED Easing = 0
+	Easing_EASING_IN_OUT      Easing = 1
+	Easing_EASING_OUT         Easing = 2
+	Easing_EASING_NONE        Easing = 3
+)
+
+// Enum value maps for Easing.
+var (
+	Easing_name = map[int32]string{
+		0: "EASING_UNSPECIFIED",
+		1: "EASING_IN_OUT",
+		2: "EASING_OUT",
+		3: "EASING_NONE",
+	}
+	Easing_value = map[string]int32{
+		"EASING_UNSPECIFIED": 0,
+		"EASING_IN_OUT":      1,
+		"EASING_OUT":         2,
+		"EASING_NONE":        3,
+	}
+)
+
+func (x Easing) Enum() *Easing {
+	p := new(Easing)
+	*p = x
+	return p
+}
+
+func (x Easing) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Easing) Descriptor() protoreflect.EnumDescriptor {
+	return file_desktopautomation_v1_automation_proto_enumTypes[1].Descriptor()
+}
+
+func (Easing) Type() protoreflect.EnumType {
+	return &file_desktopautomation_v1_automation_proto_enumTypes[1]
+}
+
+func (x Easing) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Easing.Descriptor instead.
+func (Easing) EnumDescriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{1}
+}
+
+type MouseButton int32
+
+const (
+	MouseButton_MOUSE_BUTTON_UNSPECIFIED MouseButton = 0
+	MouseButton_MOUSE_BUTTON_LEFT        MouseButton = 1
+	MouseButton_MOUSE_BUTTON_RIGHT       MouseButton = 2
+)
+
+// Enum value maps for MouseButton.
+var (
+	MouseButton_name = map[int32]string{
+		0: "MOUSE_BUTTON_UNSPECIFIED",
+		1: "MOUSE_BUTTON_LEFT",
+		2: "MOUSE_BUTTON_RIGHT",
+	}
+	MouseButton_value = map[string]int32{
+		"MOUSE_BUTTON_UNSPECIFIED": 0,
+		"MOUSE_BUTTON_LEFT":        1,
+		"MOUSE_BUTTON_RIGHT":       2,
+	}
+)
+
+func (x MouseButton) Enum() *MouseButton {
+	p := new(MouseButton)
+	*p = x
+	return p
+}
+
+func (x MouseButton) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MouseButton) Descriptor() protoreflect.EnumDescriptor {
+	return file_desktopautomation_v1_automation_proto_enumTypes[2].Descriptor()
+}
+
+func (MouseButton) Type() protoreflect.EnumType {
+	return &file_desktopautomation_v1_automation_proto_enumTypes[2]
+}
+
+func (x MouseButton) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MouseButton.Descriptor instead.
+func (MouseButton) EnumDescriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{2}
+}
+
+type KeyAction int32
+
+const (
+	KeyAction_KEY_ACTION_UNSPECIFIED KeyAction = 0
+	// Press and release.
+	KeyAction_KEY_ACTION_PRESS KeyAction = 1
+	// Hold down until released.
+	KeyAction_KEY_ACTION_DOWN KeyAction = 2
+	// Release a held key.
+	KeyAction_KEY_ACTION_UP KeyAction = 3
+)
+
+// Enum value maps for KeyAction.
+var (
+	KeyAction_name = map[int32]string{
+		0: "KEY_ACTION_UNSPECIFIED",
+		1: "KEY_ACTION_PRESS",
+		2: "KEY_ACTION_DOWN",
+		3: "KEY_ACTION_UP",
+	}
+	KeyAction_value = map[string]int32{
+		"KEY_ACTION_UNSPECIFIED": 0,
+		"KEY_ACTION_PRESS":       1,
+		"KEY_ACTION_DOWN":        2,
+		"KEY_ACTION_UP":          3,
+	}
+)
+
+func (x KeyAction) Enum() *KeyAction {
+	p := new(KeyAction)
+	*p = x
+	return p
+}
+
+func (x KeyAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KeyAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_desktopautomation_v1_automation_proto_enumTypes[3].Descriptor()
+}
+
+func (KeyAction) Type() protoreflect.EnumType {
+	return &file_desktopautomation_v1_automation_proto_enumTypes[3]
+}
+
+func (x KeyAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KeyAction.Descriptor instead.
+func (KeyAction) EnumDescriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{3}
+}
+
+// Point is a position on the screen.
+type Point struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Point) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Point) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+// Rect is a rectangle on the screen.
+type Rect struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	Width         int32                  `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rect) Reset() {
+	*x = Rect{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rect) ProtoMessage() {}
+
+func (x *Rect) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rect.ProtoReflect.Descriptor instead.
+func (*Rect) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Rect) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Rect) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *Rect) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Rect) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+// Target selects a position.
+type Target struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Absolute coordinates, used unless expression is set.
+	Point *Point `protobuf:"bytes,1,opt,name=point,proto3" json:"point,omitempty"`
+	// Target expression such as "50%,50%", "+10,-5" or "@center".
+	Expression string `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
+	// Frame for percentages and anchors: "screen" (default) or "window".
+	RelativeTo    string `protobuf:"bytes,3,opt,name=relative_to,json=relativeTo,proto3" json:"relative_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Target) Reset() {
+	*x = Target{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Target) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Target) ProtoMessage() {}
+
+func (x *Target) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Target.ProtoReflect.Descriptor instead.
+func (*Target) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Target) GetPoint() *Point {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+func (x *Target) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *Target) GetRelativeTo() string {
+	if x != nil {
+		return x.RelativeTo
+	}
+	return ""
+}
+
+type MoveRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Target *Target                `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	// Animate the movement; implied by a path other than unspecified.
+	Smooth bool `protobuf:"varint,2,opt,name=smooth,proto3" json:"smooth,omitempty"`
+	// Duration of smooth movement in seconds, 1 by default.
+	Duration  float64  `protobuf:"fixed64,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Path      PathKind `protobuf:"varint,4,opt,name=path,proto3,enum=desktopautomation.v1.PathKind" json:"path,omitempty"`
+	Easing    Easing   `protobuf:"varint,5,opt,name=easing,proto3,enum=desktopautomation.v1.Easing" json:"easing,omitempty"`
+	Overshoot bool     `protobuf:"varint,6,opt,name=overshoot,proto3" json:"overshoot,omitempty"`
+	// Standard deviation in pixels of noise added to each step.
+	Jitter float64 `protobuf:"fixed64,7,opt,name=jitter,proto3" json:"jitter,omitempty"`
+	// Seed for the randomized trajectory, random if zero.
+	Seed          int64 `protobuf:"varint,8,opt,name=seed,proto3" json:"seed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveRequest) Reset() {
+	*x = MoveRequest{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveRequest) ProtoMessage() {}
+
+func (x *MoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveRequest.ProtoReflect.Descriptor instead.
+func (*MoveRequest) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MoveRequest) GetTarget() *Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *MoveRequest) GetSmooth() bool {
+	if x != nil {
+		return x.Smooth
+	}
+	return false
+}
+
+func (x *MoveRequest) GetDuration() float64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *MoveRequest) GetPath() PathKind {
+	if x != nil {
+		return x.Path
+	}
+	return PathKind_PATH_KIND_UNSPECIFIED
+}
+
+func (x *MoveRequest) GetEasing() Easing {
+	if x != nil {
+		return x.Easing
+	}
+	return Easing_EASING_UNSPECIFIED
+}
+
+func (x *MoveRequest) GetOvershoot() bool {
+	if x != nil {
+		return x.Overshoot
+	}
+	return false
+}
+
+func (x *MoveRequest) GetJitter() float64 {
+	if x != nil {
+		return x.Jitter
+	}
+	return 0
+}
+
+func (x *MoveRequest) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+type ClickRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Target *Target                `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	// Left if unspecified.
+	Button MouseButton `protobuf:"varint,2,opt,name=button,proto3,enum=desktopautomation.v1.MouseButton" json:"button,omitempty"`
+	// Double click; only supported with the left button.
+	Double        bool `protobuf:"varint,3,opt,name=double,proto3" json:"double,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClickRequest) Reset() {
+	*x = ClickRequest{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClickRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClickRequest) ProtoMessage() {}
+
+func (x *ClickRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClickRequest.ProtoReflect.Descriptor instead.
+func (*ClickRequest) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ClickRequest) GetTarget() *Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *ClickRequest) GetButton() MouseButton {
+	if x != nil {
+		return x.Button
+	}
+	return MouseButton_MOUSE_BUTTON_UNSPECIFIED
+}
+
+func (x *ClickRequest) GetDouble() bool {
+	if x != nil {
+		return x.Double
+	}
+	return false
+}
+
+type ScrollRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Positive values scroll right.
+	Dx int32 `protobuf:"varint,1,opt,name=dx,proto3" json:"dx,omitempty"`
+	// Positive values scroll down.
+	Dy            int32 `protobuf:"varint,2,opt,name=dy,proto3" json:"dy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScrollRequest) Reset() {
+	*x = ScrollRequest{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScrollRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrollRequest) ProtoMessage() {}
+
+func (x *ScrollRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrollRequest.ProtoReflect.Descriptor instead.
+func (*ScrollRequest) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ScrollRequest) GetDx() int32 {
+	if x != nil {
+		return x.Dx
+	}
+	return 0
+}
+
+func (x *ScrollRequest) GetDy() int32 {
+	if x != nil {
+		return x.Dy
+	}
+	return 0
+}
+
+type ScrollResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScrollResponse) Reset() {
+	*x = ScrollResponse{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScrollResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrollResponse) ProtoMessage() {}
+
+func (x *ScrollResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrollResponse.ProtoReflect.Descriptor instead.
+func (*ScrollResponse) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{6}
+}
+
+type PositionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PositionRequest) Reset() {
+	*x = PositionRequest{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PositionRequest) ProtoMessage() {}
+
+func (x *PositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PositionRequest.ProtoReflect.Descriptor instead.
+func (*PositionRequest) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{7}
+}
+
+type TypeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Text  string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// Fixed delay in milliseconds between characters.
+	DelayMs int32 `protobuf:"varint,2,opt,name=delay_ms,json=delayMs,proto3" json:"delay_ms,omitempty"`
+	// Human-like typing profile: uniform, natural, fast or hesitant.
+	Profile string `protobuf:"bytes,3,opt,name=profile,proto3" json:"profile,omitempty"`
+	// Typing speed in words per minute for the profile.
+	Wpm float64 `protobuf:"fixed64,4,opt,name=wpm,proto3" json:"wpm,omitempty"`
+	// Seed for reproducible cadence, random if zero.
+	Seed          int64 `protobuf:"varint,5,opt,name=seed,proto3" json:"seed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TypeRequest) Reset() {
+	*x = TypeRequest{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeRequest) ProtoMessage() {}
+
+func (x *TypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeRequest.ProtoReflect.Descriptor instead.
+func (*TypeRequest) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TypeRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *TypeRequest) GetDelayMs() int32 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
+func (x *TypeRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *TypeRequest) GetWpm() float64 {
+	if x != nil {
+		return x.Wpm
+	}
+	return 0
+}
+
+func (x *TypeRequest) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+type TypeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TypeResponse) Reset() {
+	*x = TypeResponse{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TypeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeResponse) ProtoMessage() {}
+
+func (x *TypeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeResponse.ProtoReflect.Descriptor instead.
+func (*TypeResponse) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{9}
+}
+
+type KeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Key or combination joined with "+", e.g. "enter" or "ctrl+shift+t".
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Press if unspecified.
+	Action        KeyAction `protobuf:"varint,2,opt,name=action,proto3,enum=desktopautomation.v1.KeyAction" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyRequest) Reset() {
+	*x = KeyRequest{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyRequest) ProtoMessage() {}
+
+func (x *KeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyRequest.ProtoReflect.Descriptor instead.
+func (*KeyRequest) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *KeyRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *KeyRequest) GetAction() KeyAction {
+	if x != nil {
+		return x.Action
+	}
+	return KeyAction_KEY_ACTION_UNSPECIFIED
+}
+
+type KeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyResponse) Reset() {
+	*x = KeyResponse{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyResponse) ProtoMessage() {}
+
+func (x *KeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyResponse.ProtoReflect.Descriptor instead.
+func (*KeyResponse) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{11}
+}
+
+type SizeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SizeRequest) Reset() {
+	*x = SizeRequest{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SizeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SizeRequest) ProtoMessage() {}
+
+func (x *SizeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SizeRequest.ProtoReflect.Descriptor instead.
+func (*SizeRequest) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{12}
+}
+
+type SizeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Size in the server's coordinate space.
+	Width  int32 `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height int32 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// Physical pixels per logical unit.
+	Scale float64 `protobuf:"fixed64,3,opt,name=scale,proto3" json:"scale,omitempty"`
+	// Coordinate space of all coordinates: logical, physical or screenshot.
+	Coords        string `protobuf:"bytes,4,opt,name=coords,proto3" json:"coords,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SizeResponse) Reset() {
+	*x = SizeResponse{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SizeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SizeResponse) ProtoMessage() {}
+
+func (x *SizeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SizeResponse.ProtoReflect.Descriptor instead.
+func (*SizeResponse) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SizeResponse) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *SizeResponse) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *SizeResponse) GetScale() float64 {
+	if x != nil {
+		return x.Scale
+	}
+	return 0
+}
+
+func (x *SizeResponse) GetCoords() string {
+	if x != nil {
+		return x.Coords
+	}
+	return ""
+}
+
+type CaptureRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Region to capture; the full screen if empty.
+	Region        *Rect `protobuf:"bytes,1,opt,name=region,proto3" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaptureRequest) Reset() {
+	*x = CaptureRequest{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptureRequest) ProtoMessage() {}
+
+func (x *CaptureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptureRequest.ProtoReflect.Descriptor instead.
+func (*CaptureRequest) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CaptureRequest) GetRegion() *Rect {
+	if x != nil {
+		return x.Region
+	}
+	return nil
+}
+
+type StreamCapturesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Region to capture; the full screen if empty.
+	Region *Rect `protobuf:"bytes,1,opt,name=region,proto3" json:"region,omitempty"`
+	// Frames per second, 1 by default.
+	Fps float64 `protobuf:"fixed64,2,opt,name=fps,proto3" json:"fps,omitempty"`
+	// Number of frames after which the stream ends; unlimited if zero.
+	MaxFrames     int32 `protobuf:"varint,3,opt,name=max_frames,json=maxFrames,proto3" json:"max_frames,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamCapturesRequest) Reset() {
+	*x = StreamCapturesRequest{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamCapturesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCapturesRequest) ProtoMessage() {}
+
+func (x *StreamCapturesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCapturesRequest.ProtoReflect.Descriptor instead.
+func (*StreamCapturesRequest) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *StreamCapturesRequest) GetRegion() *Rect {
+	if x != nil {
+		return x.Region
+	}
+	return nil
+}
+
+func (x *StreamCapturesRequest) GetFps() float64 {
+	if x != nil {
+		return x.Fps
+	}
+	return 0
+}
+
+func (x *StreamCapturesRequest) GetMaxFrames() int32 {
+	if x != nil {
+		return x.MaxFrames
+	}
+	return 0
+}
+
+// Image is a PNG encoded screenshot in physical pixels.
+type Image struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Png    []byte                 `protobuf:"bytes,1,opt,name=png,proto3" json:"png,omitempty"`
+	Width  int32                  `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height int32                  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	// Capture time in Unix nanoseconds.
+	Timestamp     int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Image) Reset() {
+	*x = Image{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Image) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Image) ProtoMessage() {}
+
+func (x *Image) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Image.ProtoReflect.Descriptor instead.
+func (*Image) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Image) GetPng() []byte {
+	if x != nil {
+		return x.Png
+	}
+	return nil
+}
+
+func (x *Image) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Image) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Image) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type ActiveBoundsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActiveBoundsRequest) Reset() {
+	*x = ActiveBoundsRequest{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActiveBoundsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActiveBoundsRequest) ProtoMessage() {}
+
+func (x *ActiveBoundsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActiveBoundsRequest.ProtoReflect.Descriptor instead.
+func (*ActiveBoundsRequest) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{17}
+}
+
+// InputEvent is a single input action sent over InputEvents.
+type InputEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Chosen by the client and echoed in the result.
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*InputEvent_Move
+	//	*InputEvent_Click
+	//	*InputEvent_Scroll
+	//	*InputEvent_Type
+	//	*InputEvent_Key
+	Event         isInputEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InputEvent) Reset() {
+	*x = InputEvent{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InputEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InputEvent) ProtoMessage() {}
+
+func (x *InputEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InputEvent.ProtoReflect.Descriptor instead.
+func (*InputEvent) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *InputEvent) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *InputEvent) GetEvent() isInputEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *InputEvent) GetMove() *MoveRequest {
+	if x != nil {
+		if x, ok := x.Event.(*InputEvent_Move); ok {
+			return x.Move
+		}
+	}
+	return nil
+}
+
+func (x *InputEvent) GetClick() *ClickRequest {
+	if x != nil {
+		if x, ok := x.Event.(*InputEvent_Click); ok {
+			return x.Click
+		}
+	}
+	return nil
+}
+
+func (x *InputEvent) GetScroll() *ScrollRequest {
+	if x != nil {
+		if x, ok := x.Event.(*InputEvent_Scroll); ok {
+			return x.Scroll
+		}
+	}
+	return nil
+}
+
+func (x *InputEvent) GetType() *TypeRequest {
+	if x != nil {
+		if x, ok := x.Event.(*InputEvent_Type); ok {
+			return x.Type
+		}
+	}
+	return nil
+}
+
+func (x *InputEvent) GetKey() *KeyRequest {
+	if x != nil {
+		if x, ok := x.Event.(*InputEvent_Key); ok {
+			return x.Key
+		}
+	}
+	return nil
+}
+
+type isInputEvent_Event interface {
+	isInputEvent_Event()
+}
+
+type InputEvent_Move struct {
+	Move *MoveRequest `protobuf:"bytes,2,opt,name=move,proto3,oneof"`
+}
+
+type InputEvent_Click struct {
+	Click *ClickRequest `protobuf:"bytes,3,opt,name=click,proto3,oneof"`
+}
+
+type InputEvent_Scroll struct {
+	Scroll *ScrollRequest `protobuf:"bytes,4,opt,name=scroll,proto3,oneof"`
+}
+
+type InputEvent_Type struct {
+	Type *TypeRequest `protobuf:"bytes,5,opt,name=type,proto3,oneof"`
+}
+
+type InputEvent_Key struct {
+	Key *KeyRequest `protobuf:"bytes,6,opt,name=key,proto3,oneof"`
+}
+
+func (*InputEvent_Move) isInputEvent_Event() {}
+
+func (*InputEvent_Click) isInputEvent_Event() {}
+
+func (*InputEvent_Scroll) isInputEvent_Event() {}
+
+func (*InputEvent_Type) isInputEvent_Event() {}
+
+func (*InputEvent_Key) isInputEvent_Event() {}
+
+type InputEventResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Empty on success.
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// Cursor position after the event.
+	Position      *Point `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InputEventResult) Reset() {
+	*x = InputEventResult{}
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InputEventResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InputEventResult) ProtoMessage() {}
+
+func (x *InputEventResult) ProtoReflect() protoreflect.Message {
+	mi := &file_desktopautomation_v1_automation_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InputEventResult.ProtoReflect.Descriptor instead.
+func (*InputEventResult) Descriptor() ([]byte, []int) {
+	return file_desktopautomation_v1_automation_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *InputEventResult) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *InputEventResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *InputEventResult) GetPosition() *Point {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+var File_desktopautomation_v1_automation_proto protoreflect.FileDescriptor
+
+const file_desktopautomation_v1_automation_proto_rawDesc = "" +
+	"\n" +
+	"%desktopautomation/v1/automation.proto\x12\x14desktopautomation.v1\"#\n" +
+	"\x05Point\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\"P\n" +
+	"\x04Rect\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\x12\x14\n" +
+	"\x05width\x18\x03 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x04 \x01(\x05R\x06height\"|\n" +
+	"\x06Target\x121\n" +
+	"\x05point\x18\x01 \x01(\v2\x1b.desktopautomation.v1.PointR\x05point\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x02 \x01(\tR\n" +
+	"expression\x12\x1f\n" +
+	"\vrelative_to\x18\x03 \x01(\tR\n" +
+	"relativeTo\"\xab\x02\n" +
+	"\vMoveRequest\x124\n" +
+	"\x06target\x18\x01 \x01(\v2\x1c.desktopautomation.v1.TargetR\x06target\x12\x16\n" +
+	"\x06smooth\x18\x02 \x01(\bR\x06smooth\x12\x1a\n" +
+	"\bduration\x18\x03 \x01(\x01R\bduration\x122\n" +
+	"\x04path\x18\x04 \x01(\x0e2\x1e.desktopautomation.v1.PathKindR\x04path\x124\n" +
+	"\x06easing\x18\x05 \x01(\x0e2\x1c.desktopautomation.v1.EasingR\x06easing\x12\x1c\n" +
+	"\tovershoot\x18\x06 \x01(\bR\tovershoot\x12\x16\n" +
+	"\x06jitter\x18\a \x01(\x01R\x06jitter\x12\x12\n" +
+	"\x04seed\x18\b \x01(\x03R\x04seed\"\x97\x01\n" +
+	"\fClickRequest\x124\n" +
+	"\x06target\x18\x01 \x01(\v2\x1c.desktopautomation.v1.TargetR\x06target\x129\n" +
+	"\x06button\x18\x02 \x01(\x0e2!.desktopautomation.v1.MouseButtonR\x06button\x12\x16\n" +
+	"\x06double\x18\x03 \x01(\bR\x06double\"/\n" +
+	"\rScrollRequest\x12\x0e\n" +
+	"\x02dx\x18\x01 \x01(\x05R\x02dx\x12\x0e\n" +
+	"\x02dy\x18\x02 \x01(\x05R\x02dy\"\x10\n" +
+	"\x0eScrollResponse\"\x11\n" +
+	"\x0fPositionRequest\"|\n" +
+	"\vTypeRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x19\n" +
+	"\bdelay_ms\x18\x02 \x01(\x05R\adelayMs\x12\x18\n" +
+	"\aprofile\x18\x03 \x01(\tR\aprofile\x12\x10\n" +
+	"\x03wpm\x18\x04 \x01(\x01R\x03wpm\x12\x12\n" +
+	"\x04seed\x18\x05 \x01(\x03R\x04seed\"\x0e\n" +
+	"\fTypeResponse\"W\n" +
+	"\n" +
+	"KeyRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x127\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x1f.desktopautomation.v1.KeyActionR\x06action\"\r\n" +
+	"\vKeyResponse\"\r\n" +
+	"\vSizeRequest\"j\n" +
+	"\fSizeResponse\x12\x14\n" +
+	"\x05width\x18\x01 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\x05R\x06height\x12\x14\n" +
+	"\x05scale\x18\x03 \x01(\x01R\x05scale\x12\x16\n" +
+	"\x06coords\x18\x04 \x01(\tR\x06coords\"D\n" +
+	"\x0eCaptureRequest\x122\n" +
+	"\x06region\x18\x01 \x01(\v2\x1a.desktopautomation.v1.RectR\x06region\"|\n" +
+	"\x15StreamCapturesRequest\x122\n" +
+	"\x06region\x18\x01 \x01(\v2\x1a.desktopautomation.v1.RectR\x06region\x12\x10\n" +
+	"\x03fps\x18\x02 \x01(\x01R\x03fps\x12\x1d\n" +
+	"\n" +
+	"max_frames\x18\x03 \x01(\x05R\tmaxFrames\"e\n" +
+	"\x05Image\x12\x10\n" +
+	"\x03png\x18\x01 \x01(\fR\x03png\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x05R\x06height\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\"\x15\n" +
+	"\x13ActiveBoundsRequest\"\xc8\x02\n" +
+	"\n" +
+	"InputEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x127\n" +
+	"\x04move\x18\x02 \x01(\v2!.desktopautomation.v1.MoveRequestH\x00R\x04move\x12:\n" +
+	"\x05click\x18\x03 \x01(\v2\".desktopautomation.v1.ClickRequestH\x00R\x05click\x12=\n" +
+	"\x06scroll\x18\x04 \x01(\v2#.desktopautomation.v1.ScrollRequestH\x00R\x06scroll\x127\n" +
+	"\x04type\x18\x05 \x01(\v2!.desktopautomation.v1.TypeRequestH\x00R\x04type\x124\n" +
+	"\x03key\x18\x06 \x01(\v2 .desktopautomation.v1.KeyRequestH\x00R\x03keyB\a\n" +
+	"\x05event\"q\n" +
+	"\x10InputEventResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x127\n" +
+	"\bposition\x18\x03 \x01(\v2\x1b.desktopautomation.v1.PointR\bposition*e\n" +
+	"\bPathKind\x12\x19\n" +
+	"\x15PATH_KIND_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10PATH_KIND_LINEAR\x10\x01\x12\x14\n" +
+	"\x10PATH_KIND_BEZIER\x10\x02\x12\x12\n" +
+	"\x0ePATH_KIND_WIND\x10\x03*T\n" +
+	"\x06Easing\x12\x16\n" +
+	"\x12EASING_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rEASING_IN_OUT\x10\x01\x12\x0e\n" +
+	"\n" +
+	"EASING_OUT\x10\x02\x12\x0f\n" +
+	"\vEASING_NONE\x10\x03*Z\n" +
+	"\vMouseButton\x12\x1c\n" +
+	"\x18MOUSE_BUTTON_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11MOUSE_BUTTON_LEFT\x10\x01\x12\x16\n" +
+	"\x12MOUSE_BUTTON_RIGHT\x10\x02*e\n" +
+	"\tKeyAction\x12\x1a\n" +
+	"\x16KEY_ACTION_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10KEY_ACTION_PRESS\x10\x01\x12\x13\n" +
+	"\x0fKEY_ACTION_DOWN\x10\x02\x12\x11\n" +
+	"\rKEY_ACTION_UP\x10\x032\xbe\x02\n" +
+	"\x05Mouse\x12F\n" +
+	"\x04Move\x12!.desktopautomation.v1.MoveRequest\x1a\x1b.desktopautomation.v1.Point\x12H\n" +
+	"\x05Click\x12\".desktopautomation.v1.ClickRequest\x1a\x1b.desktopautomation.v1.Point\x12S\n" +
+	"\x06Scroll\x12#.desktopautomation.v1.ScrollRequest\x1a$.desktopautomation.v1.ScrollResponse\x12N\n" +
+	"\bPosition\x12%.desktopautomation.v1.PositionRequest\x1a\x1b.desktopautomation.v1.Point2\xa5\x01\n" +
+	"\bKeyboard\x12M\n" +
+	"\x04Type\x12!.desktopautomation.v1.TypeRequest\x1a\".desktopautomation.v1.TypeResponse\x12J\n" +
+	"\x03Key\x12 .desktopautomation.v1.KeyRequest\x1a!.desktopautomation.v1.KeyResponse2\x83\x02\n" +
+	"\x06Screen\x12M\n" +
+	"\x04Size\x12!.desktopautomation.v1.SizeRequest\x1a\".desktopautomation.v1.SizeResponse\x12L\n" +
+	"\aCapture\x12$.desktopautomation.v1.CaptureRequest\x1a\x1b.desktopautomation.v1.Image\x12\\\n" +
+	"\x0eStreamCaptures\x12+.desktopautomation.v1.StreamCapturesRequest\x1a\x1b.desktopautomation.v1.Image0\x012_\n" +
+	"\x06Window\x12U\n" +
+	"\fActiveBounds\x12).desktopautomation.v1.ActiveBoundsRequest\x1a\x1a.desktopautomation.v1.Rect2d\n" +
+	"\x05Input\x12[\n" +
+	"\vInputEvents\x12 .desktopautomation.v1.InputEvent\x1a&.desktopautomation.v1.InputEventResult(\x010\x01BTZRgithub.com/dmahlow/desktop-automation/api/desktopautomation/v1;desktopautomationv1b\x06proto3"
+
+var (
+	file_desktopautomation_v1_automation_proto_rawDescOnce sync.Once
+	file_desktopautomation_v1_automation_proto_rawDescData []byte
+)
+
+func file_desktopautomation_v1_automation_proto_rawDescGZIP() []byte {
+	file_desktopautomation_v1_automation_proto_rawDescOnce.Do(func() {
+		file_desktopautomation_v1_automation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_desktopautomation_v1_automation_proto_rawDesc), len(file_desktopautomation_v1_automation_proto_rawDesc)))
+	})
+	return file_desktopautomation_v1_automation_proto_rawDescData
+}
+
+var file_desktopautomation_v1_automation_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_desktopautomation_v1_automation_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_desktopautomation_v1_automation_proto_goTypes = []any{
+	(PathKind)(0),                 // 0: desktopautomation.v1.PathKind
+	(Easing)(0),                   // 1: desktopautomation.v1.Easing
+	(MouseButton)(0),              // 2: desktopautomation.v1.MouseButton
+	(KeyAction)(0),                // 3: desktopautomation.v1.KeyAction
+	(*Point)(nil),                 // 4: desktopautomation.v1.Point
+	(*Rect)(nil),                  // 5: desktopautomation.v1.Rect
+	(*Target)(nil),                // 6: desktopautomation.v1.Target
+	(*MoveRequest)(nil),           // 7: desktopautomation.v1.MoveRequest
+	(*ClickRequest)(nil),          // 8: desktopautomation.v1.ClickRequest
+	(*ScrollRequest)(nil),         // 9: desktopautomation.v1.ScrollRequest
+	(*ScrollResponse)(nil),        // 10: desktopautomation.v1.ScrollResponse
+	(*PositionRequest)(nil),       // 11: desktopautomation.v1.PositionRequest
+	(*TypeRequest)(nil),           // 12: desktopautomation.v1.TypeRequest
+	(*TypeResponse)(nil),          // 13: desktopautomation.v1.TypeResponse
+	(*KeyRequest)(nil),            // 14: desktopautomation.v1.KeyRequest
+	(*KeyResponse)(nil),           // 15: desktopautomation.v1.KeyResponse
+	(*SizeRequest)(nil),           // 16: desktopautomation.v1.SizeRequest
+	(*SizeResponse)(nil),          // 17: desktopautomation.v1.SizeResponse
+	(*CaptureRequest)(nil),        // 18: desktopautomation.v1.CaptureRequest
+	(*StreamCapturesRequest)(nil), // 19: desktopautomation.v1.StreamCapturesRequest
+	(*Image)(nil),                 // 20: desktopautomation.v1.Image
+	(*ActiveBoundsRequest)(nil),   // 21: desktopautomation.v1.ActiveBoundsRequest
+	(*InputEvent)(nil),            // 22: desktopautomation.v1.InputEvent
+	(*InputEventResult)(nil),      // 23: desktopautomation.v1.InputEventResult
+}
+var file_desktopautomation_v1_automation_proto_depIdxs = []int32{
+	4,  // 0: desktopautomation.v1.Target.point:type_name -> desktopautomation.v1.Point
+	6,  // 1: desktopautomation.v1.MoveRequest.target:type_name -> desktopautomation.v1.Target
+	0,  // 2: desktopautomation.v1.MoveRequest.path:type_name -> desktopautomation.v1.PathKind
+	1,  // 3: desktopautomation.v1.MoveRequest.easing:type_name -> desktopautomation.v1.Easing
+	6,  // 4: desktopautomation.v1.ClickRequest.target:type_name -> desktopautomation.v1.Target
+	2,  // 5: desktopautomation.v1.ClickRequest.button:type_name -> desktopautomation.v1.MouseButton
+	3,  // 6: desktopautomation.v1.KeyRequest.action:type_name -> desktopautomation.v1.KeyAction
+	5,  // 7: desktopautomation.v1.CaptureRequest.region:type_name -> desktopautomation.v1.Rect
+	5,  // 8: desktopautomation.v1.StreamCapturesRequest.region:type_name -> desktopautomation.v1.Rect
+	7,  // 9: desktopautomation.v1.InputEvent.move:type_name -> desktopautomation.v1.MoveRequest
+	8,  // 10: desktopautomation.v1.InputEvent.click:type_name -> desktopautomation.v1.ClickRequest
+	9,  // 11: desktopautomation.v1.InputEvent.scroll:type_name -> desktopautomation.v1.ScrollRequest
+	12, // 12: desktopautomation.v1.InputEvent.type:type_name -> desktopautomation.v1.TypeRequest
+	14, // 13: desktopautomation.v1.InputEvent.key:type_name -> desktopautomation.v1.KeyRequest
+	4,  // 14: desktopautomation.v1.InputEventResult.position:type_name -> desktopautomation.v1.Point
+	7,  // 15: desktopautomation.v1.Mouse.Move:input_type -> desktopautomation.v1.MoveRequest
+	8,  // 16: desktopautomation.v1.Mouse.Click:input_type -> desktopautomation.v1.ClickRequest
+	9,  // 17: desktopautomation.v1.Mouse.Scroll:input_type -> desktopautomation.v1.ScrollRequest
+	11, // 18: desktopautomation.v1.Mouse.Position:input_type -> desktopautomation.v1.PositionRequest
+	12, // 19: desktopautomation.v1.Keyboard.Type:input_type -> desktopautomation.v1.TypeRequest
+	14, // 20: desktopautomation.v1.Keyboard.Key:input_type -> desktopautomation.v1.KeyRequest
+	16, // 21: desktopautomation.v1.Screen.Size:input_type -> desktopautomation.v1.SizeRequest
+	18, // 22: desktopautomation.v1.Screen.Capture:input_type -> desktopautomation.v1.CaptureRequest
+	19, // 23: desktopautomation.v1.Screen.StreamCaptures:input_type -> desktopautomation.v1.StreamCapturesRequest
+	21, // 24: desktopautomation.v1.Window.ActiveBounds:input_type -> desktopautomation.v1.ActiveBoundsRequest
+	22, // 25: desktopautomation.v1.Input.InputEvents:input_type -> desktopautomation.v1.InputEvent
+	4,  // 26: desktopautomation.v1.Mouse.Move:output_type -> desktopautomation.v1.Point
+	4,  // 27: desktopautomation.v1.Mouse.Click:output_type -> desktopautomation.v1.Point
+	10, // 28: desktopautomation.v1.Mouse.Scroll:output_type -> desktopautomation.v1.ScrollResponse
+	4,  // 29: desktopautomation.v1.Mouse.Position:output_type -> desktopautomation.v1.Point
+	13, // 30: desktopautomation.v1.Keyboard.Type:output_type -> desktopautomation.v1.TypeResponse
+	15, // 31: desktopautomation.v1.Keyboard.Key:output_type -> desktopautomation.v1.KeyResponse
+	17, // 32: desktopautomation.v1.Screen.Size:output_type -> desktopautomation.v1.SizeResponse
+	20, // 33: desktopautomation.v1.Screen.Capture:output_type -> desktopautomation.v1.Image
+	20, // 34: desktopautomation.v1.Screen.StreamCaptures:output_type -> desktopautomation.v1.Image
+	5,  // 35: desktopautomation.v1.Window.ActiveBounds:output_type -> desktopautomation.v1.Rect
+	23, // 36: desktopautomation.v1.Input.InputEvents:output_type -> desktopautomation.v1.InputEventResult
+	26, // [26:37] is the sub-list for method output_type
+	15, // [15:26] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_desktopautomation_v1_automation_proto_init() }
+func file_desktopautomation_v1_automation_proto_init() {
+	if File_desktopautomation_v1_automation_proto != nil {
+		return
+	}
+	file_desktopautomation_v1_automation_proto_msgTypes[18].OneofWrappers = []any{
+		(*InputEvent_Move)(nil),
+		(*InputEvent_Click)(nil),
+		(*InputEvent_Scroll)(nil),
+		(*InputEvent_Type)(nil),
+		(*InputEvent_Key)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_desktopautomation_v1_automation_proto_rawDesc), len(file_desktopautomation_v1_automation_proto_rawDesc)),
+			NumEnums:      4,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   5,
+		},
+		GoTypes:           file_desktopautomation_v1_automation_proto_goTypes,
+		DependencyIndexes: file_desktopautomation_v1_automation_proto_depIdxs,
+		EnumInfos:         file_desktopautomation_v1_automation_proto_enumTypes,
+		MessageInfos:      file_desktopautomation_v1_automation_proto_msgTypes,
+	}.Build()
+	File_desktopautomation_v1_automation_proto = out.File
+	file_desktopautomation_v1_automation_proto_goTypes = nil
+	file_desktopautomation_v1_automation_proto_depIdxs = nil
+}
@@ -0,0 +1,807 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: desktopautomation/v1/automation.proto
+
+// Remote control of a desktop: mouse, keyboard, screen capture and windows.
+//
+// All coordinates are in the coordinate space the server was started with
+// (--coords), logical units by default. Every RPC honours the call's deadline
+// and cancellation; a cancelled smooth movement or typing run stops between steps.
+
+package desktopautomationv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Mouse_Move_FullMethodName     = "/desktopautomation.v1.Mouse/Move"
+	Mouse_Click_FullMethodName    = "/desktopautomation.v1.Mouse/Click"
+	Mouse_Scroll_FullMethodName   = "/desktopautomation.v1.Mouse/Scroll"
+	Mouse_Position_FullMethodName = "/desktopautomation.v1.Mouse/Position"
+)
+
+// MouseClient is the client API for Mouse service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Mouse moves the cursor, clicks and scrolls.
+type MouseClient interface {
+	// Move moves the cursor to a position, instantly or along a trajectory.
+	Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*Point, error)
+	// Click moves the cursor to a position and clicks there.
+	Click(ctx context.Context, in *ClickRequest, opts ...grpc.CallOption) (*Point, error)
+	// Scroll scrolls the mouse wheel at the current position.
+	Scroll(ctx context.Context, in *ScrollRequest, opts ...grpc.CallOption) (*ScrollResponse, error)
+	// Position returns the current cursor position.
+	Position(ctx context.Context, in *PositionRequest, opts ...grpc.CallOption) (*Point, error)
+}
+
+type mouseClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMouseClient(cc grpc.ClientConnInterface) MouseClient {
+	return &mouseClient{cc}
+}
+
+func (c *mouseClient) Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*Point, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Point)
+	err := c.cc.Invoke(ctx, Mouse_Move_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mouseClient) Click(ctx context.Context, in *ClickRequest, opts ...grpc.CallOption) (*Point, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Point)
+	err := c.cc.Invoke(ctx, Mouse_Click_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mouseClient) Scroll(ctx context.Context, in *ScrollRequest, opts ...grpc.CallOption) (*ScrollResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScrollResponse)
+	err := c.cc.Invoke(ctx, Mouse_Scroll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mouseClient) Position(ctx context.Context, in *PositionRequest, opts ...grpc.CallOption) (*Point, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Point)
+	err := c.cc.Invoke(ctx, Mouse_Position_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MouseServer is the server API for Mouse service.
+// All implementations must embed UnimplementedMouseServer
+// for forward compatibility.
+//
+// Mouse moves the cursor, clicks and scrolls.
+type MouseServer interface {
+	// Move moves the cursor to a position, instantly or along a trajectory.
+	Move(context.Context, *MoveRequest) (*Point, error)
+	// Click moves the cursor to a position and clicks there.
+	Click(context.Context, *ClickRequest) (*Point, error)
+	// Scroll scrolls the mouse wheel at the current position.
+	Scroll(context.Context, *ScrollRequest) (*ScrollResponse, error)
+	// Position returns the current cursor position.
+	Position(context.Context, *PositionRequest) (*Point, error)
+	mustEmbedUnimplementedMouseServer()
+}
+
+// UnimplementedMouseServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMouseServer struct{}
+
+func (UnimplementedMouseServer) Move(context.Context, *MoveRequest) (*Point, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Move not implemented")
+}
+func (UnimplementedMouseServer) Click(context.Context, *ClickRequest) (*Point, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Click not implemented")
+}
+func (UnimplementedMouseServer) Scroll(context.Context, *ScrollRequest) (*ScrollResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scroll not implemented")
+}
+func (UnimplementedMouseServer) Position(context.Context, *PositionRequest) (*Point, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Position not implemented")
+}
+func (UnimplementedMouseServer) mustEmbedUnimplementedMouseServer() {}
+func (UnimplementedMouseServer) testEmbeddedByValue()               {}
+
+// UnsafeMouseServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MouseServer will
+// result in compilation errors.
+type UnsafeMouseServer interface {
+	mustEmbedUnimplementedMouseServer()
+}
+
+func RegisterMouseServer(s grpc.ServiceRegistrar, srv MouseServer) {
+	// If the following call pancis, it indicates UnimplementedMouseServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Mouse_ServiceDesc, srv)
+}
+
+func _Mouse_Move_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MouseServer).Move(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mouse_Move_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MouseServer).Move(ctx, req.(*MoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mouse_Click_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClickRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MouseServer).Click(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mouse_Click_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MouseServer).Click(ctx, req.(*ClickRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mouse_Scroll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScrollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MouseServer).Scroll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mouse_Scroll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MouseServer).Scroll(ctx, req.(*ScrollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mouse_Position_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PositionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MouseServer).Position(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mouse_Position_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MouseServer).Position(ctx, req.(*PositionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Mouse_ServiceDesc is the grpc.ServiceDesc for Mouse service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Mouse_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "desktopautomation.v1.Mouse",
+	HandlerType: (*MouseServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Move",
+			Handler:    _Mouse_Move_Handler,
+		},
+		{
+			MethodName: "Click",
+			Handler:    _Mouse_Click_Handler,
+		},
+		{
+			MethodName: "Scroll",
+			Handler:    _Mouse_Scroll_Handler,
+		},
+		{
+			MethodName: "Position",
+			Handler:    _Mouse_Position_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "desktopautomation/v1/automation.proto",
+}
+
+const (
+	Keyboard_Type_FullMethodName = "/desktopautomation.v1.Keyboard/Type"
+	Keyboard_Key_FullMethodName  = "/desktopautomation.v1.Keyboard/Key"
+)
+
+// KeyboardClient is the client API for Keyboard service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Keyboard types text and presses keys.
+type KeyboardClient interface {
+	// Type types text at the current focus.
+	Type(ctx context.Context, in *TypeRequest, opts ...grpc.CallOption) (*TypeResponse, error)
+	// Key presses, holds or releases a key or key combination.
+	Key(ctx context.Context, in *KeyRequest, opts ...grpc.CallOption) (*KeyResponse, error)
+}
+
+type keyboardClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKeyboardClient(cc grpc.ClientConnInterface) KeyboardClient {
+	return &keyboardClient{cc}
+}
+
+func (c *keyboardClient) Type(ctx context.Context, in *TypeRequest, opts ...grpc.CallOption) (*TypeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TypeResponse)
+	err := c.cc.Invoke(ctx, Keyboard_Type_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyboardClient) Key(ctx context.Context, in *KeyRequest, opts ...grpc.CallOption) (*KeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KeyResponse)
+	err := c.cc.Invoke(ctx, Keyboard_Key_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KeyboardServer is the server API for Keyboard service.
+// All implementations must embed UnimplementedKeyboardServer
+// for forward compatibility.
+//
+// Keyboard types text and presses keys.
+type KeyboardServer interface {
+	// Type types text at the current focus.
+	Type(context.Context, *TypeRequest) (*TypeResponse, error)
+	// Key presses, holds or releases a key or key combination.
+	Key(context.Context, *KeyRequest) (*KeyResponse, error)
+	mustEmbedUnimplementedKeyboardServer()
+}
+
+// UnimplementedKeyboardServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedKeyboardServer struct{}
+
+func (UnimplementedKeyboardServer) Type(context.Context, *TypeRequest) (*TypeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Type not implemented")
+}
+func (UnimplementedKeyboardServer) Key(context.Context, *KeyRequest) (*KeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Key not implemented")
+}
+func (UnimplementedKeyboardServer) mustEmbedUnimplementedKeyboardServer() {}
+func (UnimplementedKeyboardServer) testEmbeddedByValue()                  {}
+
+// UnsafeKeyboardServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KeyboardServer will
+// result in compilation errors.
+type UnsafeKeyboardServer interface {
+	mustEmbedUnimplementedKeyboardServer()
+}
+
+func RegisterKeyboardServer(s grpc.ServiceRegistrar, srv KeyboardServer) {
+	// If the following call pancis, it indicates UnimplementedKeyboardServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Keyboard_ServiceDesc, srv)
+}
+
+func _Keyboard_Type_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyboardServer).Type(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keyboard_Type_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyboardServer).Type(ctx, req.(*TypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Keyboard_Key_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyboardServer).Key(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Keyboard_Key_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyboardServer).Key(ctx, req.(*KeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Keyboard_ServiceDesc is the grpc.ServiceDesc for Keyboard service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Keyboard_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "desktopautomation.v1.Keyboard",
+	HandlerType: (*KeyboardServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Type",
+			Handler:    _Keyboard_Type_Handler,
+		},
+		{
+			MethodName: "Key",
+			Handler:    _Keyboard_Key_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "desktopautomation/v1/automation.proto",
+}
+
+const (
+	Screen_Size_FullMethodName           = "/desktopautomation.v1.Screen/Size"
+	Screen_Capture_FullMethodName        = "/desktopautomation.v1.Screen/Capture"
+	Screen_StreamCaptures_FullMethodName = "/desktopautomation.v1.Screen/StreamCaptures"
+)
+
+// ScreenClient is the client API for Screen service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Screen captures screenshots and describes the display.
+type ScreenClient interface {
+	// Size returns the size of the main display.
+	Size(ctx context.Context, in *SizeRequest, opts ...grpc.CallOption) (*SizeResponse, error)
+	// Capture captures the screen or a region of it.
+	Capture(ctx context.Context, in *CaptureRequest, opts ...grpc.CallOption) (*Image, error)
+	// StreamCaptures captures the screen repeatedly at a fixed rate until the
+	// call is cancelled or max_frames have been sent.
+	StreamCaptures(ctx context.Context, in *StreamCapturesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Image], error)
+}
+
+type screenClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScreenClient(cc grpc.ClientConnInterface) ScreenClient {
+	return &screenClient{cc}
+}
+
+func (c *screenClient) Size(ctx context.Context, in *SizeRequest, opts ...grpc.CallOption) (*SizeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SizeResponse)
+	err := c.cc.Invoke(ctx, Screen_Size_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *screenClient) Capture(ctx context.Context, in *CaptureRequest, opts ...grpc.CallOption) (*Image, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Image)
+	err := c.cc.Invoke(ctx, Screen_Capture_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *screenClient) StreamCaptures(ctx context.Context, in *StreamCapturesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Image], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Screen_ServiceDesc.Streams[0], Screen_StreamCaptures_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamCapturesRequest, Image]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Screen_StreamCapturesClient = grpc.ServerStreamingClient[Image]
+
+// ScreenServer is the server API for Screen service.
+// All implementations must embed UnimplementedScreenServer
+// for forward compatibility.
+//
+// Screen captures screenshots and describes the display.
+type ScreenServer interface {
+	// Size returns the size of the main display.
+	Size(context.Context, *SizeRequest) (*SizeResponse, error)
+	// Capture captures the screen or a region of it.
+	Capture(context.Context, *CaptureRequest) (*Image, error)
+	// StreamCaptures captures the screen repeatedly at a fixed rate until the
+	// call is cancelled or max_frames have been sent.
+	StreamCaptures(*StreamCapturesRequest, grpc.ServerStreamingServer[Image]) error
+	mustEmbedUnimplementedScreenServer()
+}
+
+// UnimplementedScreenServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedScreenServer struct{}
+
+func (UnimplementedScreenServer) Size(context.Context, *SizeRequest) (*SizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Size not implemented")
+}
+func (UnimplementedScreenServer) Capture(context.Context, *CaptureRequest) (*Image, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Capture not implemented")
+}
+func (UnimplementedScreenServer) StreamCaptures(*StreamCapturesRequest, grpc.ServerStreamingServer[Image]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCaptures not implemented")
+}
+func (UnimplementedScreenServer) mustEmbedUnimplementedScreenServer() {}
+func (UnimplementedScreenServer) testEmbeddedByValue()                {}
+
+// UnsafeScreenServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScreenServer will
+// result in compilation errors.
+type UnsafeScreenServer interface {
+	mustEmbedUnimplementedScreenServer()
+}
+
+func RegisterScreenServer(s grpc.ServiceRegistrar, srv ScreenServer) {
+	// If the following call pancis, it indicates UnimplementedScreenServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Screen_ServiceDesc, srv)
+}
+
+func _Screen_Size_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScreenServer).Size(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Screen_Size_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScreenServer).Size(ctx, req.(*SizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Screen_Capture_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaptureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScreenServer).Capture(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Screen_Capture_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScreenServer).Capture(ctx, req.(*CaptureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Screen_StreamCaptures_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamCapturesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScreenServer).StreamCaptures(m, &grpc.GenericServerStream[StreamCapturesRequest, Image]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Screen_StreamCapturesServer = grpc.ServerStreamingServer[Image]
+
+// Screen_ServiceDesc is the grpc.ServiceDesc for Screen service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Screen_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "desktopautomation.v1.Screen",
+	HandlerType: (*ScreenServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Size",
+			Handler:    _Screen_Size_Handler,
+		},
+		{
+			MethodName: "Capture",
+			Handler:    _Screen_Capture_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamCaptures",
+			Handler:       _Screen_StreamCaptures_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "desktopautomation/v1/automation.proto",
+}
+
+const (
+	Window_ActiveBounds_FullMethodName = "/desktopautomation.v1.Window/ActiveBounds"
+)
+
+// WindowClient is the client API for Window service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Window describes application windows.
+type WindowClient interface {
+	// ActiveBounds returns the bounds of the focused window.
+	ActiveBounds(ctx context.Context, in *ActiveBoundsRequest, opts ...grpc.CallOption) (*Rect, error)
+}
+
+type windowClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWindowClient(cc grpc.ClientConnInterface) WindowClient {
+	return &windowClient{cc}
+}
+
+func (c *windowClient) ActiveBounds(ctx context.Context, in *ActiveBoundsRequest, opts ...grpc.CallOption) (*Rect, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Rect)
+	err := c.cc.Invoke(ctx, Window_ActiveBounds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WindowServer is the server API for Window service.
+// All implementations must embed UnimplementedWindowServer
+// for forward compatibility.
+//
+// Window describes application windows.
+type WindowServer interface {
+	// ActiveBounds returns the bounds of the focused window.
+	ActiveBounds(context.Context, *ActiveBoundsRequest) (*Rect, error)
+	mustEmbedUnimplementedWindowServer()
+}
+
+// UnimplementedWindowServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWindowServer struct{}
+
+func (UnimplementedWindowServer) ActiveBounds(context.Context, *ActiveBoundsRequest) (*Rect, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActiveBounds not implemented")
+}
+func (UnimplementedWindowServer) mustEmbedUnimplementedWindowServer() {}
+func (UnimplementedWindowServer) testEmbeddedByValue()                {}
+
+// UnsafeWindowServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WindowServer will
+// result in compilation errors.
+type UnsafeWindowServer interface {
+	mustEmbedUnimplementedWindowServer()
+}
+
+func RegisterWindowServer(s grpc.ServiceRegistrar, srv WindowServer) {
+	// If the following call pancis, it indicates UnimplementedWindowServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Window_ServiceDesc, srv)
+}
+
+func _Window_ActiveBounds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActiveBoundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WindowServer).ActiveBounds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Window_ActiveBounds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WindowServer).ActiveBounds(ctx, req.(*ActiveBoundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Window_ServiceDesc is the grpc.ServiceDesc for Window service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Window_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "desktopautomation.v1.Window",
+	HandlerType: (*WindowServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ActiveBounds",
+			Handler:    _Window_ActiveBounds_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "desktopautomation/v1/automation.proto",
+}
+
+const (
+	Input_InputEvents_FullMethodName = "/desktopautomation.v1.Input/InputEvents"
+)
+
+// InputClient is the client API for Input service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Input replays input events sent over a stream.
+type InputClient interface {
+	// InputEvents performs each event as it arrives, in order, and answers every
+	// event with a result carrying the same id. A failed event does not end the stream.
+	InputEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[InputEvent, InputEventResult], error)
+}
+
+type inputClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInputClient(cc grpc.ClientConnInterface) InputClient {
+	return &inputClient{cc}
+}
+
+func (c *inputClient) InputEvents(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[InputEvent, InputEventResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Input_ServiceDesc.Streams[0], Input_InputEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[InputEvent, InputEventResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Input_InputEventsClient = grpc.BidiStreamingClient[InputEvent, InputEventResult]
+
+// InputServer is the server API for Input service.
+// All implementations must embed UnimplementedInputServer
+// for forward compatibility.
+//
+// Input replays input events sent over a stream.
+type InputServer interface {
+	// InputEvents performs each event as it arrives, in order, and answers every
+	// event with a result carrying the same id. A failed event does not end the stream.
+	InputEvents(grpc.BidiStreamingServer[InputEvent, InputEventResult]) error
+	mustEmbedUnimplementedInputServer()
+}
+
+// UnimplementedInputServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInputServer struct{}
+
+func (UnimplementedInputServer) InputEvents(grpc.BidiStreamingServer[InputEvent, InputEventResult]) error {
+	return status.Errorf(codes.Unimplemented, "method InputEvents not implemented")
+}
+func (UnimplementedInputServer) mustEmbedUnimplementedInputServer() {}
+func (UnimplementedInputServer) testEmbeddedByValue()               {}
+
+// UnsafeInputServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InputServer will
+// result in compilation errors.
+type UnsafeInputServer interface {
+	mustEmbedUnimplementedInputServer()
+}
+
+func RegisterInputServer(s grpc.ServiceRegistrar, srv InputServer) {
+	// If the following call pancis, it indicates UnimplementedInputServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Input_ServiceDesc, srv)
+}
+
+func _Input_InputEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InputServer).InputEvents(&grpc.GenericServerStream[InputEvent, InputEventResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Input_InputEventsServer = grpc.BidiStreamingServer[InputEvent, InputEventResult]
+
+// Input_ServiceDesc is the grpc.ServiceDesc for Input service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Input_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "desktopautomation.v1.Input",
+	HandlerType: (*InputServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "InputEvents",
+			Handler:       _Input_InputEvents_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "desktopautomation/v1/automation.proto",
+}
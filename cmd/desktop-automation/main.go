@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	_ "github.com/charmbracelet/lipgloss"
 	"github.com/dmahlow/desktop-automation/internal/commands"
@@ -20,8 +23,18 @@ func main() {
 	// Initialize cobra
 	commands.AddCommands(rootCmd)
 
-	// Execute root command and handle errors gracefully
-	if err := rootCmd.Execute(); err != nil {
+	// Cancel running automation on Ctrl+C or termination
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Execute root command and handle errors gracefully. The backend is closed
+	// here rather than in a post-run hook, which cobra skips when a command fails.
+	err := rootCmd.ExecuteContext(ctx)
+	if closeErr := commands.CloseBackend(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		stop()
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/dmahlow/desktop-automation-mcp/internal/automation"
+)
+
+// maxActions bounds the number of steps of one execute_actions call
+const maxActions = 100
+
+// actionResult is the outcome of one step of execute_actions
+type actionResult struct {
+	Step   int    `json:"step"`
+	Action string `json:"action"`
+	OK     bool   `json:"ok"`
+	Result string `json:"result,omitempty"`
+	Output any    `json:"output,omitempty" jsonschema:"Structured result of the tool run by the action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// batchActions are the tools execute_actions can run as steps, besides the
+// built-in scroll and wait actions
+var batchActions = []string{
+	"click", "double_click", "right_click", "move_mouse", "type_text", "press_key",
+	"get_mouse_position", "get_pixel_color",
+	"a11y_click", "a11y_invoke", "a11y_set_text", "a11y_set_value",
+	"click_mark",
+}
+
+// executeActions runs the steps of an execute_actions call in order and
+// returns a result per step. Steps after a failing one are skipped unless
+// stopOnError is false. delay is the pause after each step that does not set
+// its own delay_after.
+func executeActions(ctx context.Context, s *server.MCPServer, actions []any, delay time.Duration, stopOnError bool) ([]actionResult, error) {
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("actions must contain at least one action")
+	}
+	if len(actions) > maxActions {
+		return nil, fmt.Errorf("too many actions: %d (maximum %d)", len(actions), maxActions)
+	}
+
+	var results []actionResult
+	for i, raw := range actions {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		args, ok := raw.(map[string]any)
+		if !ok {
+			return results, fmt.Errorf("action %d must be an object", i+1)
+		}
+		name, _ := args["action"].(string)
+		result := actionResult{Step: i + 1, Action: name}
+
+		text, output, err := runAction(ctx, s, name, args)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.OK = true
+			result.Result = text
+			result.Output = output
+		}
+		results = append(results, result)
+		if err != nil && stopOnError {
+			break
+		}
+
+		pause := delay
+		if ms, ok := args["delay_after"].(float64); ok {
+			pause = time.Duration(ms) * time.Millisecond
+		}
+		if pause > 0 && i < len(actions)-1 {
+			if err := sleep(ctx, pause); err != nil {
+				return results, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// runAction performs one step of execute_actions and returns its result text
+// and the structured result of the tool it ran, if any
+func runAction(ctx context.Context, s *server.MCPServer, name string, args map[string]any) (string, any, error) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+
+	switch name {
+	case "":
+		return "", nil, fmt.Errorf("action is required")
+	case "wait":
+		ms := request.GetFloat("ms", 0)
+		if ms <= 0 {
+			return "", nil, fmt.Errorf("wait requires a positive ms")
+		}
+		if err := sleep(ctx, time.Duration(ms)*time.Millisecond); err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("Waited %gms", ms), nil, nil
+	case "scroll":
+		if args["target"] != nil || args["x"] != nil || args["y"] != nil {
+			x, y, err := resolveTarget(request)
+			if err != nil {
+				return "", nil, err
+			}
+			if err := automation.Move(ctx, x, y); err != nil {
+				return "", nil, fmt.Errorf("move mouse failed: %w", err)
+			}
+		}
+		dx, dy := request.GetInt("dx", 0), request.GetInt("dy", 0)
+		if dx == 0 && dy == 0 {
+			return "", nil, fmt.Errorf("scroll requires dx or dy")
+		}
+		if err := automation.Scroll(ctx, dx, dy); err != nil {
+			return "", nil, fmt.Errorf("scroll failed: %w", err)
+		}
+		return fmt.Sprintf("Scrolled by (%d, %d)", dx, dy), nil, nil
+	}
+
+	tool := batchTool(s, name)
+	if tool == nil {
+		return "", nil, fmt.Errorf("unknown action %q", name)
+	}
+	result, err := tool.Handler(ctx, request)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var text string
+	for _, content := range result.Content {
+		if t, ok := content.(mcp.TextContent); ok {
+			text += t.Text
+		}
+	}
+	if result.IsError {
+		return "", nil, fmt.Errorf("%s", text)
+	}
+	return text, result.StructuredContent, nil
+}
+
+// batchTool returns the registered tool for a batchable action, or nil
+func batchTool(s *server.MCPServer, name string) *server.ServerTool {
+	for _, action := range batchActions {
+		if action == name {
+			return s.GetTool(name)
+		}
+	}
+	return nil
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// actionsResult builds the tool result of execute_actions from the results of
+// the steps that ran out of total actions
+func actionsResult(results []actionResult, total int, start time.Time, screenshot string) (*mcp.CallToolResult, error) {
+	batch := batchResult{Steps: results, Skipped: total - len(results), DurationMs: since(start)}
+	for _, r := range results {
+		if r.OK {
+			batch.Completed++
+		} else {
+			batch.Failed++
+		}
+	}
+
+	data, err := json.MarshalIndent(batch, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result := mcp.NewToolResultStructured(batch, string(data))
+	if screenshot != "" {
+		result.Content = append(result.Content, mcp.NewImageContent(screenshot, "image/png"))
+	}
+	result.IsError = batch.Failed > 0
+	return result, nil
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newStubServer returns a server whose click tool succeeds, press_key tool
+// reports a tool error, type_text tool fails with an error and whose
+// take_screenshot tool is not batchable. The actions run are appended to calls.
+func newStubServer(calls *[]string) *server.MCPServer {
+	s := server.NewMCPServer("test", "1.0.0")
+	s.AddTool(mcp.NewTool("click"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		*calls = append(*calls, "click")
+		return mcp.NewToolResultStructured(map[string]int{"x": 1, "y": 2}, "Clicked"), nil
+	})
+	s.AddTool(mcp.NewTool("press_key"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		*calls = append(*calls, "press_key")
+		return mcp.NewToolResultError("unknown key"), nil
+	})
+	s.AddTool(mcp.NewTool("type_text"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		*calls = append(*calls, "type_text")
+		return nil, errors.New("typing failed")
+	})
+	s.AddTool(mcp.NewTool("take_screenshot"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		*calls = append(*calls, "take_screenshot")
+		return mcp.NewToolResultText("Screenshot"), nil
+	})
+	return s
+}
+
+// actions builds the actions argument of execute_actions from action names
+func actions(names ...string) []any {
+	list := make([]any, len(names))
+	for i, name := range names {
+		list[i] = map[string]any{"action": name}
+	}
+	return list
+}
+
+func TestExecuteActions(t *testing.T) {
+	tests := []struct {
+		name        string
+		actions     []any
+		stopOnError bool
+		wantCalls   []string
+		wantOK      []bool
+		wantErrors  []string
+		wantSkipped int
+	}{
+		{
+			name:      "all succeed",
+			actions:   actions("click", "click"),
+			wantCalls: []string{"click", "click"},
+			wantOK:    []bool{true, true},
+		},
+		{
+			name:        "stop on tool error",
+			actions:     actions("click", "press_key", "click"),
+			stopOnError: true,
+			wantCalls:   []string{"click", "press_key"},
+			wantOK:      []bool{true, false},
+			wantErrors:  []string{"", "unknown key"},
+			wantSkipped: 1,
+		},
+		{
+			name:        "stop on handler error",
+			actions:     actions("type_text", "click", "click"),
+			stopOnError: true,
+			wantCalls:   []string{"type_text"},
+			wantOK:      []bool{false},
+			wantErrors:  []string{"typing failed"},
+			wantSkipped: 2,
+		},
+		{
+			name:       "continue after errors",
+			actions:    actions("press_key", "click", "type_text", "click"),
+			wantCalls:  []string{"press_key", "click", "type_text", "click"},
+			wantOK:     []bool{false, true, false, true},
+			wantErrors: []string{"unknown key", "", "typing failed", ""},
+		},
+		{
+			name:        "unknown action",
+			actions:     actions("click", "launch_rockets", "click"),
+			stopOnError: true,
+			wantCalls:   []string{"click"},
+			wantOK:      []bool{true, false},
+			wantErrors:  []string{"", `unknown action "launch_rockets"`},
+			wantSkipped: 1,
+		},
+		{
+			name:       "registered tool that is not batchable",
+			actions:    actions("take_screenshot"),
+			wantOK:     []bool{false},
+			wantErrors: []string{`unknown action "take_screenshot"`},
+		},
+		{
+			name:       "missing action",
+			actions:    []any{map[string]any{"x": 1.0}},
+			wantOK:     []bool{false},
+			wantErrors: []string{"action is required"},
+		},
+		{
+			name:       "wait without duration",
+			actions:    actions("wait"),
+			wantOK:     []bool{false},
+			wantErrors: []string{"wait requires a positive ms"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			s := newStubServer(&calls)
+
+			results, err := executeActions(context.Background(), s, tt.actions, 0, tt.stopOnError)
+			if err != nil {
+				t.Fatalf("executeActions() error = %v", err)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("tools called = %v, want %v", calls, tt.wantCalls)
+			}
+
+			var ok []bool
+			for i, r := range results {
+				ok = append(ok, r.OK)
+				if r.Step != i+1 {
+					t.Errorf("result %d has step %d", i, r.Step)
+				}
+				if tt.wantErrors != nil && r.Error != tt.wantErrors[i] {
+					t.Errorf("step %d error = %q, want %q", r.Step, r.Error, tt.wantErrors[i])
+				}
+				if r.OK && (r.Result != "Clicked" || r.Output == nil) {
+					t.Errorf("step %d result = %q, %v, want the click result", r.Step, r.Result, r.Output)
+				}
+			}
+			if !reflect.DeepEqual(ok, tt.wantOK) {
+				t.Errorf("steps succeeded = %v, want %v", ok, tt.wantOK)
+			}
+
+			result, err := actionsResult(results, len(tt.actions), time.Now(), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			batch := result.StructuredContent.(batchResult)
+			if batch.Skipped != tt.wantSkipped {
+				t.Errorf("skipped = %d, want %d", batch.Skipped, tt.wantSkipped)
+			}
+			if batch.Completed+batch.Failed+batch.Skipped != len(tt.actions) {
+				t.Errorf("completed %d, failed %d and skipped %d do not add up to %d actions",
+					batch.Completed, batch.Failed, batch.Skipped, len(tt.actions))
+			}
+			if result.IsError != (batch.Failed > 0) {
+				t.Errorf("IsError = %v with %d failed steps", result.IsError, batch.Failed)
+			}
+		})
+	}
+}
+
+func TestExecuteActionsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []any
+		want    string
+	}{
+		{"no actions", nil, "at least one action"},
+		{"too many actions", actions(strings.Fields(strings.Repeat("click ", maxActions+1))...), "too many actions"},
+		{"not an object", []any{"click"}, "action 1 must be an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			_, err := executeActions(context.Background(), newStubServer(&calls), tt.actions, 0, true)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("executeActions() error = %v, want %q", err, tt.want)
+			}
+			if len(calls) != 0 {
+				t.Errorf("tools called = %v, want none", calls)
+			}
+		})
+	}
+
+	var calls []string
+	if _, err := executeActions(context.Background(), newStubServer(&calls), actions(strings.Fields(strings.Repeat("click ", maxActions))...), 0, true); err != nil {
+		t.Errorf("executeActions() with %d actions error = %v", maxActions, err)
+	}
+}
+
+func TestExecuteActionsDelay(t *testing.T) {
+	// The default delay would time out the context unless overridden
+	const long = time.Hour
+
+	tests := []struct {
+		name    string
+		actions []any
+		delay   time.Duration
+		min     time.Duration
+	}{
+		{
+			name:    "no delay after the last action",
+			actions: actions("click"),
+			delay:   long,
+		},
+		{
+			name: "delay_after overrides the delay",
+			actions: []any{
+				map[string]any{"action": "click", "delay_after": 0.0},
+				map[string]any{"action": "click", "delay_after": 0.0},
+				map[string]any{"action": "click"},
+			},
+			delay: long,
+		},
+		{
+			name: "delay_after lengthens a pause",
+			actions: []any{
+				map[string]any{"action": "click", "delay_after": 50.0},
+				map[string]any{"action": "click"},
+			},
+			min: 50 * time.Millisecond,
+		},
+		{
+			name: "delay_after of a failed action",
+			actions: []any{
+				map[string]any{"action": "press_key", "delay_after": 50.0},
+				map[string]any{"action": "click"},
+			},
+			delay: long,
+			min:   50 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			var calls []string
+			start := time.Now()
+			results, err := executeActions(ctx, newStubServer(&calls), tt.actions, tt.delay, false)
+			elapsed := time.Since(start)
+			if err != nil {
+				t.Fatalf("executeActions() error = %v", err)
+			}
+			if len(results) != len(tt.actions) {
+				t.Errorf("%d results, want %d", len(results), len(tt.actions))
+			}
+			if elapsed < tt.min {
+				t.Errorf("took %v, want at least %v", elapsed, tt.min)
+			}
+		})
+	}
+}
+
+func TestExecuteActionsCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var calls []string
+	results, err := executeActions(ctx, newStubServer(&calls), actions("click", "click"), time.Hour, true)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("executeActions() error = %v, want deadline exceeded", err)
+	}
+	if len(results) != 1 || !reflect.DeepEqual(calls, []string{"click"}) {
+		t.Errorf("ran %v with %d results, want the first action only", calls, len(results))
+	}
+}
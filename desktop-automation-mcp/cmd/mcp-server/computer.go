@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/dmahlow/desktop-automation-mcp/internal/automation"
+)
+
+// computerActions are the actions of the computer tool
+var computerActions = []string{
+	"screenshot", "left_click", "right_click", "double_click", "mouse_move", "left_click_drag",
+	"type", "key", "scroll", "cursor_position", "wait",
+}
+
+// maxComputerWait bounds the duration of the computer tool's wait action
+const maxComputerWait = 60 * time.Second
+
+// computerKeys maps xdotool key names, which computer-use agents send, to the
+// key names of the automation package
+var computerKeys = map[string]string{
+	"return":    "enter",
+	"kp_enter":  "enter",
+	"prior":     "pageup",
+	"next":      "pagedown",
+	"page_up":   "pageup",
+	"page_down": "pagedown",
+	"super":     "cmd",
+	"meta":      "cmd",
+	"win":       "cmd",
+	"control":   "ctrl",
+	"minus":     "-",
+	"plus":      "+",
+	"equal":     "=",
+	"comma":     ",",
+	"period":    ".",
+	"slash":     "/",
+}
+
+// computerResult is the structured result of the computer tool
+type computerResult struct {
+	Action     string `json:"action" jsonschema:"Action performed"`
+	X          *int   `json:"x,omitempty" jsonschema:"X coordinate of the cursor after the action"`
+	Y          *int   `json:"y,omitempty" jsonschema:"Y coordinate of the cursor after the action"`
+	Width      int    `json:"width" jsonschema:"Width of the screenshot and of the coordinate space"`
+	Height     int    `json:"height" jsonschema:"Height of the screenshot and of the coordinate space"`
+	DurationMs int64  `json:"durationMs" jsonschema:"Time the tool took in milliseconds"`
+}
+
+// flagSet reports whether a command line flag was given
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// computerCoords returns the screenshot coordinate space of the computer
+// profile: the screen downscaled to fit into a size of the form WxH, keeping
+// its aspect ratio. The screen is never upscaled.
+func computerCoords(maxSize string) (automation.CoordSystem, error) {
+	var maxW, maxH int
+	if _, err := fmt.Sscanf(maxSize, "%dx%d", &maxW, &maxH); err != nil || maxW <= 0 || maxH <= 0 {
+		return automation.CoordSystem{}, fmt.Errorf("invalid size %q: expected WIDTHxHEIGHT", maxSize)
+	}
+
+	cs := automation.NewCoordSystem(automation.CoordScreenshot)
+	w, h := cs.PhysicalSize()
+	if w <= 0 || h <= 0 {
+		return automation.CoordSystem{}, fmt.Errorf("screen size is unknown")
+	}
+	scale := math.Min(1, math.Min(float64(maxW)/float64(w), float64(maxH)/float64(h)))
+	cs.ScreenshotWidth = int(math.Round(float64(w) * scale))
+	cs.ScreenshotHeight = int(math.Round(float64(h) * scale))
+	return cs, nil
+}
+
+// addComputerTool registers the computer tool, which bundles the input and
+// screenshot tools behind a single action argument as computer-use agents expect
+func addComputerTool(s *server.MCPServer, coordsDoc string) {
+	w, h := coords.Size()
+	tool := mcp.NewTool("computer",
+		mcp.WithDescription(fmt.Sprintf("Use the mouse and keyboard to interact with the computer and take screenshots. "+
+			"The screen is %dx%d pixels; take a screenshot to see it before clicking. ", w, h)+coordsDoc),
+		mcp.WithOutputSchema[computerResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("action",
+			mcp.Required(),
+			mcp.Description("Action to perform"),
+			mcp.Enum(computerActions...),
+		),
+		mcp.WithArray("coordinate",
+			mcp.Description("[x, y] position for clicks, mouse_move, the end of left_click_drag and, optionally, scroll"),
+			mcp.Items(map[string]any{"type": "integer"}),
+		),
+		mcp.WithArray("start_coordinate",
+			mcp.Description("[x, y] start of left_click_drag (default: the cursor position)"),
+			mcp.Items(map[string]any{"type": "integer"}),
+		),
+		mcp.WithString("text",
+			mcp.Description("Text for type, or keys for key such as 'Return', 'ctrl+s' or 'ctrl+a Delete' (xdotool syntax)"),
+		),
+		mcp.WithString("scroll_direction",
+			mcp.Description("Direction for scroll"),
+			mcp.Enum("up", "down", "left", "right"),
+		),
+		mcp.WithNumber("scroll_amount",
+			mcp.Description("Number of wheel steps for scroll (default: 3)"),
+		),
+		mcp.WithNumber("duration",
+			mcp.Description("Seconds to wait for wait"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		action, err := request.RequireString("action")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		text, err := runComputerAction(ctx, action, request)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("%s failed: %v", action, err)), nil
+		}
+
+		result := computerResult{Action: action}
+		result.Width, result.Height = coords.Size()
+		if action == "screenshot" {
+			data, err := captureScreenPNG(ctx)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			result.DurationMs = since(start)
+			return &mcp.CallToolResult{
+				Content:           []mcp.Content{mcp.NewImageContent(data, "image/png")},
+				StructuredContent: result,
+			}, nil
+		}
+
+		if action != "type" && action != "key" && action != "wait" {
+			x, y := coords.FromLogical(automation.GetPosition())
+			result.X, result.Y = &x, &y
+		}
+		result.DurationMs = since(start)
+		return mcp.NewToolResultStructured(result, text), nil
+	})
+}
+
+// runComputerAction performs an action of the computer tool and returns its
+// result text; the handler attaches the screenshot itself
+func runComputerAction(ctx context.Context, action string, request mcp.CallToolRequest) (string, error) {
+	switch action {
+	case "screenshot":
+		return "", nil
+	case "cursor_position":
+		x, y := coords.FromLogical(automation.GetPosition())
+		return fmt.Sprintf("Mouse position: (%d, %d)", x, y), nil
+	case "left_click", "right_click", "double_click", "mouse_move":
+		x, y, err := computerCoordinate(request, "coordinate")
+		if err != nil {
+			return "", err
+		}
+		verb := "Moved mouse to"
+		switch action {
+		case "left_click":
+			verb, err = "Clicked at", automation.Click(ctx, x, y)
+		case "right_click":
+			verb, err = "Right clicked at", automation.RightClick(ctx, x, y)
+		case "double_click":
+			verb, err = "Double clicked at", automation.DoubleClick(ctx, x, y)
+		default:
+			err = automation.Move(ctx, x, y)
+		}
+		if err != nil {
+			return "", err
+		}
+		x, y = coords.FromLogical(x, y)
+		return fmt.Sprintf("%s (%d, %d)", verb, x, y), nil
+	case "left_click_drag":
+		x, y, err := computerCoordinate(request, "coordinate")
+		if err != nil {
+			return "", err
+		}
+		startX, startY := automation.GetPosition()
+		if request.GetArguments()["start_coordinate"] != nil {
+			if startX, startY, err = computerCoordinate(request, "start_coordinate"); err != nil {
+				return "", err
+			}
+		}
+		if err := drag(ctx, startX, startY, x, y); err != nil {
+			return "", err
+		}
+		startX, startY = coords.FromLogical(startX, startY)
+		x, y = coords.FromLogical(x, y)
+		return fmt.Sprintf("Dragged from (%d, %d) to (%d, %d)", startX, startY, x, y), nil
+	case "type":
+		text, err := request.RequireString("text")
+		if err != nil {
+			return "", err
+		}
+		if err := automation.TypeString(ctx, text); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Typed: %s", text), nil
+	case "key":
+		keys, err := request.RequireString("text")
+		if err != nil {
+			return "", err
+		}
+		for _, combo := range strings.Fields(keys) {
+			if err := automation.PressKeyCombo(ctx, computerKeyCombo(combo)...); err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("Pressed key: %s", keys), nil
+	case "scroll":
+		if request.GetArguments()["coordinate"] != nil {
+			x, y, err := computerCoordinate(request, "coordinate")
+			if err != nil {
+				return "", err
+			}
+			if err := automation.Move(ctx, x, y); err != nil {
+				return "", err
+			}
+		}
+		amount := request.GetInt("scroll_amount", 3)
+		var dx, dy int
+		switch request.GetString("scroll_direction", "down") {
+		case "up":
+			dy = -amount
+		case "down":
+			dy = amount
+		case "left":
+			dx = -amount
+		case "right":
+			dx = amount
+		default:
+			return "", fmt.Errorf("scroll_direction must be up, down, left or right")
+		}
+		if err := automation.Scroll(ctx, dx, dy); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Scrolled by (%d, %d)", dx, dy), nil
+	case "wait":
+		d := time.Duration(request.GetFloat("duration", 1) * float64(time.Second))
+		if d <= 0 || d > maxComputerWait {
+			return "", fmt.Errorf("duration must be between 0 and %g seconds", maxComputerWait.Seconds())
+		}
+		if err := sleep(ctx, d); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Waited %gs", d.Seconds()), nil
+	}
+	return "", fmt.Errorf("unknown action %q", action)
+}
+
+// computerCoordinate reads an [x, y] argument of the computer tool and returns
+// it in logical coordinates
+func computerCoordinate(request mcp.CallToolRequest, name string) (int, int, error) {
+	point, ok := request.GetArguments()[name].([]any)
+	if !ok || len(point) != 2 {
+		return 0, 0, fmt.Errorf("%s must be an [x, y] array", name)
+	}
+	x, okX := point[0].(float64)
+	y, okY := point[1].(float64)
+	if !okX || !okY {
+		return 0, 0, fmt.Errorf("%s must contain two numbers", name)
+	}
+	w, h := coords.Size()
+	if x < 0 || y < 0 || int(x) >= w || int(y) >= h {
+		return 0, 0, fmt.Errorf("%s (%d, %d) is outside the %dx%d screen", name, int(x), int(y), w, h)
+	}
+	lx, ly := coords.ToLogical(int(x), int(y))
+	return lx, ly, nil
+}
+
+// computerKeyCombo splits an xdotool key combination such as "ctrl+shift+t"
+// into key names, modifiers first
+func computerKeyCombo(combo string) []string {
+	keys := strings.Split(combo, "+")
+	for i, key := range keys {
+		name := strings.ToLower(key)
+		// Left and right modifiers are pressed as the generic modifier
+		name = strings.TrimSuffix(strings.TrimSuffix(name, "_l"), "_r")
+		if mapped, ok := computerKeys[name]; ok {
+			name = mapped
+		}
+		keys[i] = strings.ReplaceAll(name, "_", "")
+	}
+	return keys
+}
+
+// drag presses the left button at one logical position, moves to another and
+// releases it
+func drag(ctx context.Context, fromX, fromY, toX, toY int) error {
+	if err := automation.Move(ctx, fromX, fromY); err != nil {
+		return err
+	}
+	if err := automation.MouseDown(ctx, "left"); err != nil {
+		return err
+	}
+	err := automation.MoveAlongPath(ctx, toX, toY, automation.TrajectoryOptions{
+		Path:     automation.PathLinear,
+		Easing:   automation.EaseInOut,
+		Duration: 0.5,
+	})
+	if upErr := automation.MouseUp(ctx, "left"); err == nil {
+		err = upErr
+	}
+	return err
+}
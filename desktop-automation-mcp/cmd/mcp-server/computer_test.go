@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dmahlow/desktop-automation-mcp/internal/automation"
+)
+
+// fakeBackend reports a fixed screen; other methods are not implemented
+type fakeBackend struct {
+	automation.Backend
+	width, height int
+	scale         float64
+}
+
+func (f *fakeBackend) ScreenSize(ctx context.Context) (int, int, error) {
+	return f.width, f.height, nil
+}
+
+func (f *fakeBackend) ScaleFactor(ctx context.Context) (float64, error) {
+	return f.scale, nil
+}
+
+func TestComputerKeyCombo(t *testing.T) {
+	tests := []struct {
+		combo string
+		want  []string
+	}{
+		{"a", []string{"a"}},
+		{"Return", []string{"enter"}},
+		{"KP_Enter", []string{"enter"}},
+		{"ctrl+shift+t", []string{"ctrl", "shift", "t"}},
+		{"Control_L+c", []string{"ctrl", "c"}},
+		{"Shift_R+Tab", []string{"shift", "tab"}},
+		{"super+Page_Down", []string{"cmd", "pagedown"}},
+		{"alt+Prior", []string{"alt", "pageup"}},
+		{"ctrl+minus", []string{"ctrl", "-"}},
+		{"F5", []string{"f5"}},
+		{"BackSpace", []string{"backspace"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.combo, func(t *testing.T) {
+			if got := computerKeyCombo(tt.combo); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("computerKeyCombo(%q) = %q, want %q", tt.combo, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputerCoords(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		scale         float64
+		maxSize       string
+		wantW, wantH  int
+		wantErr       bool
+	}{
+		{"fits", 1280, 800, 1, "1280x800", 1280, 800, false},
+		{"not upscaled", 1024, 768, 1, "1920x1080", 1024, 768, false},
+		{"downscaled by width", 1920, 1080, 1, "1280x800", 1280, 720, false},
+		{"downscaled by height", 1600, 1200, 1, "1280x800", 1067, 800, false},
+		{"scaled display", 1440, 900, 2, "1280x800", 1280, 800, false},
+		{"invalid size", 1920, 1080, 1, "1280", 0, 0, true},
+		{"zero size", 1920, 1080, 1, "0x800", 0, 0, true},
+		{"unknown screen", 0, 0, 1, "1280x800", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := automation.CurrentBackend()
+			automation.SetBackend(&fakeBackend{width: tt.width, height: tt.height, scale: tt.scale})
+			defer automation.SetBackend(old)
+
+			cs, err := computerCoords(tt.maxSize)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("computerCoords(%q) error = %v, wantErr %v", tt.maxSize, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if w, h := cs.Size(); w != tt.wantW || h != tt.wantH {
+				t.Errorf("computerCoords(%q) size = %dx%d, want %dx%d", tt.maxSize, w, h, tt.wantW, tt.wantH)
+			}
+			if cs.Space != automation.CoordScreenshot {
+				t.Errorf("computerCoords(%q) space = %v, want screenshot", tt.maxSize, cs.Space)
+			}
+		})
+	}
+}
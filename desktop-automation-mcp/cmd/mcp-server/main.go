@@ -2,8 +2,17 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -11,50 +20,221 @@ import (
 	"github.com/dmahlow/desktop-automation-mcp/internal/automation"
 )
 
+// coords is the coordinate system every tool's coordinates are expressed in
+var coords automation.CoordSystem
+
+// describeCoords documents the coordinate space in tool descriptions
+func describeCoords(cs automation.CoordSystem) string {
+	switch cs.Space {
+	case automation.CoordPhysical:
+		return "Coordinates are physical pixels from the top-left corner of the screen, matching full-resolution screenshots."
+	case automation.CoordScreenshot:
+		w, h := cs.ScreenshotSize()
+		return fmt.Sprintf("Coordinates are pixels of a %dx%d screenshot of the screen, from its top-left corner.", w, h)
+	}
+	return "Coordinates are logical screen units from the top-left corner of the screen, as reported by get_mouse_position."
+}
+
+// resolveTarget reads the position of a tool call from either the "target"
+// expression or the "x" and "y" arguments and returns logical screen coordinates
+func resolveTarget(request mcp.CallToolRequest) (int, int, error) {
+	frame, err := automation.ParseFrame(request.GetString("relative_to", "screen"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var target automation.Target
+	if expr := request.GetString("target", ""); expr != "" {
+		target, err = automation.ParseTarget(expr)
+	} else {
+		args := request.GetArguments()
+		if args["x"] == nil || args["y"] == nil {
+			return 0, 0, fmt.Errorf("either target or both x and y are required")
+		}
+		x, errX := axisArg("x", args["x"])
+		y, errY := axisArg("y", args["y"])
+		if errX != nil {
+			return 0, 0, errX
+		}
+		if errY != nil {
+			return 0, 0, errY
+		}
+		target, err = automation.ParseTarget(x, y)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return target.Resolve(coords, frame)
+}
+
+// axisArg converts a coordinate argument to its expression form; numbers are
+// always absolute coordinates, strings may use the full target syntax
+func axisArg(name string, v any) (string, error) {
+	switch v := v.(type) {
+	case float64:
+		if v < 0 {
+			// Negative numbers are invalid coordinates, not offsets
+			return "", fmt.Errorf("%s coordinate cannot be negative: %d", name, int(v))
+		}
+		return strconv.Itoa(int(v)), nil
+	case string:
+		return v, nil
+	}
+	return "", fmt.Errorf("%s coordinate must be a number or expression", name)
+}
+
+// a11yQuery reads the element selection arguments of the a11y tools
+func a11yQuery(request mcp.CallToolRequest) automation.A11yQuery {
+	q := automation.A11yQuery{
+		App:      request.GetString("app", ""),
+		Role:     request.GetString("role", ""),
+		Name:     request.GetString("name", ""),
+		All:      request.GetBool("include_hidden", false),
+		MaxDepth: int(request.GetFloat("max_depth", 0)),
+	}
+	if states, ok := request.GetArguments()["states"].([]any); ok {
+		for _, s := range states {
+			if s, ok := s.(string); ok {
+				q.States = append(q.States, s)
+			}
+		}
+	}
+	return q
+}
+
+// selectA11yElement finds the element selected by the arguments of an a11y
+// tool acting on a single element
+func selectA11yElement(ctx context.Context, a11y *automation.A11y, request mcp.CallToolRequest) (*automation.A11yElement, error) {
+	q := a11yQuery(request)
+	if q.Name == "" && q.Role == "" {
+		return nil, fmt.Errorf("name or role is required")
+	}
+
+	found, err := a11y.Find(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("finding accessible elements failed: %w", err)
+	}
+	index := int(request.GetFloat("index", 0))
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no accessible element matches")
+	}
+	if index < 0 || index >= len(found) {
+		return nil, fmt.Errorf("index %d out of range: %d elements match", index, len(found))
+	}
+	return found[index], nil
+}
+
+// a11yResult returns accessibility elements as a JSON tool result with
+// bounds in the tools' coordinate space
+func a11yResult(elements []*automation.A11yElement) (*mcp.CallToolResult, error) {
+	if elements == nil {
+		elements = []*automation.A11yElement{}
+	}
+	automation.ConvertA11yBounds(elements, coords)
+	data, err := json.MarshalIndent(elements, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(elementsResult{Elements: elements, Count: len(elements)}, string(data)), nil
+}
+
 func main() {
+	coordsFlag := flag.String("coords", "logical", "Coordinate space for tool coordinates: logical, physical or screenshot[:WxH]")
+	backendFlag := flag.String("backend", "", "Automation backend: robotgo, x11[:DISPLAY], uinput[:WxH] or vnc://[:password@]host[:port] (default: local desktop)")
+	profileFlag := flag.String("profile", "default", "Tool profile: default for the individual tools, computer for a single computer-use tool or all for both")
+	computerSizeFlag := flag.String("computer-size", "1280x800", "Largest screenshot size of the computer profile; screenshots are downscaled to fit and coordinates scaled back")
+	flag.Parse()
+
+	switch *profileFlag {
+	case "default", "computer", "all":
+	default:
+		log.Fatalf("Invalid -profile value %q: must be default, computer or all", *profileFlag)
+	}
+
+	// Select the backend first, the coordinate system depends on its screen.
+	// Backends holding a connection or devices are closed on shutdown.
+	var backendCloser io.Closer
+	if *backendFlag != "" {
+		b, err := automation.OpenBackend(context.Background(), *backendFlag)
+		if err != nil {
+			log.Fatalf("Invalid -backend value: %v", err)
+		}
+		automation.SetBackend(b)
+		backendCloser, _ = b.(io.Closer)
+	}
+
+	var err error
+	coords, err = automation.ParseCoordSpace(*coordsFlag)
+	if err != nil {
+		log.Fatalf("Invalid -coords value: %v", err)
+	}
+	// Computer-use agents see downscaled screenshots, so unless told
+	// otherwise all coordinates are pixels of those
+	if *profileFlag != "default" && !flagSet("coords") {
+		coords, err = computerCoords(*computerSizeFlag)
+		if err != nil {
+			log.Fatalf("Invalid -computer-size value: %v", err)
+		}
+	}
+	coordsDoc := describeCoords(coords)
+	targetDoc := "Target expression used instead of x and y: 'X,Y' where each coordinate is absolute (100), " +
+		"relative to the cursor (+50, -20) or a percentage of the frame (50%), or an anchor such as " +
+		"'@center', '@top-left', '@bottom-right' or '@cursor'"
+
 	// Create a new MCP server
 	s := server.NewMCPServer(
 		"Desktop Automation Server",
 		"1.0.0",
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(true, false),
 		server.WithRecovery(),
 	)
+	addResources(s, coordsDoc)
 
 	// Add mouse click tool
 	clickTool := mcp.NewTool("click",
-		mcp.WithDescription("Click at specified coordinates"),
+		mcp.WithDescription("Click at specified coordinates. "+coordsDoc),
+		mcp.WithOutputSchema[pointerResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithNumber("x",
-			mcp.Required(),
-			mcp.Description("X coordinate for click"),
+			mcp.Description("X coordinate for click (required unless target is given)"),
 		),
 		mcp.WithNumber("y",
-			mcp.Required(),
-			mcp.Description("Y coordinate for click"),
+			mcp.Description("Y coordinate for click (required unless target is given)"),
+		),
+		mcp.WithString("target",
+			mcp.Description(targetDoc),
+		),
+		mcp.WithString("relative_to",
+			mcp.Description("Frame for percentages and anchors in target: 'screen' (default) or 'window' for the active window"),
+			mcp.Enum("screen", "window"),
 		),
 	)
 
 	s.AddTool(clickTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		x, err := request.RequireFloat("x")
-		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
-		}
-
-		y, err := request.RequireFloat("y")
+		start := time.Now()
+		x, y, err := resolveTarget(request)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = automation.Click(int(x), int(y))
+		err = automation.Click(ctx, x, y)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Click failed: %v", err)), nil
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Clicked at (%d, %d)", int(x), int(y))), nil
+		result := newPointerResult(x, y, start)
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("Clicked at (%d, %d)", result.X, result.Y)), nil
 	})
 
 	// Add type text tool
 	typeTextTool := mcp.NewTool("type_text",
 		mcp.WithDescription("Type text at current cursor position"),
+		mcp.WithOutputSchema[typeResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithString("text",
 			mcp.Required(),
 			mcp.Description("Text to type"),
@@ -62,9 +242,20 @@ func main() {
 		mcp.WithNumber("delay",
 			mcp.Description("Delay between characters in milliseconds (optional)"),
 		),
+		mcp.WithString("profile",
+			mcp.Description("Human-like typing cadence profile (optional, default 'natural' when wpm is given)"),
+			mcp.Enum(automation.TypingProfiles()...),
+		),
+		mcp.WithNumber("wpm",
+			mcp.Description("Target typing speed in words per minute, overrides the profile's speed (optional)"),
+		),
+		mcp.WithNumber("seed",
+			mcp.Description("Seed for reproducible typing cadence (optional)"),
+		),
 	)
 
 	s.AddTool(typeTextTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
 		text, err := request.RequireString("text")
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -72,24 +263,43 @@ func main() {
 
 		args := request.GetArguments()
 		delay, hasDelay := args["delay"]
+		var warnings []string
 
-		if hasDelay && delay != nil {
+		if args["profile"] != nil || args["wpm"] != nil {
+			if hasDelay && delay != nil {
+				warnings = append(warnings, "delay is ignored when profile or wpm is given")
+			}
+			var profile automation.TypingProfile
+			profile, err = automation.TypingProfileByName(request.GetString("profile", "natural"))
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if wpm := request.GetFloat("wpm", 0); wpm > 0 {
+				profile.WPM = wpm
+			}
+			profile.Seed = int64(request.GetFloat("seed", 0))
+			err = automation.TypeWithProfile(ctx, text, profile)
+		} else if hasDelay && delay != nil {
 			delayVal := delay.(float64)
-			err = automation.TypeStringWithDelay(text, int(delayVal))
+			err = automation.TypeStringWithDelay(ctx, text, int(delayVal))
 		} else {
-			err = automation.TypeString(text)
+			err = automation.TypeString(ctx, text)
 		}
 
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Type text failed: %v", err)), nil
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Typed: %s", text)), nil
+		result := typeResult{Characters: len([]rune(text)), DurationMs: since(start), Warnings: warnings}
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("Typed: %s", text)), nil
 	})
 
 	// Add press key tool
 	pressKeyTool := mcp.NewTool("press_key",
 		mcp.WithDescription("Press a key or key combination"),
+		mcp.WithOutputSchema[keyResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithString("key",
 			mcp.Required(),
 			mcp.Description("Key to press (e.g., 'enter', 'space', 'ctrl')"),
@@ -100,6 +310,7 @@ func main() {
 	)
 
 	s.AddTool(pressKeyTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
 		key, err := request.RequireString("key")
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -115,33 +326,43 @@ func main() {
 				modifiers[i] = mod.(string)
 			}
 			keys := append(modifiers, key)
-			err = automation.PressKeyCombo(keys...)
+			err = automation.PressKeyCombo(ctx, keys...)
 
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("Press key failed: %v", err)), nil
 			}
 
-			return mcp.NewToolResultText(fmt.Sprintf("Pressed key combination: %v + %s", modifiers, key)), nil
+			result := keyResult{Keys: keys, DurationMs: since(start)}
+			return mcp.NewToolResultStructured(result, fmt.Sprintf("Pressed key combination: %v + %s", modifiers, key)), nil
 		} else {
-			err = automation.PressKey(key)
+			err = automation.PressKey(ctx, key)
 
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("Press key failed: %v", err)), nil
 			}
 		}
-		return mcp.NewToolResultText(fmt.Sprintf("Pressed key: %s", key)), nil
+		result := keyResult{Keys: []string{key}, DurationMs: since(start)}
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("Pressed key: %s", key)), nil
 	})
 
 	// Add mouse move tool
 	moveMouseTool := mcp.NewTool("move_mouse",
-		mcp.WithDescription("Move mouse to specified coordinates"),
+		mcp.WithDescription("Move mouse to specified coordinates. "+coordsDoc),
+		mcp.WithOutputSchema[pointerResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithNumber("x",
-			mcp.Required(),
-			mcp.Description("X coordinate to move to"),
+			mcp.Description("X coordinate to move to (required unless target is given)"),
 		),
 		mcp.WithNumber("y",
-			mcp.Required(),
-			mcp.Description("Y coordinate to move to"),
+			mcp.Description("Y coordinate to move to (required unless target is given)"),
+		),
+		mcp.WithString("target",
+			mcp.Description(targetDoc),
+		),
+		mcp.WithString("relative_to",
+			mcp.Description("Frame for percentages and anchors in target: 'screen' (default) or 'window' for the active window"),
+			mcp.Enum("screen", "window"),
 		),
 		mcp.WithBoolean("smooth",
 			mcp.Description("Use smooth movement (default: false)"),
@@ -149,15 +370,28 @@ func main() {
 		mcp.WithNumber("duration",
 			mcp.Description("Duration for smooth movement in seconds (default: 1.0)"),
 		),
+		mcp.WithString("path",
+			mcp.Description("Trajectory shape for smooth movement: 'linear' (default), 'bezier' for a randomized curve or 'wind' for a wandering human-like path; implies smooth"),
+			mcp.Enum("linear", "bezier", "wind"),
+		),
+		mcp.WithString("easing",
+			mcp.Description("Velocity profile for smooth movement: 'in-out' (default), 'out' or 'none'"),
+			mcp.Enum("in-out", "out", "none"),
+		),
+		mcp.WithBoolean("overshoot",
+			mcp.Description("Overshoot the target slightly and correct back (default: false)"),
+		),
+		mcp.WithNumber("jitter",
+			mcp.Description("Standard deviation in pixels of random noise added to each step (default: 0)"),
+		),
+		mcp.WithNumber("seed",
+			mcp.Description("Seed for a reproducible randomized trajectory (default: random)"),
+		),
 	)
 
 	s.AddTool(moveMouseTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		x, err := request.RequireFloat("x")
-		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
-		}
-
-		y, err := request.RequireFloat("y")
+		start := time.Now()
+		x, y, err := resolveTarget(request)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -176,96 +410,475 @@ func main() {
 			duration = durationRaw.(float64)
 		}
 
+		path, err := automation.ParsePathKind(request.GetString("path", "linear"))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		easing, err := automation.ParseEasing(request.GetString("easing", "in-out"))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if args["path"] != nil {
+			smooth = true
+		}
+
 		if smooth {
-			err = automation.SmoothMove(int(x), int(y), duration)
+			err = automation.MoveAlongPath(ctx, x, y, automation.TrajectoryOptions{
+				Path:      path,
+				Easing:    easing,
+				Duration:  duration,
+				Overshoot: request.GetBool("overshoot", false),
+				Jitter:    request.GetFloat("jitter", 0),
+				Seed:      int64(request.GetFloat("seed", 0)),
+			})
 		} else {
-			err = automation.Move(int(x), int(y))
+			err = automation.Move(ctx, x, y)
 		}
 
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Move mouse failed: %v", err)), nil
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Moved mouse to (%d, %d)", int(x), int(y))), nil
+		result := newPointerResult(x, y, start)
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("Moved mouse to (%d, %d)", result.X, result.Y)), nil
 	})
 
 	// Add get mouse position tool
 	getMousePosTool := mcp.NewTool("get_mouse_position",
-		mcp.WithDescription("Get current mouse cursor position"),
+		mcp.WithDescription("Get current mouse cursor position. "+coordsDoc),
+		mcp.WithOutputSchema[pointerResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
 	)
 
 	s.AddTool(getMousePosTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		x, y := automation.GetPosition()
-		return mcp.NewToolResultText(fmt.Sprintf("Mouse position: (%d, %d)", x, y)), nil
+		result := newPointerResult(x, y, time.Now())
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("Mouse position: (%d, %d)", result.X, result.Y)), nil
 	})
 
-	// Add right click tool
-	rightClickTool := mcp.NewTool("right_click",
-		mcp.WithDescription("Right click at specified coordinates"),
+	// Add get pixel color tool
+	getPixelColorTool := mcp.NewTool("get_pixel_color",
+		mcp.WithDescription("Get the color of the screen at specified coordinates as #rrggbb and RGB components. "+coordsDoc),
+		mcp.WithOutputSchema[colorResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
 		mcp.WithNumber("x",
-			mcp.Required(),
-			mcp.Description("X coordinate for right click"),
+			mcp.Description("X coordinate of the pixel (required unless target is given)"),
 		),
 		mcp.WithNumber("y",
-			mcp.Required(),
-			mcp.Description("Y coordinate for right click"),
+			mcp.Description("Y coordinate of the pixel (required unless target is given)"),
+		),
+		mcp.WithString("target",
+			mcp.Description(targetDoc),
+		),
+		mcp.WithString("relative_to",
+			mcp.Description("Frame for percentages and anchors in target: 'screen' (default) or 'window' for the active window"),
+			mcp.Enum("screen", "window"),
 		),
 	)
 
-	s.AddTool(rightClickTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		x, err := request.RequireFloat("x")
+	s.AddTool(getPixelColorTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		x, y, err := resolveTarget(request)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		y, err := request.RequireFloat("y")
+		c, err := automation.PixelAt(ctx, x, y)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Get pixel color failed: %v", err)), nil
+		}
+
+		result := colorResult{Hex: automation.HexColor(c), R: c.R, G: c.G, B: c.B}
+		result.X, result.Y = coords.FromLogical(x, y)
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("Color at (%d, %d): %s rgb(%d, %d, %d)",
+			result.X, result.Y, result.Hex, c.R, c.G, c.B)), nil
+	})
+
+	// Add right click tool
+	rightClickTool := mcp.NewTool("right_click",
+		mcp.WithDescription("Right click at specified coordinates. "+coordsDoc),
+		mcp.WithOutputSchema[pointerResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithNumber("x",
+			mcp.Description("X coordinate for right click (required unless target is given)"),
+		),
+		mcp.WithNumber("y",
+			mcp.Description("Y coordinate for right click (required unless target is given)"),
+		),
+		mcp.WithString("target",
+			mcp.Description(targetDoc),
+		),
+		mcp.WithString("relative_to",
+			mcp.Description("Frame for percentages and anchors in target: 'screen' (default) or 'window' for the active window"),
+			mcp.Enum("screen", "window"),
+		),
+	)
+
+	s.AddTool(rightClickTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		x, y, err := resolveTarget(request)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		err = automation.RightClick(int(x), int(y))
+		err = automation.RightClick(ctx, x, y)
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Right click failed: %v", err)), nil
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("Right clicked at (%d, %d)", int(x), int(y))), nil
+		result := newPointerResult(x, y, start)
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("Right clicked at (%d, %d)", result.X, result.Y)), nil
 	})
 
 	// Add double click tool
 	doubleClickTool := mcp.NewTool("double_click",
-		mcp.WithDescription("Double click at specified coordinates"),
+		mcp.WithDescription("Double click at specified coordinates. "+coordsDoc),
+		mcp.WithOutputSchema[pointerResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
 		mcp.WithNumber("x",
-			mcp.Required(),
-			mcp.Description("X coordinate for double click"),
+			mcp.Description("X coordinate for double click (required unless target is given)"),
 		),
 		mcp.WithNumber("y",
-			mcp.Required(),
-			mcp.Description("Y coordinate for double click"),
+			mcp.Description("Y coordinate for double click (required unless target is given)"),
+		),
+		mcp.WithString("target",
+			mcp.Description(targetDoc),
+		),
+		mcp.WithString("relative_to",
+			mcp.Description("Frame for percentages and anchors in target: 'screen' (default) or 'window' for the active window"),
+			mcp.Enum("screen", "window"),
 		),
 	)
 
 	s.AddTool(doubleClickTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		x, err := request.RequireFloat("x")
+		start := time.Now()
+		x, y, err := resolveTarget(request)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		y, err := request.RequireFloat("y")
+		err = automation.DoubleClick(ctx, x, y)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Double click failed: %v", err)), nil
+		}
+
+		result := newPointerResult(x, y, start)
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("Double clicked at (%d, %d)", result.X, result.Y)), nil
+	})
+
+	// Add accessibility tools
+	a11yAppDoc := "Only search applications with this name; case-insensitive, * and ? are wildcards"
+	a11yDepthDoc := "Maximum depth below the applications (default: no limit)"
+	a11yHiddenDoc := "Include elements that are not showing on the screen (default: false)"
+	a11yRoleDoc := "Role of the element such as 'push button', 'text' or 'menu item'; a single word such as 'button' matches the last word of the role"
+	a11yNameDoc := "Accessible name of the element; case-insensitive, * and ? are wildcards"
+	a11yStatesDoc := "States the element must have, e.g. ['focused'] or ['enabled', 'checked']"
+
+	a11yTreeTool := mcp.NewTool("a11y_tree",
+		mcp.WithDescription("Get the accessibility tree (AT-SPI) of the desktop's applications as JSON with roles, names, states, actions, text and bounds. "+
+			"Prefer acting on elements by name over pixel coordinates. "+coordsDoc),
+		a11yOutputSchema[elementsResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("app", mcp.Description(a11yAppDoc)),
+		mcp.WithNumber("max_depth", mcp.Description(a11yDepthDoc)),
+		mcp.WithBoolean("include_hidden", mcp.Description(a11yHiddenDoc)),
+	)
+
+	s.AddTool(a11yTreeTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		a11y, err := automation.OpenA11y(ctx)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
+		defer a11y.Close()
 
-		err = automation.DoubleClick(int(x), int(y))
+		apps, err := a11y.Tree(ctx, a11yQuery(request))
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Double click failed: %v", err)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("Reading accessibility tree failed: %v", err)), nil
+		}
+		return a11yResult(apps)
+	})
+
+	a11yFindTool := mcp.NewTool("a11y_find",
+		mcp.WithDescription("Find elements in the accessibility tree (AT-SPI) by application, role, name and states. "+
+			"Returns the matches with their states, actions, text and bounds. "+coordsDoc),
+		a11yOutputSchema[elementsResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithString("app", mcp.Description(a11yAppDoc)),
+		mcp.WithString("role", mcp.Description(a11yRoleDoc)),
+		mcp.WithString("name", mcp.Description(a11yNameDoc)),
+		mcp.WithArray("states", mcp.Description(a11yStatesDoc), mcp.WithStringItems()),
+		mcp.WithNumber("max_depth", mcp.Description(a11yDepthDoc)),
+		mcp.WithBoolean("include_hidden", mcp.Description(a11yHiddenDoc)),
+	)
+
+	s.AddTool(a11yFindTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		a11y, err := automation.OpenA11y(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
+		defer a11y.Close()
 
-		return mcp.NewToolResultText(fmt.Sprintf("Double clicked at (%d, %d)", int(x), int(y))), nil
+		found, err := a11y.Find(ctx, a11yQuery(request))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Finding accessible elements failed: %v", err)), nil
+		}
+		return a11yResult(found)
 	})
 
-	// Start the stdio server
+	a11yClickTool := mcp.NewTool("a11y_click",
+		mcp.WithDescription("Click the centre of an element of the accessibility tree (AT-SPI) selected by application, role, name and states. "+
+			"At least name or role is required."),
+		a11yOutputSchema[elementResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("app", mcp.Description(a11yAppDoc)),
+		mcp.WithString("role", mcp.Description(a11yRoleDoc)),
+		mcp.WithString("name", mcp.Description(a11yNameDoc)),
+		mcp.WithArray("states", mcp.Description(a11yStatesDoc), mcp.WithStringItems()),
+		mcp.WithNumber("index", mcp.Description("Which of several matching elements to click, from 0 (default: 0)")),
+		mcp.WithBoolean("include_hidden", mcp.Description(a11yHiddenDoc)),
+	)
+
+	s.AddTool(a11yClickTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		a11y, err := automation.OpenA11y(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		defer a11y.Close()
+
+		e, err := selectA11yElement(ctx, a11y, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		x, y, ok := e.Center()
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("%s %q has no position on the screen", e.Role, e.Name)), nil
+		}
+		if err := automation.Click(ctx, x, y); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Click failed: %v", err)), nil
+		}
+
+		coordsX, coordsY := coords.FromLogical(x, y)
+		result := newElementResult(e, start)
+		result.X, result.Y = &coordsX, &coordsY
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("Clicked %s %q of %s at (%d, %d)", e.Role, e.Name, e.App, coordsX, coordsY)), nil
+	})
+
+	a11yInvokeTool := mcp.NewTool("a11y_invoke",
+		mcp.WithDescription("Perform an action of an element of the accessibility tree (AT-SPI), such as pressing a button, without moving the mouse. "+
+			"Works even when the window is covered or off-screen. At least name or role is required."),
+		a11yOutputSchema[elementResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("app", mcp.Description(a11yAppDoc)),
+		mcp.WithString("role", mcp.Description(a11yRoleDoc)),
+		mcp.WithString("name", mcp.Description(a11yNameDoc)),
+		mcp.WithArray("states", mcp.Description(a11yStatesDoc), mcp.WithStringItems()),
+		mcp.WithNumber("index", mcp.Description("Which of several matching elements to use, from 0 (default: 0)")),
+		mcp.WithBoolean("include_hidden", mcp.Description(a11yHiddenDoc)),
+		mcp.WithString("action", mcp.Description("Name of the action as listed by a11y_find (default: click, press, activate or the element's first action)")),
+	)
+
+	s.AddTool(a11yInvokeTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		a11y, err := automation.OpenA11y(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		defer a11y.Close()
+
+		e, err := selectA11yElement(ctx, a11y, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		done, err := a11y.DoAction(ctx, e, request.GetString("action", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		result := newElementResult(e, start)
+		result.Action = done
+		return mcp.NewToolResultStructured(result, fmt.Sprintf("Performed %q on %s %q of %s", done, e.Role, e.Name, e.App)), nil
+	})
+
+	a11ySetTextTool := mcp.NewTool("a11y_set_text",
+		mcp.WithDescription("Replace the text of an editable element of the accessibility tree (AT-SPI) without typing. "+
+			"Works even when the window is covered or off-screen. At least name or role is required."),
+		a11yOutputSchema[elementResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithString("text", mcp.Required(), mcp.Description("New text of the element")),
+		mcp.WithString("app", mcp.Description(a11yAppDoc)),
+		mcp.WithString("role", mcp.Description(a11yRoleDoc)),
+		mcp.WithString("name", mcp.Description(a11yNameDoc)),
+		mcp.WithArray("states", mcp.Description(a11yStatesDoc), mcp.WithStringItems()),
+		mcp.WithNumber("index", mcp.Description("Which of several matching elements to use, from 0 (default: 0)")),
+		mcp.WithBoolean("include_hidden", mcp.Description(a11yHiddenDoc)),
+	)
+
+	s.AddTool(a11ySetTextTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		text, err := request.RequireString("text")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		a11y, err := automation.OpenA11y(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		defer a11y.Close()
+
+		e, err := selectA11yElement(ctx, a11y, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if err := a11y.SetText(ctx, e, text); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultStructured(newElementResult(e, start), fmt.Sprintf("Set text of %s %q of %s", e.Role, e.Name, e.App)), nil
+	})
+
+	a11ySetValueTool := mcp.NewTool("a11y_set_value",
+		mcp.WithDescription("Set the current value of a slider, spin button or similar element of the accessibility tree (AT-SPI) without dragging or typing. "+
+			"At least name or role is required."),
+		a11yOutputSchema[elementResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithNumber("value", mcp.Required(), mcp.Description("New value, between the element's minimum and maximum")),
+		mcp.WithString("app", mcp.Description(a11yAppDoc)),
+		mcp.WithString("role", mcp.Description(a11yRoleDoc)),
+		mcp.WithString("name", mcp.Description(a11yNameDoc)),
+		mcp.WithArray("states", mcp.Description(a11yStatesDoc), mcp.WithStringItems()),
+		mcp.WithNumber("index", mcp.Description("Which of several matching elements to use, from 0 (default: 0)")),
+		mcp.WithBoolean("include_hidden", mcp.Description(a11yHiddenDoc)),
+	)
+
+	s.AddTool(a11ySetValueTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		value, err := request.RequireFloat("value")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		a11y, err := automation.OpenA11y(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		defer a11y.Close()
+
+		e, err := selectA11yElement(ctx, a11y, request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if err := a11y.SetValue(ctx, e, value); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultStructured(newElementResult(e, start), fmt.Sprintf("Set value of %s %q of %s to %g", e.Role, e.Name, e.App, value)), nil
+	})
+
+	// Add screenshot, mark and zoom tools
+	addMarkTools(s, coordsDoc)
+	addZoomTool(s, coordsDoc)
+
+	// Add batch action tool
+	executeActionsTool := mcp.NewTool("execute_actions",
+		mcp.WithDescription("Run a sequence of actions in one call and return the result of each step. "+
+			"Each action is an object whose 'action' names one of the tools "+strings.Join(batchActions, ", ")+
+			", 'scroll' (dx, dy and optionally a position to scroll at) or 'wait' (ms), together with that tool's arguments. "+
+			"'delay_after' on an action overrides the pause in milliseconds after it. "+coordsDoc),
+		mcp.WithOutputSchema[batchResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithArray("actions",
+			mcp.Required(),
+			mcp.Description("Actions to run in order, e.g. [{\"action\": \"click\", \"x\": 100, \"y\": 200}, {\"action\": \"type_text\", \"text\": \"hello\"}, {\"action\": \"press_key\", \"key\": \"enter\"}]"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"action":      map[string]any{"type": "string", "enum": append(batchActions, "scroll", "wait")},
+					"delay_after": map[string]any{"type": "number"},
+				},
+				"required": []string{"action"},
+			}),
+		),
+		mcp.WithNumber("delay",
+			mcp.Description("Pause between actions in milliseconds (default: 0)"),
+		),
+		mcp.WithBoolean("stop_on_error",
+			mcp.Description("Skip the remaining actions after one fails (default: true)"),
+		),
+		mcp.WithBoolean("screenshot",
+			mcp.Description("Attach a screenshot taken after the last action (default: false)"),
+		),
+	)
+
+	s.AddTool(executeActionsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		actions, ok := request.GetArguments()["actions"].([]any)
+		if !ok {
+			return mcp.NewToolResultError("actions must be an array"), nil
+		}
+		delay := time.Duration(request.GetFloat("delay", 0)) * time.Millisecond
+
+		results, err := executeActions(ctx, s, actions, delay, request.GetBool("stop_on_error", true))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Execute actions failed after %d steps: %v", len(results), err)), nil
+		}
+
+		var screenshot string
+		if request.GetBool("screenshot", false) {
+			screenshot, err = captureScreenPNG(ctx)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		return actionsResult(results, len(actions), start, screenshot)
+	})
+
+	// The computer profile replaces the individual tools with the single tool
+	// computer-use agents expect
+	switch *profileFlag {
+	case "computer":
+		for name := range s.ListTools() {
+			s.DeleteTools(name)
+		}
+		addComputerTool(s, coordsDoc)
+	case "all":
+		addComputerTool(s, coordsDoc)
+	}
+
+	// Start the stdio server. Tool calls run one at a time so input from
+	// different requests never interleaves, while the reader stays free to
+	// handle notifications/cancelled, which cancels the running tool's ctx.
+	// Resource subscriptions are answered and watched next to the server,
+	// which writes through the same writer so messages never interleave.
 	log.Println("Starting Desktop Automation MCP Server...")
-	if err := server.ServeStdio(s); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	out := &syncWriter{w: os.Stdout}
+	subs := newSubscriptions(out)
+	go subs.watch(ctx, s)
+
+	stdio := server.NewStdioServer(s)
+	server.WithWorkerPoolSize(1)(stdio)
+	err = stdio.Listen(ctx, subs.filter(os.Stdin), out)
+	if backendCloser != nil {
+		if closeErr := backendCloser.Close(); closeErr != nil {
+			log.Printf("Failed to close backend: %v", closeErr)
+		}
+	}
+	if err != nil && ctx.Err() == nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
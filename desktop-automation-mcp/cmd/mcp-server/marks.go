@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/dmahlow/desktop-automation-mcp/internal/automation"
+)
+
+// lastMarks holds the marks of the latest screenshot in logical coordinates,
+// which click_mark refers to by number
+var lastMarks struct {
+	sync.Mutex
+	marks []automation.Mark
+}
+
+// screenshotResult is the structured result of the screenshot tool
+type screenshotResult struct {
+	Width      int               `json:"width" jsonschema:"Width of the screenshot and of the coordinate space"`
+	Height     int               `json:"height" jsonschema:"Height of the screenshot and of the coordinate space"`
+	Marks      []automation.Mark `json:"marks,omitempty" jsonschema:"Numbered marks drawn on the screenshot with their centres, for click_mark"`
+	DurationMs int64             `json:"durationMs" jsonschema:"Time the tool took in milliseconds"`
+}
+
+// addMarkTools registers the screenshot tool, which can overlay a grid and
+// numbered marks, and click_mark, which clicks a mark of the latest screenshot
+func addMarkTools(s *server.MCPServer, coordsDoc string) {
+	screenshotTool := mcp.NewTool("screenshot",
+		mcp.WithDescription("Take a screenshot of the whole screen. grid overlays lines labelled with their coordinates; "+
+			"marks numbers the buttons, fields and links on the screen so they can be clicked with click_mark. "+coordsDoc),
+		mcp.WithOutputSchema[screenshotResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("grid",
+			mcp.Description("Draw a labelled coordinate grid with lines every N units (default: no grid)"),
+		),
+		mcp.WithBoolean("marks",
+			mcp.Description("Draw numbered marks on UI elements and list their centres (default: false)"),
+		),
+		mcp.WithString("marks_source",
+			mcp.Description("Where marks come from: the accessibility tree with edge detection as fallback (auto, default), only the accessibility tree (a11y) or only edge detection (edges)"),
+			mcp.Enum("auto", "a11y", "edges"),
+		),
+	)
+
+	s.AddTool(screenshotTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		grid := request.GetInt("grid", 0)
+		if grid < 0 {
+			return mcp.NewToolResultError("grid must not be negative"), nil
+		}
+		source, err := automation.ParseMarkSource(request.GetString("marks_source", "auto"))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		captured, err := captureScreen(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		img := automation.ToRGBA(captured)
+
+		var marks []automation.Mark
+		if request.GetBool("marks", false) {
+			marks, err = automation.FindMarks(ctx, captured, source)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Find marks failed: %v", err)), nil
+			}
+			lastMarks.Lock()
+			lastMarks.marks = marks
+			lastMarks.Unlock()
+		}
+
+		result := screenshotResult{Marks: automation.ConvertMarks(marks, coords)}
+		result.Width, result.Height = coords.Size()
+		automation.DrawGrid(img, result.Width, result.Height, grid)
+		automation.DrawMarks(img, coords.LogicalWidth, coords.LogicalHeight, marks)
+
+		data, err := encodePNG(img)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		content := []mcp.Content{mcp.NewImageContent(data, "image/png")}
+		if len(result.Marks) > 0 {
+			var text strings.Builder
+			for _, m := range result.Marks {
+				fmt.Fprintf(&text, "%d: (%d, %d) %s\n", m.ID, m.X, m.Y, m.Label)
+			}
+			content = append(content, mcp.NewTextContent(text.String()))
+		}
+		result.DurationMs = since(start)
+		return &mcp.CallToolResult{Content: content, StructuredContent: result}, nil
+	})
+
+	clickMarkTool := mcp.NewTool("click_mark",
+		mcp.WithDescription("Click the centre of a numbered mark of the latest screenshot taken with marks. "+coordsDoc),
+		mcp.WithOutputSchema[pointerResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithNumber("mark",
+			mcp.Required(),
+			mcp.Description("Number of the mark to click"),
+		),
+	)
+
+	s.AddTool(clickMarkTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		id, err := request.RequireInt("mark")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		lastMarks.Lock()
+		marks := lastMarks.marks
+		lastMarks.Unlock()
+		if len(marks) == 0 {
+			return mcp.NewToolResultError("no marks: take a screenshot with marks first"), nil
+		}
+		if id < 1 || id > len(marks) {
+			return mcp.NewToolResultError(fmt.Sprintf("mark %d does not exist: the latest screenshot has marks 1 to %d", id, len(marks))), nil
+		}
+		m := marks[id-1]
+
+		if err := automation.Click(ctx, m.X, m.Y); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Click failed: %v", err)), nil
+		}
+
+		result := newPointerResult(m.X, m.Y, start)
+		text := fmt.Sprintf("Clicked mark %d at (%d, %d)", id, result.X, result.Y)
+		if m.Label != "" {
+			text = fmt.Sprintf("Clicked mark %d (%s) at (%d, %d)", id, m.Label, result.X, result.Y)
+		}
+		return mcp.NewToolResultStructured(result, text), nil
+	})
+}
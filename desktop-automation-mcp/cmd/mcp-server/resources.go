@@ -0,0 +1,409 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/dmahlow/desktop-automation-mcp/internal/automation"
+)
+
+// URIs of the resources describing the desktop
+const (
+	screenURI   = "screen://current"
+	displaysURI = "screen://displays"
+	cursorURI   = "cursor://position"
+	windowURI   = "window://active"
+)
+
+const (
+	// watchInterval is how often subscribed resources are checked for changes;
+	// the screen and displays are checked every few intervals
+	watchInterval = 250 * time.Millisecond
+	screenEvery   = 4
+	displaysEvery = 8
+
+	// screenSampleStep is the distance in pixels between the samples compared
+	// to detect screen changes
+	screenSampleStep = 8
+	// screenChangeThreshold is the fraction of samples that must change for
+	// the screen to count as changed
+	screenChangeThreshold = 0.01
+)
+
+// addResources registers the desktop resources with the server
+func addResources(s *server.MCPServer, coordsDoc string) {
+	s.AddResource(mcp.NewResource(screenURI, "Current screen",
+		mcp.WithResourceDescription("Screenshot of the whole screen as PNG in physical pixels, or resized to the screenshot coordinate space when tools use it"),
+		mcp.WithMIMEType("image/png"),
+	), readScreen)
+
+	s.AddResource(mcp.NewResource(displaysURI, "Displays",
+		mcp.WithResourceDescription("Screen size, scale factor and monitor layout as JSON. "+coordsDoc),
+		mcp.WithMIMEType("application/json"),
+	), readDisplays)
+
+	s.AddResource(mcp.NewResource(cursorURI, "Cursor position",
+		mcp.WithResourceDescription("Position of the mouse cursor as JSON. "+coordsDoc),
+		mcp.WithMIMEType("application/json"),
+	), readCursor)
+
+	s.AddResource(mcp.NewResource(windowURI, "Active window",
+		mcp.WithResourceDescription("Bounds and, where the accessibility tree is available, title and application of the active window as JSON. "+coordsDoc),
+		mcp.WithMIMEType("application/json"),
+	), readWindow)
+}
+
+func readScreen(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	data, err := captureScreenPNG(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.BlobResourceContents{
+		URI:      screenURI,
+		MIMEType: "image/png",
+		Blob:     data,
+	}}, nil
+}
+
+// captureScreenPNG captures the whole screen as a base64-encoded PNG. In the
+// screenshot coordinate space the image is resized to that space's size, so
+// pixels read off it are valid tool coordinates.
+func captureScreenPNG(ctx context.Context) (string, error) {
+	img, err := captureScreen(ctx)
+	if err != nil {
+		return "", err
+	}
+	return encodePNG(img)
+}
+
+// captureScreen captures the whole screen, resized to the size of the
+// screenshot coordinate space when tools use it
+func captureScreen(ctx context.Context) (image.Image, error) {
+	img, err := automation.CaptureImage(ctx, image.Rectangle{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture screen: %w", err)
+	}
+	if coords.Space == automation.CoordScreenshot {
+		w, h := coords.ScreenshotSize()
+		img = automation.ResizeImage(img, w, h)
+	}
+	return img, nil
+}
+
+// encodePNG encodes an image as a base64-encoded PNG
+func encodePNG(img image.Image) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// rect is a rectangle in the tools' coordinate space
+type rect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// toRect converts a logical rectangle to the tools' coordinate space
+func toRect(r image.Rectangle) rect {
+	x0, y0 := coords.FromLogical(r.Min.X, r.Min.Y)
+	x1, y1 := coords.FromLogical(r.Max.X, r.Max.Y)
+	return rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+func readDisplays(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	// The coordinate system is re-read so resolution changes show up
+	cs := automation.NewCoordSystem(coords.Space)
+	cs.ScreenshotWidth, cs.ScreenshotHeight = coords.ScreenshotWidth, coords.ScreenshotHeight
+	monitors, err := automation.Monitors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list monitors: %w", err)
+	}
+
+	w, h := cs.Size()
+	pw, ph := cs.PhysicalSize()
+	displays := struct {
+		CoordinateSpace string  `json:"coordinateSpace"`
+		Width           int     `json:"width"`
+		Height          int     `json:"height"`
+		PhysicalWidth   int     `json:"physicalWidth"`
+		PhysicalHeight  int     `json:"physicalHeight"`
+		Scale           float64 `json:"scale"`
+		Monitors        []rect  `json:"monitors"`
+	}{cs.Space.String(), w, h, pw, ph, cs.Scale, nil}
+	for _, m := range monitors {
+		displays.Monitors = append(displays.Monitors, toRect(m))
+	}
+	return jsonContents(displaysURI, displays)
+}
+
+func readCursor(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	x, y, err := automation.CurrentBackend().MousePosition(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cursor position: %w", err)
+	}
+	x, y = coords.FromLogical(x, y)
+	return jsonContents(cursorURI, map[string]int{"x": x, "y": y})
+}
+
+func readWindow(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	bounds, err := automation.CurrentBackend().ActiveWindowBounds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the active window: %w", err)
+	}
+
+	window := struct {
+		Title  string `json:"title,omitempty"`
+		App    string `json:"app,omitempty"`
+		Bounds rect   `json:"bounds"`
+	}{Bounds: toRect(bounds)}
+
+	// The title comes from the accessibility tree, where there is one
+	a11yCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if a11y, err := automation.OpenA11y(a11yCtx); err == nil {
+		found, _ := a11y.Find(a11yCtx, automation.A11yQuery{States: []string{"active"}, MaxDepth: 1})
+		if len(found) > 0 {
+			window.Title, window.App = found[0].Name, found[0].App
+		}
+		a11y.Close()
+	}
+	return jsonContents(windowURI, window)
+}
+
+// jsonContents returns a value as the JSON contents of a resource
+func jsonContents(uri string, v any) ([]mcp.ResourceContents, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: "application/json",
+		Text:     string(data),
+	}}, nil
+}
+
+// subscriptions tracks the resources the client subscribed to and notifies
+// it when they change. mcp-go advertises subscriptions but does not handle
+// resources/subscribe, so those requests are answered here before messages
+// reach the server.
+type subscriptions struct {
+	out io.Writer
+
+	mu   sync.Mutex
+	uris map[string]bool
+	// last holds a fingerprint of each subscribed resource's last state;
+	// a missing entry means the state has not been observed yet
+	last map[string]string
+	// screen holds the screen samples of the last notification
+	screen []byte
+}
+
+func newSubscriptions(out io.Writer) *subscriptions {
+	return &subscriptions{out: out, uris: map[string]bool{}, last: map[string]string{}}
+}
+
+// filter returns a reader of the messages from r that are not subscription
+// requests
+func (s *subscriptions) filter(r io.Reader) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		reader := bufio.NewReader(r)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 && !s.handle(line) {
+				if _, err := pw.Write(line); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return pr
+}
+
+// handle answers a resources/subscribe or resources/unsubscribe request and
+// reports whether the message was one
+func (s *subscriptions) handle(line []byte) bool {
+	var msg struct {
+		ID     mcp.RequestId `json:"id"`
+		Method string        `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if json.Unmarshal(line, &msg) != nil || msg.ID.IsNil() {
+		return false
+	}
+
+	var response any
+	switch msg.Method {
+	case "resources/subscribe":
+		switch msg.Params.URI {
+		case screenURI, displaysURI, cursorURI, windowURI:
+			s.mu.Lock()
+			s.uris[msg.Params.URI] = true
+			delete(s.last, msg.Params.URI)
+			if msg.Params.URI == screenURI {
+				s.screen = nil
+			}
+			s.mu.Unlock()
+			response = mcp.NewJSONRPCResultResponse(msg.ID, mcp.EmptyResult{})
+		default:
+			response = mcp.NewJSONRPCError(msg.ID, mcp.INVALID_PARAMS,
+				fmt.Sprintf("unknown resource %q", msg.Params.URI), nil)
+		}
+	case "resources/unsubscribe":
+		s.mu.Lock()
+		delete(s.uris, msg.Params.URI)
+		delete(s.last, msg.Params.URI)
+		s.mu.Unlock()
+		response = mcp.NewJSONRPCResultResponse(msg.ID, mcp.EmptyResult{})
+	default:
+		return false
+	}
+
+	data, err := json.Marshal(response)
+	if err == nil {
+		s.out.Write(append(data, '\n'))
+	}
+	return true
+}
+
+// subscribed reports whether the client subscribed to a resource
+func (s *subscriptions) subscribed(uri string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.uris[uri]
+}
+
+// update records the latest state of a resource and reports whether it
+// changed since it was last observed
+func (s *subscriptions) update(uri, state string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, seen := s.last[uri]
+	s.last[uri] = state
+	return seen && last != state
+}
+
+// watch polls the subscribed resources until ctx is done and sends
+// notifications/resources/updated when one changes
+func (s *subscriptions) watch(ctx context.Context, srv *server.MCPServer) {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for tick := 0; ; tick++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var changed []string
+		if s.subscribed(cursorURI) {
+			if x, y, err := automation.CurrentBackend().MousePosition(ctx); err == nil && s.update(cursorURI, fmt.Sprint(x, y)) {
+				changed = append(changed, cursorURI)
+			}
+		}
+		if s.subscribed(windowURI) {
+			if b, err := automation.CurrentBackend().ActiveWindowBounds(ctx); err == nil && s.update(windowURI, b.String()) {
+				changed = append(changed, windowURI)
+			}
+		}
+		if tick%displaysEvery == 0 && s.subscribed(displaysURI) {
+			if monitors, err := automation.Monitors(ctx); err == nil && s.update(displaysURI, fmt.Sprint(monitors)) {
+				changed = append(changed, displaysURI)
+			}
+		}
+		if tick%screenEvery == 0 && s.subscribed(screenURI) {
+			if img, err := automation.CaptureImage(ctx, image.Rectangle{}); err == nil && s.screenChanged(sampleScreen(img)) {
+				changed = append(changed, screenURI)
+			}
+		}
+
+		for _, uri := range changed {
+			srv.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		}
+	}
+}
+
+// sampleScreen returns the RGB values of a grid of pixels of a screenshot
+func sampleScreen(img image.Image) []byte {
+	b := img.Bounds()
+	rgba, ok := img.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(b)
+		draw.Draw(rgba, b, img, b.Min, draw.Src)
+	}
+
+	samples := make([]byte, 0, 3*(b.Dx()/screenSampleStep+1)*(b.Dy()/screenSampleStep+1))
+	for y := b.Min.Y; y < b.Max.Y; y += screenSampleStep {
+		for x := b.Min.X; x < b.Max.X; x += screenSampleStep {
+			o := rgba.PixOffset(x, y)
+			samples = append(samples, rgba.Pix[o], rgba.Pix[o+1], rgba.Pix[o+2])
+		}
+	}
+	return samples
+}
+
+// screenChanged compares samples of the screen to those taken when the client
+// was last notified and reports whether more than screenChangeThreshold of
+// them differ noticeably
+func (s *subscriptions) screenChanged(samples []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.screen) != len(samples) {
+		s.screen = samples
+		return false
+	}
+
+	changed := 0
+	for i := 0; i < len(samples); i += 3 {
+		for c := 0; c < 3; c++ {
+			d := int(s.screen[i+c]) - int(samples[i+c])
+			if d > 24 || d < -24 {
+				changed++
+				break
+			}
+		}
+	}
+	if float64(changed) <= screenChangeThreshold*float64(len(samples)/3) {
+		return false
+	}
+	s.screen = samples
+	return true
+}
+
+// syncWriter serializes writes, so responses written by the subscriptions
+// never interleave with those of the server
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestSubscriptionsHandle(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		wantHandled bool
+		// wantError is the JSON-RPC error code of the response, 0 for a result
+		wantError      int
+		wantSubscribed map[string]bool
+	}{
+		{
+			name:           "subscribe",
+			line:           `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"cursor://position"}}`,
+			wantHandled:    true,
+			wantSubscribed: map[string]bool{cursorURI: true, screenURI: true},
+		},
+		{
+			name:           "subscribe with string id",
+			line:           `{"jsonrpc":"2.0","id":"a","method":"resources/subscribe","params":{"uri":"window://active"}}`,
+			wantHandled:    true,
+			wantSubscribed: map[string]bool{windowURI: true, screenURI: true},
+		},
+		{
+			name:           "unsubscribe",
+			line:           `{"jsonrpc":"2.0","id":2,"method":"resources/unsubscribe","params":{"uri":"screen://current"}}`,
+			wantHandled:    true,
+			wantSubscribed: map[string]bool{screenURI: false},
+		},
+		{
+			name:           "unknown resource",
+			line:           `{"jsonrpc":"2.0","id":3,"method":"resources/subscribe","params":{"uri":"file:///etc/passwd"}}`,
+			wantHandled:    true,
+			wantError:      -32602,
+			wantSubscribed: map[string]bool{"file:///etc/passwd": false, screenURI: true},
+		},
+		{
+			name:           "other request",
+			line:           `{"jsonrpc":"2.0","id":4,"method":"resources/read","params":{"uri":"cursor://position"}}`,
+			wantSubscribed: map[string]bool{screenURI: true},
+		},
+		{
+			name:           "notification",
+			line:           `{"jsonrpc":"2.0","method":"resources/subscribe","params":{"uri":"cursor://position"}}`,
+			wantSubscribed: map[string]bool{cursorURI: false, screenURI: true},
+		},
+		{
+			name:           "invalid JSON",
+			line:           `{"jsonrpc":`,
+			wantSubscribed: map[string]bool{screenURI: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			s := newSubscriptions(&out)
+			s.uris[screenURI] = true
+			s.last[screenURI] = "state"
+
+			if got := s.handle([]byte(tt.line + "\n")); got != tt.wantHandled {
+				t.Fatalf("handle() = %v, want %v", got, tt.wantHandled)
+			}
+			for uri, want := range tt.wantSubscribed {
+				if got := s.subscribed(uri); got != want {
+					t.Errorf("subscribed(%q) = %v, want %v", uri, got, want)
+				}
+			}
+
+			if !tt.wantHandled {
+				if out.Len() != 0 {
+					t.Errorf("handle() wrote %q, want nothing", out.String())
+				}
+				return
+			}
+			var response struct {
+				ID     any             `json:"id"`
+				Result json.RawMessage `json:"result"`
+				Error  *struct {
+					Code int `json:"code"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(out.Bytes(), &response); err != nil {
+				t.Fatalf("invalid response %q: %v", out.String(), err)
+			}
+			var request struct {
+				ID any `json:"id"`
+			}
+			json.Unmarshal([]byte(tt.line), &request)
+			if response.ID != request.ID {
+				t.Errorf("response id = %v, want %v", response.ID, request.ID)
+			}
+			switch {
+			case tt.wantError == 0 && response.Error != nil:
+				t.Errorf("response error = %d, want a result", response.Error.Code)
+			case tt.wantError != 0 && (response.Error == nil || response.Error.Code != tt.wantError):
+				t.Errorf("response = %s, want error %d", out.String(), tt.wantError)
+			}
+		})
+	}
+}
+
+func TestSubscriptionsUpdate(t *testing.T) {
+	s := newSubscriptions(&bytes.Buffer{})
+	steps := []struct {
+		state string
+		want  bool
+	}{
+		{"1 2", false}, // first observation
+		{"1 2", false},
+		{"3 4", true},
+		{"3 4", false},
+	}
+	for i, step := range steps {
+		if got := s.update(cursorURI, step.state); got != step.want {
+			t.Errorf("step %d: update(%q) = %v, want %v", i, step.state, got, step.want)
+		}
+	}
+
+	// Subscribing again starts over, so the next state is not a change
+	s.handle([]byte(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"cursor://position"}}`))
+	if s.update(cursorURI, "5 6") {
+		t.Error("update() after subscribing = true, want false")
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/dmahlow/desktop-automation-mcp/internal/automation"
+)
+
+// pointerResult is the structured result of the mouse tools
+type pointerResult struct {
+	X               int      `json:"x" jsonschema:"X coordinate of the cursor"`
+	Y               int      `json:"y" jsonschema:"Y coordinate of the cursor"`
+	ScreenWidth     int      `json:"screenWidth" jsonschema:"Width of the screen in the same coordinate space"`
+	ScreenHeight    int      `json:"screenHeight" jsonschema:"Height of the screen in the same coordinate space"`
+	CoordinateSpace string   `json:"coordinateSpace" jsonschema:"Coordinate space of all coordinates: logical, physical or screenshot"`
+	DurationMs      int64    `json:"durationMs" jsonschema:"Time the tool took in milliseconds"`
+	Warnings        []string `json:"warnings,omitempty" jsonschema:"Problems that did not make the tool fail"`
+}
+
+// colorResult is the structured result of get_pixel_color
+type colorResult struct {
+	X   int    `json:"x" jsonschema:"X coordinate of the pixel"`
+	Y   int    `json:"y" jsonschema:"Y coordinate of the pixel"`
+	Hex string `json:"hex" jsonschema:"Color as #rrggbb"`
+	R   uint8  `json:"r" jsonschema:"Red component, 0-255"`
+	G   uint8  `json:"g" jsonschema:"Green component, 0-255"`
+	B   uint8  `json:"b" jsonschema:"Blue component, 0-255"`
+}
+
+// typeResult is the structured result of type_text
+type typeResult struct {
+	Characters int      `json:"characters" jsonschema:"Number of characters typed"`
+	DurationMs int64    `json:"durationMs" jsonschema:"Time the tool took in milliseconds"`
+	Warnings   []string `json:"warnings,omitempty" jsonschema:"Problems that did not make the tool fail"`
+}
+
+// keyResult is the structured result of press_key
+type keyResult struct {
+	Keys       []string `json:"keys" jsonschema:"Keys pressed together, modifiers first"`
+	DurationMs int64    `json:"durationMs" jsonschema:"Time the tool took in milliseconds"`
+}
+
+// elementsResult is the structured result of a11y_tree and a11y_find
+type elementsResult struct {
+	Elements []*automation.A11yElement `json:"elements" jsonschema:"Matching elements, or the applications for a11y_tree"`
+	Count    int                       `json:"count" jsonschema:"Number of elements, not counting children"`
+}
+
+// elementResult is the structured result of the a11y tools acting on an element
+type elementResult struct {
+	Element    *automation.A11yElement `json:"element" jsonschema:"Element acted on"`
+	Action     string                  `json:"action,omitempty" jsonschema:"Action performed by a11y_invoke"`
+	X          *int                    `json:"x,omitempty" jsonschema:"X coordinate clicked by a11y_click"`
+	Y          *int                    `json:"y,omitempty" jsonschema:"Y coordinate clicked by a11y_click"`
+	DurationMs int64                   `json:"durationMs" jsonschema:"Time the tool took in milliseconds"`
+}
+
+// batchResult is the structured result of execute_actions
+type batchResult struct {
+	Steps      []actionResult `json:"steps" jsonschema:"Result of each action that ran, in order"`
+	Completed  int            `json:"completed" jsonschema:"Number of actions that succeeded"`
+	Failed     int            `json:"failed" jsonschema:"Number of actions that failed"`
+	Skipped    int            `json:"skipped" jsonschema:"Number of actions not run after a failure"`
+	DurationMs int64          `json:"durationMs" jsonschema:"Time the tool took in milliseconds"`
+}
+
+// a11yElementSchema describes automation.A11yElement, which jsonschema cannot
+// infer because children are elements themselves
+const a11yElementSchema = `{
+	"type": "object",
+	"properties": {
+		"id": {"type": "string", "description": "Identifies the element as BUS:PATH while its application runs"},
+		"app": {"type": "string"},
+		"role": {"type": "string"},
+		"name": {"type": "string"},
+		"description": {"type": "string"},
+		"states": {"type": "array", "items": {"type": "string"}},
+		"bounds": {
+			"type": "object",
+			"properties": {
+				"x": {"type": "integer"},
+				"y": {"type": "integer"},
+				"width": {"type": "integer"},
+				"height": {"type": "integer"}
+			},
+			"required": ["x", "y", "width", "height"]
+		},
+		"actions": {"type": "array", "items": {"type": "string"}},
+		"text": {"type": "string"},
+		"value": {"type": "number"},
+		"children": {"type": "array", "items": {"$ref": "#/$defs/element"}}
+	},
+	"required": ["id", "app", "role", "name"]
+}`
+
+// a11yOutputSchema is mcp.WithOutputSchema for results containing
+// accessibility elements, which refer to a shared element definition
+func a11yOutputSchema[T any]() mcp.ToolOption {
+	var element jsonschema.Schema
+	if err := json.Unmarshal([]byte(a11yElementSchema), &element); err != nil {
+		panic(err)
+	}
+	schema, err := jsonschema.For[T](&jsonschema.ForOptions{
+		IgnoreInvalidTypes: true,
+		TypeSchemas: map[reflect.Type]*jsonschema.Schema{
+			reflect.TypeFor[automation.A11yElement](): {Ref: "#/$defs/element"},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	clearRefTypes(schema)
+	schema.Defs = map[string]*jsonschema.Schema{"element": &element}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		panic(err)
+	}
+	return mcp.WithRawOutputSchema(data)
+}
+
+// clearRefTypes removes the "null" type jsonschema gives references to the
+// element definition through pointers, which would only allow null
+func clearRefTypes(s *jsonschema.Schema) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		s.Type, s.Types = "", nil
+	}
+	clearRefTypes(s.Items)
+	for _, p := range s.Properties {
+		clearRefTypes(p)
+	}
+}
+
+// newPointerResult describes the cursor after a mouse tool moved it to the
+// logical position x, y
+func newPointerResult(x, y int, start time.Time) pointerResult {
+	r := pointerResult{CoordinateSpace: coords.Space.String(), DurationMs: since(start)}
+	r.X, r.Y = coords.FromLogical(x, y)
+	r.ScreenWidth, r.ScreenHeight = coords.Size()
+
+	// The cursor may be stopped short, e.g. by a screen edge or barrier
+	if cx, cy := automation.GetPosition(); abs(cx-x) > 1 || abs(cy-y) > 1 {
+		cx, cy = coords.FromLogical(cx, cy)
+		r.Warnings = append(r.Warnings, fmt.Sprintf("cursor is at (%d, %d) instead of (%d, %d)", cx, cy, r.X, r.Y))
+	}
+	return r
+}
+
+// newElementResult describes an element an a11y tool acted on, with bounds in
+// the tools' coordinate space
+func newElementResult(e *automation.A11yElement, start time.Time) elementResult {
+	automation.ConvertA11yBounds([]*automation.A11yElement{e}, coords)
+	return elementResult{Element: e, DurationMs: since(start)}
+}
+
+// since returns the milliseconds elapsed since start
+func since(start time.Time) int64 {
+	return time.Since(start).Milliseconds()
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
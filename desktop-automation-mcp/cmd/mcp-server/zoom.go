@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/dmahlow/desktop-automation-mcp/internal/automation"
+)
+
+// zoomResult is the structured result of the zoom tool
+type zoomResult struct {
+	X          int   `json:"x" jsonschema:"X coordinate of the region's top-left corner"`
+	Y          int   `json:"y" jsonschema:"Y coordinate of the region's top-left corner"`
+	Width      int   `json:"width" jsonschema:"Width of the region"`
+	Height     int   `json:"height" jsonschema:"Height of the region"`
+	Scale      int   `json:"scale" jsonschema:"Pixels of the image per unit of the region"`
+	OffsetX    int   `json:"offsetX" jsonschema:"X position of the region's top-left corner in the image, right of the legend"`
+	OffsetY    int   `json:"offsetY" jsonschema:"Y position of the region's top-left corner in the image, below the legend"`
+	Step       int   `json:"step" jsonschema:"Distance in units between the legend's lines"`
+	DurationMs int64 `json:"durationMs" jsonschema:"Time the tool took in milliseconds"`
+}
+
+// addZoomTool registers the zoom tool, which magnifies a region of the screen
+// for targeting small elements
+func addZoomTool(s *server.MCPServer, coordsDoc string) {
+	zoomTool := mcp.NewTool("zoom",
+		mcp.WithDescription("Capture a region of the screen magnified so each unit is scale pixels, with lines labelled "+
+			"with their screen coordinates along the top and left edges. Use it to locate small checkboxes and icons "+
+			"exactly before clicking. "+coordsDoc),
+		mcp.WithOutputSchema[zoomResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("x",
+			mcp.Required(),
+			mcp.Description("X coordinate of the region's top-left corner"),
+		),
+		mcp.WithNumber("y",
+			mcp.Required(),
+			mcp.Description("Y coordinate of the region's top-left corner"),
+		),
+		mcp.WithNumber("width",
+			mcp.Required(),
+			mcp.Description("Width of the region"),
+		),
+		mcp.WithNumber("height",
+			mcp.Required(),
+			mcp.Description("Height of the region"),
+		),
+		mcp.WithNumber("scale",
+			mcp.Description("Pixels per unit of the region, 1-16 (default: 3)"),
+		),
+		mcp.WithString("filter",
+			mcp.Description("Upscaling filter: nearest keeps edges sharp and colours exact (default), smooth interpolates"),
+			mcp.Enum("nearest", "smooth"),
+		),
+	)
+
+	s.AddTool(zoomTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		var values [4]int
+		for i, name := range []string{"x", "y", "width", "height"} {
+			v, err := request.RequireInt(name)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			values[i] = v
+		}
+		x, y, w, h := values[0], values[1], values[2], values[3]
+		if w <= 0 || h <= 0 {
+			return mcp.NewToolResultError("width and height must be positive"), nil
+		}
+		filter, err := automation.ParseZoomFilter(request.GetString("filter", "nearest"))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		z, err := automation.Zoom(ctx, coords, image.Rect(x, y, x+w, y+h), request.GetInt("scale", 3), filter)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Zoom failed: %v", err)), nil
+		}
+		data, err := encodePNG(z.Image)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result := zoomResult{
+			X: x, Y: y, Width: w, Height: h,
+			Scale: z.Scale, OffsetX: z.Offset.X, OffsetY: z.Offset.Y, Step: z.Step,
+			DurationMs: since(start),
+		}
+		legend := fmt.Sprintf("Region (%d, %d) %dx%d at %dx, legend every %d. "+
+			"Screen x = %d + (image x - %d) / %d, screen y = %d + (image y - %d) / %d",
+			x, y, w, h, z.Scale, z.Step, x, z.Offset.X, z.Scale, y, z.Offset.Y, z.Scale)
+		return &mcp.CallToolResult{
+			Content:           []mcp.Content{mcp.NewImageContent(data, "image/png"), mcp.NewTextContent(legend)},
+			StructuredContent: result,
+		}, nil
+	})
+}
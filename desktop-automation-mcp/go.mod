@@ -4,16 +4,19 @@ go 1.23.0
 
 require (
 	github.com/go-vgo/robotgo v0.110.3
-	github.com/mark3labs/mcp-go v0.32.0
+	github.com/godbus/dbus/v5 v5.1.0
+	github.com/google/jsonschema-go v0.4.2
+	github.com/jezek/xgb v1.1.1
+	github.com/mark3labs/mcp-go v0.47.1
+	golang.org/x/image v0.20.0
+	golang.org/x/sys v0.33.0
 )
 
 require (
 	github.com/dblohm7/wingoes v0.0.0-20240820181039-f2b84150679e // indirect
 	github.com/gen2brain/shm v0.1.0 // indirect
 	github.com/go-ole/go-ole v1.3.0 // indirect
-	github.com/godbus/dbus/v5 v5.1.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
-	github.com/jezek/xgb v1.1.1 // indirect
 	github.com/kbinani/screenshot v0.0.0-20240820160931-a8a2c5d0e191 // indirect
 	github.com/lufia/plan9stats v0.0.0-20240819163618-b1d8f4d146e7 // indirect
 	github.com/lxn/win v0.0.0-20210218163916-a377121e959e // indirect
@@ -35,7 +38,5 @@ require (
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
 	github.com/yusufpapurcu/wmi v1.2.4 // indirect
 	golang.org/x/exp v0.0.0-20240904232852-e7e105dedf7e // indirect
-	golang.org/x/image v0.20.0 // indirect
 	golang.org/x/net v0.41.0 // indirect
-	golang.org/x/sys v0.33.0 // indirect
 )
@@ -0,0 +1,507 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// AT-SPI bus names, paths and interfaces
+const (
+	atspiRegistry   = "org.a11y.atspi.Registry"
+	atspiRootPath   = "/org/a11y/atspi/accessible/root"
+	atspiAccessible = "org.a11y.atspi.Accessible"
+	atspiComponent  = "org.a11y.atspi.Component"
+	atspiAction     = "org.a11y.atspi.Action"
+	atspiText       = "org.a11y.atspi.Text"
+	atspiEditable   = "org.a11y.atspi.EditableText"
+	atspiValue      = "org.a11y.atspi.Value"
+)
+
+const (
+	// a11yCallTimeout bounds every D-Bus call, so an application that does
+	// not respond is skipped instead of stalling the walk of the whole tree
+	a11yCallTimeout = 2 * time.Second
+	// a11yMaxText is the number of characters of an element's text returned
+	a11yMaxText = 1000
+)
+
+// atspiStates are the names of the AT-SPI state bits, indexed by bit number
+var atspiStates = []string{
+	"invalid", "active", "armed", "busy", "checked", "collapsed", "defunct",
+	"editable", "enabled", "expandable", "expanded", "focusable", "focused",
+	"has-tooltip", "horizontal", "iconified", "modal", "multi-line",
+	"multiselectable", "opaque", "pressed", "resizable", "selectable",
+	"selected", "sensitive", "showing", "single-line", "stale", "transient",
+	"vertical", "visible", "manages-descendants", "indeterminate", "required",
+	"truncated", "animated", "invalid-entry", "supports-autocompletion",
+	"selectable-text", "is-default", "visited", "checkable", "has-popup",
+	"read-only",
+}
+
+// A11yBounds is the position and size of an element on the screen
+type A11yBounds struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// A11yElement is a node of the accessibility tree
+type A11yElement struct {
+	// ID identifies the element as BUS:PATH while its application runs
+	ID          string         `json:"id"`
+	App         string         `json:"app"`
+	Role        string         `json:"role"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	States      []string       `json:"states,omitempty"`
+	Bounds      *A11yBounds    `json:"bounds,omitempty"`
+	Actions     []string       `json:"actions,omitempty"`
+	Text        string         `json:"text,omitempty"`
+	Value       *float64       `json:"value,omitempty"`
+	Children    []*A11yElement `json:"children,omitempty"`
+
+	ref        a11yRef
+	interfaces []string
+}
+
+// HasState reports whether the element has the named state
+func (e *A11yElement) HasState(state string) bool {
+	for _, s := range e.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Center returns the centre of the element in logical coordinates, or false
+// if the element has no position on the screen
+func (e *A11yElement) Center() (x, y int, ok bool) {
+	b := e.Bounds
+	if b == nil || b.Width <= 0 || b.Height <= 0 || b.X+b.Width <= 0 || b.Y+b.Height <= 0 {
+		return 0, 0, false
+	}
+	return b.X + b.Width/2, b.Y + b.Height/2, true
+}
+
+// A11yQuery selects elements of the accessibility tree
+type A11yQuery struct {
+	// App restricts the search to applications whose name matches
+	App string
+	// Role matches the role name, such as "push button", or its last word
+	Role string
+	// Name matches the accessible name case-insensitively; * and ? are wildcards
+	Name string
+	// States lists states the element must have, such as "focused"
+	States []string
+	// All includes elements that are not showing on the screen
+	All bool
+	// MaxDepth limits how deep below the applications the tree is walked;
+	// zero means no limit
+	MaxDepth int
+}
+
+// matches reports whether an element satisfies the role, name and state filters
+func (q A11yQuery) matches(e *A11yElement) bool {
+	if q.Role != "" && !matchRole(e.Role, q.Role) {
+		return false
+	}
+	if q.Name != "" && !matchName(e.Name, q.Name) {
+		return false
+	}
+	for _, s := range q.States {
+		if !e.HasState(strings.ToLower(s)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchRole compares role names ignoring case and separators, so "button"
+// matches "push button" and "toggle_button"
+func matchRole(role, pattern string) bool {
+	norm := func(s string) string {
+		return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+			return r == ' ' || r == '_' || r == '-'
+		}), " ")
+	}
+	role, pattern = norm(role), norm(pattern)
+	return role == pattern || strings.HasSuffix(role, " "+pattern)
+}
+
+// matchName compares names case-insensitively with * and ? wildcards. All
+// other characters, including / and [, match only themselves.
+func matchName(name, pattern string) bool {
+	n, p := []rune(strings.ToLower(name)), []rune(strings.ToLower(pattern))
+
+	// On a mismatch after a *, retry with the * taking one more character
+	star, next := -1, 0
+	i, j := 0, 0
+	for i < len(n) {
+		switch {
+		case j < len(p) && (p[j] == '?' || p[j] == n[i]):
+			i++
+			j++
+		case j < len(p) && p[j] == '*':
+			star, next = j, i
+			j++
+		case star >= 0:
+			next++
+			i, j = next, star+1
+		default:
+			return false
+		}
+	}
+	for j < len(p) && p[j] == '*' {
+		j++
+	}
+	return j == len(p)
+}
+
+// a11yRef addresses an accessible object on the accessibility bus
+type a11yRef struct {
+	Dest string
+	Path dbus.ObjectPath
+}
+
+// A11y is a connection to the AT-SPI accessibility bus
+type A11y struct {
+	conn *dbus.Conn
+}
+
+// OpenA11y connects to the accessibility bus of the desktop session, found
+// through $AT_SPI_BUS_ADDRESS or the org.a11y.Bus service of the session bus
+func OpenA11y(ctx context.Context) (*A11y, error) {
+	addr := os.Getenv("AT_SPI_BUS_ADDRESS")
+	if addr == "" {
+		bus, err := dbus.ConnectSessionBus(dbus.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to the session bus: %w", err)
+		}
+		err = bus.Object("org.a11y.Bus", "/org/a11y/bus").CallWithContext(ctx, "org.a11y.Bus.GetAddress", 0).Store(&addr)
+		bus.Close()
+		if err != nil {
+			return nil, fmt.Errorf("accessibility bus not available (is at-spi2-core running?): %w", err)
+		}
+	}
+
+	conn, err := dbus.Connect(addr, dbus.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the accessibility bus: %w", err)
+	}
+	return &A11y{conn: conn}, nil
+}
+
+// Close closes the connection to the accessibility bus
+func (a *A11y) Close() error {
+	return a.conn.Close()
+}
+
+// Tree returns the applications matching q.App with their descendants down
+// to q.MaxDepth. The role, name and state filters of q are not applied.
+func (a *A11y) Tree(ctx context.Context, q A11yQuery) ([]*A11yElement, error) {
+	var apps []*A11yElement
+	err := a.walk(ctx, q, func(e, parent *A11yElement) error {
+		if err := a.loadDetails(ctx, e); err != nil {
+			return err
+		}
+		if parent == nil {
+			apps = append(apps, e)
+		} else {
+			parent.Children = append(parent.Children, e)
+		}
+		return nil
+	})
+	return apps, err
+}
+
+// Find returns the elements matching q in tree order
+func (a *A11y) Find(ctx context.Context, q A11yQuery) ([]*A11yElement, error) {
+	var found []*A11yElement
+	err := a.walk(ctx, q, func(e, parent *A11yElement) error {
+		if parent == nil || !q.matches(e) {
+			return nil
+		}
+		if err := a.loadDetails(ctx, e); err != nil {
+			return err
+		}
+		found = append(found, e)
+		return nil
+	})
+	return found, err
+}
+
+// walk visits the applications matching q.App and their descendants depth
+// first. Elements that are not showing are skipped with their subtrees unless
+// q.All is set, and so are elements whose application does not respond.
+func (a *A11y) walk(ctx context.Context, q A11yQuery, visit func(e, parent *A11yElement) error) error {
+	root := a11yRef{Dest: atspiRegistry, Path: atspiRootPath}
+	apps, err := a.children(ctx, root)
+	if err != nil {
+		return fmt.Errorf("failed to list accessible applications: %w", err)
+	}
+
+	seen := map[a11yRef]bool{}
+	var descend func(ref a11yRef, app string, parent *A11yElement, depth int) error
+	descend = func(ref a11yRef, app string, parent *A11yElement, depth int) error {
+		if seen[ref] {
+			return nil
+		}
+		seen[ref] = true
+
+		e, err := a.load(ctx, ref)
+		if err != nil {
+			// Applications may exit or hang while the tree is walked
+			return ctx.Err()
+		}
+		if parent == nil {
+			app = e.Name
+		}
+		e.App = app
+		if parent != nil && !q.All && !e.HasState("showing") {
+			return nil
+		}
+		if e.HasState("defunct") {
+			return nil
+		}
+		if err := visit(e, parent); err != nil {
+			return err
+		}
+		if q.MaxDepth > 0 && depth >= q.MaxDepth {
+			return nil
+		}
+
+		children, err := a.children(ctx, ref)
+		if err != nil {
+			return ctx.Err()
+		}
+		for _, c := range children {
+			if err := descend(c, app, e, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, ref := range apps {
+		if q.App != "" {
+			var name string
+			if err := a.property(ctx, ref, atspiAccessible, "Name", &name); err != nil || !matchName(name, q.App) {
+				continue
+			}
+		}
+		if err := descend(ref, "", nil, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// load reads the name, role and states of an element
+func (a *A11y) load(ctx context.Context, ref a11yRef) (*A11yElement, error) {
+	e := &A11yElement{ID: ref.Dest + ":" + string(ref.Path), ref: ref}
+
+	var props map[string]dbus.Variant
+	if err := a.call(ctx, ref, "org.freedesktop.DBus.Properties.GetAll", atspiAccessible).Store(&props); err != nil {
+		return nil, err
+	}
+	e.Name, _ = props["Name"].Value().(string)
+	e.Description, _ = props["Description"].Value().(string)
+
+	if err := a.call(ctx, ref, atspiAccessible+".GetRoleName").Store(&e.Role); err != nil {
+		return nil, err
+	}
+
+	var states []uint32
+	if err := a.call(ctx, ref, atspiAccessible+".GetState").Store(&states); err != nil {
+		return nil, err
+	}
+	for i, word := range states {
+		for bit := 0; bit < 32; bit++ {
+			if word&(1<<bit) != 0 && i*32+bit < len(atspiStates) {
+				e.States = append(e.States, atspiStates[i*32+bit])
+			}
+		}
+	}
+	return e, nil
+}
+
+// loadDetails reads the bounds, actions and text of an element from the
+// interfaces it implements
+func (a *A11y) loadDetails(ctx context.Context, e *A11yElement) error {
+	if err := a.call(ctx, e.ref, atspiAccessible+".GetInterfaces").Store(&e.interfaces); err != nil {
+		return ctx.Err()
+	}
+
+	if e.implements(atspiComponent) {
+		// Coordinate type 0 is relative to the screen
+		var ext struct{ X, Y, Width, Height int32 }
+		if a.call(ctx, e.ref, atspiComponent+".GetExtents", uint32(0)).Store(&ext) == nil {
+			e.Bounds = &A11yBounds{X: int(ext.X), Y: int(ext.Y), Width: int(ext.Width), Height: int(ext.Height)}
+		}
+	}
+
+	if e.implements(atspiAction) {
+		var actions []struct{ Name, Description, KeyBinding string }
+		if a.call(ctx, e.ref, atspiAction+".GetActions").Store(&actions) == nil {
+			for _, act := range actions {
+				e.Actions = append(e.Actions, act.Name)
+			}
+		}
+	}
+
+	if e.implements(atspiText) {
+		var text string
+		if a.call(ctx, e.ref, atspiText+".GetText", int32(0), int32(-1)).Store(&text) == nil {
+			if r := []rune(text); len(r) > a11yMaxText {
+				text = string(r[:a11yMaxText]) + "…"
+			}
+			e.Text = text
+		}
+	}
+	if e.implements(atspiValue) {
+		var value float64
+		if a.property(ctx, e.ref, atspiValue, "CurrentValue", &value) == nil {
+			e.Value = &value
+		}
+	}
+	return ctx.Err()
+}
+
+// defaultActions are the actions DoAction performs when no name is given,
+// in order of preference
+var defaultActions = []string{"click", "press", "activate", "toggle", "jump", "open"}
+
+// DoAction performs an action of an element through the AT-SPI Action
+// interface, without synthesizing input. The action is matched by name
+// case-insensitively; an empty name selects the element's default action such
+// as click, press or activate. The name of the performed action is returned.
+func (a *A11y) DoAction(ctx context.Context, e *A11yElement, name string) (string, error) {
+	if !e.implements(atspiAction) || len(e.Actions) == 0 {
+		return "", fmt.Errorf("%s %q has no actions", e.Role, e.Name)
+	}
+
+	index := -1
+	if name == "" {
+		for _, want := range defaultActions {
+			if index = indexFold(e.Actions, want); index >= 0 {
+				break
+			}
+		}
+		if index < 0 {
+			index = 0
+		}
+	} else if index = indexFold(e.Actions, name); index < 0 {
+		return "", fmt.Errorf("%s %q has no action %q, available: %s", e.Role, e.Name, name, strings.Join(e.Actions, ", "))
+	}
+
+	var ok bool
+	if err := a.call(ctx, e.ref, atspiAction+".DoAction", int32(index)).Store(&ok); err != nil {
+		return "", fmt.Errorf("action %q failed: %w", e.Actions[index], err)
+	}
+	if !ok {
+		return "", fmt.Errorf("%s %q refused action %q", e.Role, e.Name, e.Actions[index])
+	}
+	return e.Actions[index], nil
+}
+
+// SetText replaces the text of an editable element through the AT-SPI
+// EditableText interface, without synthesizing input
+func (a *A11y) SetText(ctx context.Context, e *A11yElement, text string) error {
+	if !e.implements(atspiEditable) {
+		return fmt.Errorf("%s %q is not editable text", e.Role, e.Name)
+	}
+
+	var ok bool
+	if err := a.call(ctx, e.ref, atspiEditable+".SetTextContents", text).Store(&ok); err != nil {
+		return fmt.Errorf("failed to set text: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%s %q refused the text", e.Role, e.Name)
+	}
+	return nil
+}
+
+// SetValue sets the current value of an element such as a slider or spin
+// button through the AT-SPI Value interface, without synthesizing input
+func (a *A11y) SetValue(ctx context.Context, e *A11yElement, value float64) error {
+	if !e.implements(atspiValue) {
+		return fmt.Errorf("%s %q has no value", e.Role, e.Name)
+	}
+
+	var lo, hi float64
+	if a.property(ctx, e.ref, atspiValue, "MinimumValue", &lo) == nil &&
+		a.property(ctx, e.ref, atspiValue, "MaximumValue", &hi) == nil &&
+		lo < hi && (value < lo || value > hi) {
+		return fmt.Errorf("value %g is out of range for %s %q: must be between %g and %g", value, e.Role, e.Name, lo, hi)
+	}
+
+	err := a.call(ctx, e.ref, "org.freedesktop.DBus.Properties.Set", atspiValue, "CurrentValue", dbus.MakeVariant(value)).Err
+	if err != nil {
+		return fmt.Errorf("failed to set value: %w", err)
+	}
+	return nil
+}
+
+// indexFold returns the index of the first string equal to s ignoring case,
+// or -1
+func indexFold(list []string, s string) int {
+	for i, v := range list {
+		if strings.EqualFold(v, s) {
+			return i
+		}
+	}
+	return -1
+}
+
+// implements reports whether the element implements an AT-SPI interface
+func (e *A11yElement) implements(iface string) bool {
+	for _, i := range e.interfaces {
+		if i == iface {
+			return true
+		}
+	}
+	return false
+}
+
+// children returns the references of an element's children
+func (a *A11y) children(ctx context.Context, ref a11yRef) ([]a11yRef, error) {
+	var children []a11yRef
+	err := a.call(ctx, ref, atspiAccessible+".GetChildren").Store(&children)
+	return children, err
+}
+
+// property reads a D-Bus property of an element
+func (a *A11y) property(ctx context.Context, ref a11yRef, iface, name string, v any) error {
+	var variant dbus.Variant
+	if err := a.call(ctx, ref, "org.freedesktop.DBus.Properties.Get", iface, name).Store(&variant); err != nil {
+		return err
+	}
+	return variant.Store(v)
+}
+
+// call calls a method of an element with a11yCallTimeout
+func (a *A11y) call(ctx context.Context, ref a11yRef, method string, args ...any) *dbus.Call {
+	ctx, cancel := context.WithTimeout(ctx, a11yCallTimeout)
+	defer cancel()
+	return a.conn.Object(ref.Dest, ref.Path).CallWithContext(ctx, method, 0, args...)
+}
+
+// ConvertA11yBounds converts the bounds of elements and their descendants
+// from logical coordinates to the coordinate system's space
+func ConvertA11yBounds(elements []*A11yElement, cs CoordSystem) {
+	for _, e := range elements {
+		if b := e.Bounds; b != nil {
+			x0, y0 := cs.FromLogical(b.X, b.Y)
+			x1, y1 := cs.FromLogical(b.X+b.Width, b.Y+b.Height)
+			e.Bounds = &A11yBounds{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+		}
+		ConvertA11yBounds(e.Children, cs)
+	}
+}
@@ -0,0 +1,118 @@
+package automation
+
+import "testing"
+
+func TestMatchRole(t *testing.T) {
+	tests := []struct {
+		role, pattern string
+		want          bool
+	}{
+		{"push button", "push button", true},
+		{"push button", "button", true},
+		{"push button", "Button", true},
+		{"toggle button", "toggle_button", true},
+		{"toggle button", "toggle-button", true},
+		{"push button", "push", false},
+		{"push button", "utton", false},
+		{"text", "entry", false},
+		{"menu item", "item", true},
+		{"check menu item", "menu item", true},
+	}
+	for _, tt := range tests {
+		if got := matchRole(tt.role, tt.pattern); got != tt.want {
+			t.Errorf("matchRole(%q, %q) = %v, want %v", tt.role, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestMatchName(t *testing.T) {
+	tests := []struct {
+		name, pattern string
+		want          bool
+	}{
+		{"Save", "save", true},
+		{"Save", "Sav", false},
+		{"Save As…", "save*", true},
+		{"Save", "*", true},
+		{"", "*", true},
+		{"", "", true},
+		{"Save", "", false},
+		{"Save", "s?ve", true},
+		{"Save", "s?e", false},
+		{"Save As", "*as", true},
+		{"Save As", "*a*s*", true},
+		{"aaab", "*a*b", true},
+		{"aaab", "*a*c", false},
+		{"Open File", "open*file", true},
+		{"Open a recent file", "open*file", true},
+		{"Open File…", "open*file", false},
+		{"Größe", "grö?e", true},
+		{"Sub/Folder", "sub/*", true},
+		{"Sub/Folder", "*folder", true},
+		{"[draft] Note", "[draft]*", true},
+		{"d Note", "[a-z]*", false},
+		{`a\b`, `a\b`, true},
+		{"ab", `a\b`, false},
+	}
+	for _, tt := range tests {
+		if got := matchName(tt.name, tt.pattern); got != tt.want {
+			t.Errorf("matchName(%q, %q) = %v, want %v", tt.name, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestA11yQueryMatches(t *testing.T) {
+	e := &A11yElement{Role: "push button", Name: "Save As", States: []string{"enabled", "focused", "showing"}}
+
+	tests := []struct {
+		name  string
+		query A11yQuery
+		want  bool
+	}{
+		{"empty query", A11yQuery{}, true},
+		{"role", A11yQuery{Role: "button"}, true},
+		{"wrong role", A11yQuery{Role: "menu item"}, false},
+		{"name", A11yQuery{Name: "save*"}, true},
+		{"wrong name", A11yQuery{Name: "open*"}, false},
+		{"states", A11yQuery{States: []string{"Focused", "enabled"}}, true},
+		{"missing state", A11yQuery{States: []string{"focused", "checked"}}, false},
+		{"all filters", A11yQuery{Role: "push button", Name: "save as", States: []string{"showing"}}, true},
+		{"one filter fails", A11yQuery{Role: "push button", Name: "save as", States: []string{"pressed"}}, false},
+		{"app is not an element filter", A11yQuery{App: "other"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.matches(e); got != tt.want {
+				t.Errorf("%+v.matches() = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestA11yElementCenter(t *testing.T) {
+	tests := []struct {
+		name   string
+		bounds *A11yBounds
+		wantX  int
+		wantY  int
+		wantOK bool
+	}{
+		{"no bounds", nil, 0, 0, false},
+		{"element", &A11yBounds{X: 10, Y: 20, Width: 100, Height: 30}, 60, 35, true},
+		{"odd size", &A11yBounds{X: 0, Y: 0, Width: 5, Height: 3}, 2, 1, true},
+		{"zero width", &A11yBounds{X: 10, Y: 20, Width: 0, Height: 30}, 0, 0, false},
+		{"negative height", &A11yBounds{X: 10, Y: 20, Width: 10, Height: -1}, 0, 0, false},
+		{"left of the screen", &A11yBounds{X: -100, Y: 20, Width: 100, Height: 30}, 0, 0, false},
+		{"above the screen", &A11yBounds{X: 10, Y: -30, Width: 100, Height: 30}, 0, 0, false},
+		{"partly on the screen", &A11yBounds{X: -50, Y: -10, Width: 100, Height: 30}, 0, 5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &A11yElement{Bounds: tt.bounds}
+			x, y, ok := e.Center()
+			if x != tt.wantX || y != tt.wantY || ok != tt.wantOK {
+				t.Errorf("Center() = %d, %d, %v, want %d, %d, %v", x, y, ok, tt.wantX, tt.wantY, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package automation
+
+import (
+	"context"
+	"image"
+	"testing"
+)
+
+// fakeBackend is a backend with a fixed screen for tests; methods that are not
+// overridden panic
+type fakeBackend struct {
+	Backend
+	width, height int
+	scale         float64
+	window        image.Rectangle
+	windowErr     error
+	x, y          int
+	// screen holds the physical pixels returned by CaptureScreen
+	screen *image.RGBA
+}
+
+func (f *fakeBackend) ScreenSize(ctx context.Context) (int, int, error) {
+	return f.width, f.height, nil
+}
+
+func (f *fakeBackend) ScaleFactor(ctx context.Context) (float64, error) {
+	return f.scale, nil
+}
+
+func (f *fakeBackend) MousePosition(ctx context.Context) (int, int, error) {
+	return f.x, f.y, nil
+}
+
+func (f *fakeBackend) ActiveWindowBounds(ctx context.Context) (image.Rectangle, error) {
+	return f.window, f.windowErr
+}
+
+// CaptureScreen returns the physical pixels under a logical region, clipped to
+// the screen like the real backends do
+func (f *fakeBackend) CaptureScreen(ctx context.Context, region image.Rectangle) (image.Image, error) {
+	bounds := image.Rect(0, 0, f.width, f.height)
+	if region.Empty() {
+		region = bounds
+	}
+	region = region.Intersect(bounds)
+	physical := image.Rect(
+		int(float64(region.Min.X)*f.scale), int(float64(region.Min.Y)*f.scale),
+		int(float64(region.Max.X)*f.scale), int(float64(region.Max.Y)*f.scale),
+	)
+	return f.screen.SubImage(physical), nil
+}
+
+// useFakeBackend installs a fake backend for the duration of a test
+func useFakeBackend(t *testing.T, f *fakeBackend) {
+	t.Helper()
+	old := CurrentBackend()
+	SetBackend(f)
+	t.Cleanup(func() { SetBackend(old) })
+}
+
+func TestParseCoordSpace(t *testing.T) {
+	useFakeBackend(t, &fakeBackend{width: 1440, height: 900, scale: 2})
+
+	tests := []struct {
+		spec    string
+		space   CoordSpace
+		w, h    int
+		wantErr bool
+	}{
+		{spec: "", space: CoordLogical, w: 1440, h: 900},
+		{spec: "logical", space: CoordLogical, w: 1440, h: 900},
+		{spec: " Physical ", space: CoordPhysical, w: 2880, h: 1800},
+		{spec: "screenshot", space: CoordScreenshot, w: 2880, h: 1800},
+		{spec: "screenshot:1280x800", space: CoordScreenshot, w: 1280, h: 800},
+		{spec: "screenshot:1280X800", space: CoordScreenshot, w: 1280, h: 800},
+		{spec: "screenshot:1280", wantErr: true},
+		{spec: "screenshot:0x800", wantErr: true},
+		{spec: "screenshot:axb", wantErr: true},
+		{spec: "logical:100x100", wantErr: true},
+		{spec: "physical:100x100", wantErr: true},
+		{spec: "pixels", wantErr: true},
+	}
+	for _, tt := range tests {
+		cs, err := ParseCoordSpace(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseCoordSpace(%q) succeeded, want error", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseCoordSpace(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if w, h := cs.Size(); cs.Space != tt.space || w != tt.w || h != tt.h {
+			t.Errorf("ParseCoordSpace(%q) = %s %dx%d, want %s %dx%d", tt.spec, cs.Space, w, h, tt.space, tt.w, tt.h)
+		}
+	}
+}
+
+func TestCoordSystemConvert(t *testing.T) {
+	cs := CoordSystem{LogicalWidth: 1440, LogicalHeight: 900, Scale: 2, ScreenshotWidth: 1280, ScreenshotHeight: 800}
+
+	tests := []struct {
+		x, y     int
+		from, to CoordSpace
+		wantX    int
+		wantY    int
+	}{
+		{100, 50, CoordLogical, CoordLogical, 100, 50},
+		{100, 50, CoordLogical, CoordPhysical, 200, 100},
+		{200, 100, CoordPhysical, CoordLogical, 100, 50},
+		{1440, 900, CoordLogical, CoordScreenshot, 1280, 800},
+		{640, 400, CoordScreenshot, CoordLogical, 720, 450},
+		{640, 400, CoordScreenshot, CoordPhysical, 1440, 900},
+		{2880, 1800, CoordPhysical, CoordScreenshot, 1280, 800},
+		// Odd physical pixels round to the nearest logical unit
+		{3, 3, CoordPhysical, CoordLogical, 2, 2},
+	}
+	for _, tt := range tests {
+		x, y := cs.Convert(tt.x, tt.y, tt.from, tt.to)
+		if x != tt.wantX || y != tt.wantY {
+			t.Errorf("Convert(%d, %d, %s, %s) = (%d, %d), want (%d, %d)",
+				tt.x, tt.y, tt.from, tt.to, x, y, tt.wantX, tt.wantY)
+		}
+	}
+}
+
+func TestCoordSystemUnscaledScreenshot(t *testing.T) {
+	// Without a screenshot size the space is the physical screen
+	cs := CoordSystem{Space: CoordScreenshot, LogicalWidth: 1440, LogicalHeight: 900, Scale: 2}
+	if x, y := cs.FromLogical(10, 20); x != 20 || y != 40 {
+		t.Errorf("FromLogical(10, 20) = (%d, %d), want (20, 40)", x, y)
+	}
+}
@@ -0,0 +1,139 @@
+package automation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// namedEvdevKeys maps the key names used throughout this package to Linux
+// evdev key codes, which the uinput backend sends instead of keysyms
+var namedEvdevKeys = map[string]uint16{
+	"enter":       28,
+	"return":      28,
+	"tab":         15,
+	"space":       57,
+	"backspace":   14,
+	"esc":         1,
+	"escape":      1,
+	"delete":      111,
+	"insert":      110,
+	"home":        102,
+	"end":         107,
+	"pageup":      104,
+	"pagedown":    109,
+	"left":        105,
+	"up":          103,
+	"right":       106,
+	"down":        108,
+	"capslock":    58,
+	"menu":        127,
+	"printscreen": 99,
+	"shift":       42,
+	"lshift":      42,
+	"rshift":      54,
+	"ctrl":        29,
+	"control":     29,
+	"lctrl":       29,
+	"rctrl":       97,
+	"alt":         56,
+	"lalt":        56,
+	"ralt":        100,
+	"cmd":         125,
+	"command":     125,
+	"super":       125,
+	"win":         125,
+}
+
+// evdevKeyRows lists the characters of a US keyboard by evdev key code, unshifted
+// and shifted
+var evdevKeyRows = []struct {
+	first          uint16
+	plain, shifted string
+}{
+	{2, "1234567890-=", "!@#$%^&*()_+"},
+	{16, "qwertyuiop[]", "QWERTYUIOP{}"},
+	{30, "asdfghjkl;'`", "ASDFGHJKL:\"~"},
+	{43, "\\zxcvbnm,./", "|ZXCVBNM<>?"},
+}
+
+// evdevKey is a key code and whether shift is needed to type a character with it
+type evdevKey struct {
+	code  uint16
+	shift bool
+}
+
+// evdevChars maps the characters of a US keyboard to their keys
+var evdevChars = func() map[rune]evdevKey {
+	chars := map[rune]evdevKey{
+		' ':  {code: 57},
+		'\n': {code: 28},
+		'\r': {code: 28},
+		'\t': {code: 15},
+		'\b': {code: 14},
+	}
+	for _, row := range evdevKeyRows {
+		shifted := []rune(row.shifted)
+		for i, r := range []rune(row.plain) {
+			chars[r] = evdevKey{code: row.first + uint16(i)}
+			chars[shifted[i]] = evdevKey{code: row.first + uint16(i), shift: true}
+		}
+	}
+	return chars
+}()
+
+// evdevKeyForName returns the key of a key name such as "enter", "f5", "a" or
+// "ctrl". Single characters keep their case, so "A" and "?" need shift.
+func evdevKeyForName(key string) (evdevKey, error) {
+	if r, size := utf8.DecodeRuneInString(key); size == len(key) {
+		if k, ok := evdevChars[r]; ok {
+			return k, nil
+		}
+	}
+
+	name := strings.ToLower(key)
+	if code, ok := namedEvdevKeys[name]; ok {
+		return evdevKey{code: code}, nil
+	}
+
+	var n int
+	if _, err := fmt.Sscanf(name, "f%d", &n); err == nil && name == fmt.Sprintf("f%d", n) {
+		switch {
+		case n >= 1 && n <= 10:
+			return evdevKey{code: 59 + uint16(n-1)}, nil
+		case n == 11 || n == 12:
+			return evdevKey{code: 87 + uint16(n-11)}, nil
+		case n >= 13 && n <= 24:
+			return evdevKey{code: 183 + uint16(n-13)}, nil
+		}
+	}
+	return evdevKey{}, fmt.Errorf("unknown key '%s'", key)
+}
+
+// evdevComboCodes returns the key codes to press in order for a key
+// combination. Keys that need shift, such as "A" or "?", get shift pressed
+// first unless the combination already holds a shift key.
+func evdevComboCodes(keys []evdevKey) []uint16 {
+	shift, lshift, rshift := namedEvdevKeys["shift"], namedEvdevKeys["lshift"], namedEvdevKeys["rshift"]
+
+	codes := make([]uint16, 0, len(keys)+1)
+	needShift, shiftHeld := false, false
+	for _, k := range keys {
+		needShift = needShift || k.shift
+		shiftHeld = shiftHeld || k.code == lshift || k.code == rshift
+		codes = append(codes, k.code)
+	}
+	if needShift && !shiftHeld {
+		codes = append([]uint16{shift}, codes...)
+	}
+	return codes
+}
+
+// evdevKeyForRune returns the key typing the character r with a US layout
+func evdevKeyForRune(r rune) (evdevKey, error) {
+	k, ok := evdevChars[r]
+	if !ok {
+		return evdevKey{}, fmt.Errorf("cannot type %q: only characters of a US keyboard layout are supported", r)
+	}
+	return k, nil
+}
@@ -0,0 +1,81 @@
+package automation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// namedKeysyms maps the key names used throughout this package to X11 keysyms,
+// which VNC and X11 backends send instead of platform key codes
+var namedKeysyms = map[string]uint32{
+	"enter":       0xff0d,
+	"return":      0xff0d,
+	"tab":         0xff09,
+	"space":       0x0020,
+	"backspace":   0xff08,
+	"esc":         0xff1b,
+	"escape":      0xff1b,
+	"delete":      0xffff,
+	"insert":      0xff63,
+	"home":        0xff50,
+	"end":         0xff57,
+	"pageup":      0xff55,
+	"pagedown":    0xff56,
+	"left":        0xff51,
+	"up":          0xff52,
+	"right":       0xff53,
+	"down":        0xff54,
+	"capslock":    0xffe5,
+	"menu":        0xff67,
+	"printscreen": 0xff61,
+	"shift":       0xffe1,
+	"lshift":      0xffe1,
+	"rshift":      0xffe2,
+	"ctrl":        0xffe3,
+	"control":     0xffe3,
+	"lctrl":       0xffe3,
+	"rctrl":       0xffe4,
+	"alt":         0xffe9,
+	"lalt":        0xffe9,
+	"ralt":        0xffea,
+	"cmd":         0xffeb,
+	"command":     0xffeb,
+	"super":       0xffeb,
+	"win":         0xffeb,
+}
+
+// keysymForName returns the X11 keysym of a key name such as "enter", "f5",
+// "a" or "ctrl"
+func keysymForName(key string) (uint32, error) {
+	name := strings.ToLower(key)
+	if sym, ok := namedKeysyms[name]; ok {
+		return sym, nil
+	}
+
+	var n int
+	if _, err := fmt.Sscanf(name, "f%d", &n); err == nil && n >= 1 && n <= 24 && name == fmt.Sprintf("f%d", n) {
+		return 0xffbe + uint32(n-1), nil
+	}
+
+	if r, size := utf8.DecodeRuneInString(key); size == len(key) && r != utf8.RuneError {
+		return keysymForRune(r), nil
+	}
+	return 0, fmt.Errorf("unknown key '%s'", key)
+}
+
+// keysymForRune returns the X11 keysym typing the character r
+func keysymForRune(r rune) uint32 {
+	switch {
+	case r == '\n' || r == '\r':
+		return namedKeysyms["enter"]
+	case r == '\t':
+		return namedKeysyms["tab"]
+	case r == '\b':
+		return namedKeysyms["backspace"]
+	case r < 0x100:
+		// Latin-1 characters are their own keysym
+		return uint32(r)
+	}
+	return 0x01000000 | uint32(r)
+}
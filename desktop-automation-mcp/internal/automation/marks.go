@@ -0,0 +1,359 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// maxMarks bounds the number of marks drawn on a screenshot
+	maxMarks = 150
+	// edgeSampleWidth is the width edge detection downsamples screenshots to
+	edgeSampleWidth = 960
+	// edgeThreshold is the brightness difference between neighbouring samples
+	// that counts as an edge
+	edgeThreshold = 40
+	// labelWidth is the screenshot width up to which labels are drawn at the
+	// font's size; wider screenshots scale them up to stay legible
+	labelWidth = 1600
+)
+
+// markColors are the colours marks cycle through, chosen to stay distinct
+// from each other and readable with white text
+var markColors = []color.RGBA{
+	{230, 25, 75, 255},
+	{0, 130, 200, 255},
+	{60, 150, 60, 255},
+	{145, 30, 180, 255},
+	{245, 130, 48, 255},
+	{0, 128, 128, 255},
+	{170, 110, 40, 255},
+	{128, 0, 0, 255},
+}
+
+// MarkSource selects how UI elements are detected for marks
+type MarkSource int
+
+const (
+	// MarksAuto uses the accessibility tree and falls back to edge detection
+	MarksAuto MarkSource = iota
+	// MarksA11y uses interactive elements of the accessibility tree
+	MarksA11y
+	// MarksEdges detects elements from edges in the screenshot
+	MarksEdges
+)
+
+// ParseMarkSource parses a mark source name: "auto", "a11y" or "edges"
+func ParseMarkSource(s string) (MarkSource, error) {
+	switch strings.ToLower(s) {
+	case "auto", "":
+		return MarksAuto, nil
+	case "a11y":
+		return MarksA11y, nil
+	case "edges":
+		return MarksEdges, nil
+	}
+	return MarksAuto, fmt.Errorf("invalid mark source '%s': must be auto, a11y or edges", s)
+}
+
+// Mark is a numbered marker on a UI element of a screenshot
+type Mark struct {
+	ID int `json:"id"`
+	// X and Y are the centre of the element
+	X int `json:"x"`
+	Y int `json:"y"`
+	// Bounds encloses the element
+	Bounds image.Rectangle `json:"-"`
+	// Label describes the element when it came from the accessibility tree
+	Label string `json:"label,omitempty"`
+}
+
+// FindMarks detects UI elements to mark on a screenshot of the whole screen,
+// numbered in reading order. Marks are in logical coordinates.
+func FindMarks(ctx context.Context, img image.Image, source MarkSource) ([]Mark, error) {
+	w, h, err := CurrentBackend().ScreenSize(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get screen size: %w", err)
+	}
+	screen := image.Rect(0, 0, w, h)
+
+	var marks []Mark
+	if source != MarksEdges {
+		marks, err = a11yMarks(ctx, screen)
+		if err != nil && source == MarksA11y {
+			return nil, err
+		}
+	}
+	if len(marks) == 0 && source != MarksA11y {
+		marks = edgeMarks(img, screen)
+	}
+
+	sort.SliceStable(marks, func(i, j int) bool {
+		a, b := marks[i].Bounds.Min, marks[j].Bounds.Min
+		// Elements starting within a few units of each other share a row
+		if rowA, rowB := a.Y/16, b.Y/16; rowA != rowB {
+			return rowA < rowB
+		}
+		return a.X < b.X
+	})
+	if len(marks) > maxMarks {
+		marks = marks[:maxMarks]
+	}
+	for i := range marks {
+		marks[i].ID = i + 1
+		center := marks[i].Bounds.Min.Add(marks[i].Bounds.Max).Div(2)
+		marks[i].X, marks[i].Y = center.X, center.Y
+	}
+	return marks, nil
+}
+
+// ConvertMarks converts marks from logical coordinates to the coordinate
+// system's space
+func ConvertMarks(marks []Mark, cs CoordSystem) []Mark {
+	converted := make([]Mark, len(marks))
+	for i, m := range marks {
+		m.X, m.Y = cs.FromLogical(m.X, m.Y)
+		x0, y0 := cs.FromLogical(m.Bounds.Min.X, m.Bounds.Min.Y)
+		x1, y1 := cs.FromLogical(m.Bounds.Max.X, m.Bounds.Max.Y)
+		m.Bounds = image.Rect(x0, y0, x1, y1)
+		converted[i] = m
+	}
+	return converted
+}
+
+// a11yMarks returns the interactive elements of the accessibility tree that
+// are on the screen
+func a11yMarks(ctx context.Context, screen image.Rectangle) ([]Mark, error) {
+	a11y, err := OpenA11y(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer a11y.Close()
+
+	elements, err := a11y.Find(ctx, A11yQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	var marks []Mark
+	seen := map[image.Rectangle]bool{}
+	for _, e := range elements {
+		if e.Bounds == nil || (len(e.Actions) == 0 && !e.HasState("editable")) {
+			continue
+		}
+		b := e.Bounds
+		r := image.Rect(b.X, b.Y, b.X+b.Width, b.Y+b.Height).Intersect(screen)
+		// Skip elements covering much of the screen, such as whole windows
+		if r.Empty() || seen[r] || r.Dx()*r.Dy() > screen.Dx()*screen.Dy()/4 {
+			continue
+		}
+		seen[r] = true
+
+		label := e.Role
+		if e.Name != "" {
+			label += " " + strconv.Quote(e.Name)
+		}
+		marks = append(marks, Mark{Bounds: r, Label: label})
+	}
+	return marks, nil
+}
+
+// edgeMarks detects UI elements as clusters of edges in a screenshot of the
+// whole screen. Nearby edges are joined so the letters of a word or the
+// outline and text of a button form one element.
+func edgeMarks(img image.Image, screen image.Rectangle) []Mark {
+	b := img.Bounds()
+	step := max(1, b.Dx()/edgeSampleWidth)
+	w, h := b.Dx()/step, b.Dy()/step
+	if w < 2 || h < 2 {
+		return nil
+	}
+
+	gray := make([]int, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, bl, _ := img.At(b.Min.X+x*step, b.Min.Y+y*step).RGBA()
+			gray[y*w+x] = int(299*r+587*g+114*bl) / 1000 >> 8
+		}
+	}
+
+	// Mark edges and widen them, more horizontally than vertically so words
+	// join up without merging lines of text
+	const joinX, joinY = 3, 1
+	edges := make([]bool, w*h)
+	for y := 0; y < h-1; y++ {
+		for x := 0; x < w-1; x++ {
+			i := y*w + x
+			if abs(gray[i]-gray[i+1])+abs(gray[i]-gray[i+w]) <= edgeThreshold {
+				continue
+			}
+			for dy := max(0, y-joinY); dy <= min(h-1, y+joinY); dy++ {
+				for dx := max(0, x-joinX); dx <= min(w-1, x+joinX); dx++ {
+					edges[dy*w+dx] = true
+				}
+			}
+		}
+	}
+
+	// Screen units per sample
+	sx := float64(screen.Dx()) / float64(w)
+	sy := float64(screen.Dy()) / float64(h)
+
+	var marks []Mark
+	var stack []int
+	for start, ok := range edges {
+		if !ok {
+			continue
+		}
+
+		edges[start] = false
+		stack = append(stack[:0], start)
+		bounds := image.Rectangle{}
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			x, y := i%w, i/w
+			bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+			for _, n := range [4]int{i - 1, i + 1, i - w, i + w} {
+				if n < 0 || n >= len(edges) || !edges[n] || (n == i-1 && x == 0) || (n == i+1 && x == w-1) {
+					continue
+				}
+				edges[n] = false
+				stack = append(stack, n)
+			}
+		}
+
+		// Skip specks and large areas such as panels, photos and windows
+		if bounds.Dx() < 2*joinX+3 || bounds.Dy() < 2*joinY+3 || bounds.Dx() > w/3 || bounds.Dy() > h/4 {
+			continue
+		}
+		// Remove the widening again
+		bounds = bounds.Inset(1)
+		marks = append(marks, Mark{Bounds: image.Rect(
+			int(float64(bounds.Min.X)*sx), int(float64(bounds.Min.Y)*sy),
+			int(float64(bounds.Max.X)*sx), int(float64(bounds.Max.Y)*sy),
+		)})
+	}
+
+	// The label inside a button's outline is part of the button
+	var outer []Mark
+	for i, m := range marks {
+		inside := false
+		for j, o := range marks {
+			if i != j && m.Bounds.In(o.Bounds) && m.Bounds != o.Bounds {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			outer = append(outer, m)
+		}
+	}
+	return outer
+}
+
+// DrawGrid draws lines every step units over a screenshot of a screen that is
+// width x height units large, labelled with their coordinates along the top
+// and left edges
+func DrawGrid(img *image.RGBA, width, height, step int) {
+	if step <= 0 || width <= 0 || height <= 0 {
+		return
+	}
+	b := img.Bounds()
+	sx := float64(b.Dx()) / float64(width)
+	sy := float64(b.Dy()) / float64(height)
+	scale := labelScale(img)
+	line := color.RGBA{255, 0, 255, 160}
+	label := color.RGBA{128, 0, 128, 255}
+
+	for v := step; v < width; v += step {
+		x := b.Min.X + int(float64(v)*sx)
+		draw.Draw(img, image.Rect(x, b.Min.Y, x+scale, b.Max.Y), image.NewUniform(line), image.Point{}, draw.Over)
+		drawLabel(img, image.Pt(x+scale, b.Min.Y), strconv.Itoa(v), label, scale)
+	}
+	for v := step; v < height; v += step {
+		y := b.Min.Y + int(float64(v)*sy)
+		draw.Draw(img, image.Rect(b.Min.X, y, b.Max.X, y+scale), image.NewUniform(line), image.Point{}, draw.Over)
+		drawLabel(img, image.Pt(b.Min.X, y+scale), strconv.Itoa(v), label, scale)
+	}
+}
+
+// DrawMarks outlines the marks and draws their numbers over a screenshot of a
+// screen that is width x height units large, the units the marks are in
+func DrawMarks(img *image.RGBA, width, height int, marks []Mark) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	b := img.Bounds()
+	sx := float64(b.Dx()) / float64(width)
+	sy := float64(b.Dy()) / float64(height)
+	scale := labelScale(img)
+
+	for i, m := range marks {
+		c := markColors[i%len(markColors)]
+		r := image.Rect(
+			b.Min.X+int(float64(m.Bounds.Min.X)*sx), b.Min.Y+int(float64(m.Bounds.Min.Y)*sy),
+			b.Min.X+int(float64(m.Bounds.Max.X)*sx), b.Min.Y+int(float64(m.Bounds.Max.Y)*sy),
+		)
+		for _, edge := range []image.Rectangle{
+			image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+scale),
+			image.Rect(r.Min.X, r.Max.Y-scale, r.Max.X, r.Max.Y),
+			image.Rect(r.Min.X, r.Min.Y, r.Min.X+scale, r.Max.Y),
+			image.Rect(r.Max.X-scale, r.Min.Y, r.Max.X, r.Max.Y),
+		} {
+			draw.Draw(img, edge, image.NewUniform(c), image.Point{}, draw.Src)
+		}
+		drawLabel(img, r.Min, strconv.Itoa(m.ID), c, scale)
+	}
+}
+
+// labelScale returns the factor labels and lines are enlarged by on an image
+func labelScale(img *image.RGBA) int {
+	return max(1, (img.Bounds().Dx()+labelWidth/2)/labelWidth)
+}
+
+// drawLabel draws white text on a coloured box with its top-left corner at p,
+// moved inside the image if needed, enlarged by scale
+func drawLabel(img *image.RGBA, p image.Point, text string, bg color.RGBA, scale int) {
+	face := basicfont.Face7x13
+	label := image.NewRGBA(image.Rect(0, 0, font.MeasureString(face, text).Ceil()+4, face.Height+2))
+	draw.Draw(label, label.Rect, image.NewUniform(bg), image.Point{}, draw.Src)
+	d := font.Drawer{Dst: label, Src: image.White, Face: face, Dot: fixed.P(2, face.Ascent+1)}
+	d.DrawString(text)
+
+	r := image.Rectangle{Min: p, Max: p.Add(label.Rect.Size().Mul(scale))}
+	b := img.Bounds()
+	if r.Max.X > b.Max.X {
+		r = r.Sub(image.Pt(r.Max.X-b.Max.X, 0))
+	}
+	if r.Max.Y > b.Max.Y {
+		r = r.Sub(image.Pt(0, r.Max.Y-b.Max.Y))
+	}
+	draw.NearestNeighbor.Scale(img, r, label, label.Rect, draw.Src, nil)
+}
+
+// ToRGBA returns a copy of an image as RGBA with its origin at (0, 0), for
+// drawing overlays on a capture
+func ToRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Rect, img, b.Min, draw.Src)
+	return out
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
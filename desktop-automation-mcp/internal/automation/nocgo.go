@@ -0,0 +1,157 @@
+//go:build !cgo
+
+package automation
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"sync"
+)
+
+// defaultBackend returns the backend used until SetBackend is called. robotgo
+// needs cgo, so builds without it drive the X11 display from $DISPLAY.
+func defaultBackend() Backend {
+	return &lazyBackend{open: func(ctx context.Context) (Backend, error) {
+		return DialX11(ctx, "")
+	}}
+}
+
+// openRobotgo fails because robotgo is not compiled in
+func openRobotgo() (Backend, error) {
+	return nil, fmt.Errorf("the robotgo backend is not available in builds without cgo, use x11 instead")
+}
+
+// lazyBackend opens its backend on first use, so merely loading the package
+// does not require a display
+type lazyBackend struct {
+	open func(ctx context.Context) (Backend, error)
+
+	mu      sync.Mutex
+	backend Backend
+}
+
+func (l *lazyBackend) get(ctx context.Context) (Backend, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.backend == nil {
+		b, err := l.open(ctx)
+		if err != nil {
+			return nil, err
+		}
+		l.backend = b
+	}
+	return l.backend, nil
+}
+
+func (l *lazyBackend) MoveMouse(ctx context.Context, x, y int) error {
+	b, err := l.get(ctx)
+	if err != nil {
+		return err
+	}
+	return b.MoveMouse(ctx, x, y)
+}
+
+func (l *lazyBackend) MouseToggle(ctx context.Context, button string, down bool) error {
+	b, err := l.get(ctx)
+	if err != nil {
+		return err
+	}
+	return b.MouseToggle(ctx, button, down)
+}
+
+func (l *lazyBackend) Click(ctx context.Context, button string, double bool) error {
+	b, err := l.get(ctx)
+	if err != nil {
+		return err
+	}
+	return b.Click(ctx, button, double)
+}
+
+func (l *lazyBackend) Scroll(ctx context.Context, dx, dy int) error {
+	b, err := l.get(ctx)
+	if err != nil {
+		return err
+	}
+	return b.Scroll(ctx, dx, dy)
+}
+
+func (l *lazyBackend) KeyTap(ctx context.Context, key string, modifiers ...string) error {
+	b, err := l.get(ctx)
+	if err != nil {
+		return err
+	}
+	return b.KeyTap(ctx, key, modifiers...)
+}
+
+func (l *lazyBackend) KeyToggle(ctx context.Context, key string, down bool) error {
+	b, err := l.get(ctx)
+	if err != nil {
+		return err
+	}
+	return b.KeyToggle(ctx, key, down)
+}
+
+func (l *lazyBackend) TypeString(ctx context.Context, text string) error {
+	b, err := l.get(ctx)
+	if err != nil {
+		return err
+	}
+	return b.TypeString(ctx, text)
+}
+
+func (l *lazyBackend) MousePosition(ctx context.Context) (int, int, error) {
+	b, err := l.get(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return b.MousePosition(ctx)
+}
+
+func (l *lazyBackend) ScreenSize(ctx context.Context) (int, int, error) {
+	b, err := l.get(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return b.ScreenSize(ctx)
+}
+
+func (l *lazyBackend) ScaleFactor(ctx context.Context) (float64, error) {
+	b, err := l.get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return b.ScaleFactor(ctx)
+}
+
+func (l *lazyBackend) CaptureScreen(ctx context.Context, region image.Rectangle) (image.Image, error) {
+	b, err := l.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return b.CaptureScreen(ctx, region)
+}
+
+func (l *lazyBackend) ActiveWindowBounds(ctx context.Context) (image.Rectangle, error) {
+	b, err := l.get(ctx)
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	return b.ActiveWindowBounds(ctx)
+}
+
+func (l *lazyBackend) Monitors(ctx context.Context) ([]image.Rectangle, error) {
+	b, err := l.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if lister, ok := b.(MonitorLister); ok {
+		return lister.Monitors(ctx)
+	}
+	w, h, err := b.ScreenSize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []image.Rectangle{image.Rect(0, 0, w, h)}, nil
+}
@@ -0,0 +1,171 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ColorBlob is a connected group of pixels matching a searched colour
+type ColorBlob struct {
+	// X and Y are the centroid of the blob in logical coordinates
+	X, Y int
+	// Bounds encloses the blob in logical coordinates
+	Bounds image.Rectangle
+	// Pixels is the number of matching physical pixels in the blob
+	Pixels int
+}
+
+// ParseColor parses a colour given as #rrggbb, #rgb or r,g,b
+func ParseColor(s string) (color.RGBA, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, ",") {
+		parts := strings.Split(s, ",")
+		if len(parts) != 3 {
+			return color.RGBA{}, fmt.Errorf("invalid color '%s': must be #rrggbb, #rgb or r,g,b", s)
+		}
+		var c [3]uint8
+		for i, p := range parts {
+			v, err := strconv.ParseUint(strings.TrimSpace(p), 10, 8)
+			if err != nil {
+				return color.RGBA{}, fmt.Errorf("invalid color '%s': components must be 0-255", s)
+			}
+			c[i] = uint8(v)
+		}
+		return color.RGBA{c[0], c[1], c[2], 255}, nil
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color '%s': must be #rrggbb, #rgb or r,g,b", s)
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, nil
+}
+
+// HexColor formats a colour as #rrggbb
+func HexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// PixelAt returns the colour of the screen at a logical position. On scaled
+// displays this is the top-left physical pixel of the logical unit.
+func PixelAt(ctx context.Context, x, y int) (color.RGBA, error) {
+	img, err := CaptureImage(ctx, image.Rect(x, y, x+1, y+1))
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	b := img.Bounds()
+	if b.Empty() {
+		return color.RGBA{}, fmt.Errorf("position (%d, %d) is outside the screen", x, y)
+	}
+	return color.RGBAModel.Convert(img.At(b.Min.X, b.Min.Y)).(color.RGBA), nil
+}
+
+// FindColor searches a logical region of the screen, or the whole screen if
+// the region is empty, for pixels whose channels all differ from target by
+// at most tolerance. Matching pixels that touch, including diagonally, form a
+// blob. Blobs are returned largest first.
+func FindColor(ctx context.Context, region image.Rectangle, target color.RGBA, tolerance uint8) ([]ColorBlob, error) {
+	// Backends clip captures to the screen, so clip the region the same way
+	// before mapping captured pixels back to it
+	w, h, err := CurrentBackend().ScreenSize(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get screen size: %w", err)
+	}
+	screen := image.Rect(0, 0, w, h)
+	if region.Empty() {
+		region = screen
+	}
+	region = region.Intersect(screen)
+	if region.Empty() {
+		return nil, nil
+	}
+
+	captured, err := CaptureImage(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, captured.Bounds().Dx(), captured.Bounds().Dy()))
+	draw.Draw(img, img.Rect, captured, captured.Bounds().Min, draw.Src)
+	w, h = img.Rect.Dx(), img.Rect.Dy()
+	if w == 0 || h == 0 {
+		return nil, nil
+	}
+
+	matches := make([]bool, w*h)
+	for i := range matches {
+		p := img.Pix[i*4 : i*4+3 : i*4+3]
+		matches[i] = near(p[0], target.R, tolerance) && near(p[1], target.G, tolerance) && near(p[2], target.B, tolerance)
+	}
+
+	// Physical pixels of the capture per logical unit of the region
+	sx := float64(w) / float64(region.Dx())
+	sy := float64(h) / float64(region.Dy())
+
+	var blobs []ColorBlob
+	var stack []int
+	for start, ok := range matches {
+		if !ok {
+			continue
+		}
+
+		// Flood fill the blob, clearing its pixels as they are visited
+		matches[start] = false
+		stack = append(stack[:0], start)
+		var sumX, sumY, n int
+		bounds := image.Rectangle{}
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			x, y := i%w, i/w
+			sumX += x
+			sumY += y
+			n++
+			bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || nx >= w || ny >= h || !matches[ny*w+nx] {
+						continue
+					}
+					matches[ny*w+nx] = false
+					stack = append(stack, ny*w+nx)
+				}
+			}
+		}
+
+		cx := (float64(sumX)/float64(n) + 0.5) / sx
+		cy := (float64(sumY)/float64(n) + 0.5) / sy
+		blobs = append(blobs, ColorBlob{
+			X: region.Min.X + int(cx),
+			Y: region.Min.Y + int(cy),
+			Bounds: image.Rect(
+				region.Min.X+int(float64(bounds.Min.X)/sx), region.Min.Y+int(float64(bounds.Min.Y)/sy),
+				region.Min.X+int(math.Ceil(float64(bounds.Max.X)/sx)), region.Min.Y+int(math.Ceil(float64(bounds.Max.Y)/sy)),
+			),
+			Pixels: n,
+		})
+	}
+
+	sort.SliceStable(blobs, func(i, j int) bool { return blobs[i].Pixels > blobs[j].Pixels })
+	return blobs, nil
+}
+
+// near reports whether two colour components differ by at most tolerance
+func near(a, b, tolerance uint8) bool {
+	if a > b {
+		return a-b <= tolerance
+	}
+	return b-a <= tolerance
+}
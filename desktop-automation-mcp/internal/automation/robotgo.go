@@ -0,0 +1,128 @@
+//go:build cgo
+
+package automation
+
+import (
+	"context"
+	"fmt"
+	"image"
+
+	"github.com/go-vgo/robotgo"
+)
+
+// robotgoBackend drives the local desktop through robotgo
+type robotgoBackend struct{}
+
+// defaultBackend returns the backend used until SetBackend is called
+func defaultBackend() Backend {
+	return robotgoBackend{}
+}
+
+// openRobotgo returns the robotgo backend
+func openRobotgo() (Backend, error) {
+	return robotgoBackend{}, nil
+}
+
+func (robotgoBackend) MoveMouse(ctx context.Context, x, y int) error {
+	robotgo.Move(x, y)
+	return nil
+}
+
+func (robotgoBackend) MouseToggle(ctx context.Context, button string, down bool) error {
+	if down {
+		return robotgo.Toggle(button)
+	}
+	return robotgo.Toggle(button, "up")
+}
+
+func (robotgoBackend) Click(ctx context.Context, button string, double bool) error {
+	robotgo.Click(button, double)
+	return nil
+}
+
+func (robotgoBackend) Scroll(ctx context.Context, dx, dy int) error {
+	// robotgo scrolls up for positive y, the backend contract is positive is down
+	robotgo.Scroll(dx, -dy)
+	return nil
+}
+
+func (robotgoBackend) KeyTap(ctx context.Context, key string, modifiers ...string) error {
+	if len(modifiers) == 0 {
+		return robotgo.KeyTap(key)
+	}
+
+	// Convert []string to []interface{} for robotgo.KeyTap
+	args := make([]interface{}, len(modifiers))
+	for i, modifier := range modifiers {
+		args[i] = modifier
+	}
+	return robotgo.KeyTap(key, args...)
+}
+
+func (robotgoBackend) KeyToggle(ctx context.Context, key string, down bool) error {
+	if down {
+		return robotgo.KeyToggle(key, "down")
+	}
+	return robotgo.KeyToggle(key, "up")
+}
+
+func (robotgoBackend) TypeString(ctx context.Context, text string) error {
+	robotgo.TypeStr(text)
+	return nil
+}
+
+func (robotgoBackend) MousePosition(ctx context.Context) (int, int, error) {
+	x, y := robotgo.GetMousePos()
+	return x, y, nil
+}
+
+func (robotgoBackend) ScreenSize(ctx context.Context) (int, int, error) {
+	w, h := robotgo.GetScreenSize()
+	return w, h, nil
+}
+
+// Monitors returns the bounds of the displays with the main display first
+func (robotgoBackend) Monitors(ctx context.Context) ([]image.Rectangle, error) {
+	n := robotgo.DisplaysNum()
+	monitors := make([]image.Rectangle, 0, n)
+	for i := 0; i < n; i++ {
+		x, y, w, h := robotgo.GetDisplayBounds(i)
+		monitors = append(monitors, image.Rect(x, y, x+w, y+h))
+	}
+	if len(monitors) == 0 {
+		w, h := robotgo.GetScreenSize()
+		monitors = append(monitors, image.Rect(0, 0, w, h))
+	}
+	return monitors, nil
+}
+
+func (robotgoBackend) ScaleFactor(ctx context.Context) (float64, error) {
+	return robotgo.ScaleF(), nil
+}
+
+func (robotgoBackend) CaptureScreen(ctx context.Context, region image.Rectangle) (image.Image, error) {
+	var img image.Image
+	if region.Empty() {
+		img = robotgo.CaptureImg()
+	} else {
+		img = robotgo.CaptureImg(region.Min.X, region.Min.Y, region.Dx(), region.Dy())
+	}
+	if img == nil {
+		return nil, fmt.Errorf("failed to capture screen: image is nil")
+	}
+	return img, nil
+}
+
+func (robotgoBackend) ActiveWindowBounds(ctx context.Context) (image.Rectangle, error) {
+	pid := robotgo.GetPid()
+	if pid <= 0 {
+		return image.Rectangle{}, fmt.Errorf("failed to find the active window")
+	}
+
+	x, y, w, h := robotgo.GetBounds(pid)
+	if w <= 0 || h <= 0 {
+		return image.Rectangle{}, fmt.Errorf("failed to get bounds of the active window (pid %d)", pid)
+	}
+
+	return image.Rect(x, y, x+w, y+h), nil
+}
@@ -0,0 +1,247 @@
+package automation
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Frame is the rectangle percentages and anchors in a target are resolved against
+type Frame int
+
+const (
+	// FrameScreen resolves targets against the whole screen
+	FrameScreen Frame = iota
+	// FrameWindow resolves targets against the active window
+	FrameWindow
+)
+
+// ParseFrame parses a frame name: "screen" or "window"
+func ParseFrame(s string) (Frame, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "screen", "":
+		return FrameScreen, nil
+	case "window":
+		return FrameWindow, nil
+	}
+	return FrameScreen, fmt.Errorf("unknown frame %q: must be screen or window", s)
+}
+
+// axisKind is the way a single coordinate of a target is expressed
+type axisKind int
+
+const (
+	axisAbsolute axisKind = iota // 100
+	axisRelative                 // +50 or -20, relative to the cursor
+	axisPercent                  // 50%, of the frame
+)
+
+// axis is one parsed coordinate of a target
+type axis struct {
+	kind  axisKind
+	value float64
+}
+
+// Target is a parsed position expression. It is resolved against the cursor
+// position and a frame when used, so the same expression works at any resolution.
+//
+// Supported forms, either as one "X,Y" argument or as separate X and Y:
+//
+//	100,200     absolute coordinates
+//	+50,-20     relative to the current cursor position
+//	50%,25%     percentage of the screen or active window
+//	@center     a built-in anchor (see Anchors) or one registered with SetAnchor
+type Target struct {
+	x, y   axis
+	anchor string
+}
+
+// builtinAnchors maps built-in anchor names to percentages of the frame
+var builtinAnchors = map[string][2]float64{
+	"center":       {50, 50},
+	"top-left":     {0, 0},
+	"top":          {50, 0},
+	"top-right":    {100, 0},
+	"left":         {0, 50},
+	"right":        {100, 50},
+	"bottom-left":  {0, 100},
+	"bottom":       {50, 100},
+	"bottom-right": {100, 100},
+}
+
+var (
+	anchorsMu sync.RWMutex
+	anchors   = map[string]image.Point{}
+)
+
+// SetAnchor registers a named anchor at the given logical coordinates
+func SetAnchor(name string, x, y int) error {
+	name = strings.ToLower(strings.TrimPrefix(name, "@"))
+	if name == "" {
+		return fmt.Errorf("anchor name cannot be empty")
+	}
+	if _, ok := builtinAnchors[name]; ok || name == "cursor" {
+		return fmt.Errorf("anchor name %q is reserved", name)
+	}
+
+	anchorsMu.Lock()
+	defer anchorsMu.Unlock()
+	anchors[name] = image.Pt(x, y)
+	return nil
+}
+
+// Anchors returns the names of all anchors, built-in and registered, sorted
+func Anchors() []string {
+	names := []string{"cursor"}
+	for name := range builtinAnchors {
+		names = append(names, name)
+	}
+
+	anchorsMu.RLock()
+	for name := range anchors {
+		names = append(names, name)
+	}
+	anchorsMu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// ParseTarget parses a position expression given as a single "X,Y" or "@anchor"
+// argument or as separate X and Y arguments
+func ParseTarget(args ...string) (Target, error) {
+	switch len(args) {
+	case 1:
+		expr := strings.TrimSpace(args[0])
+		if strings.HasPrefix(expr, "@") {
+			name := strings.ToLower(expr[1:])
+			if name == "" {
+				return Target{}, fmt.Errorf("anchor name cannot be empty")
+			}
+			return Target{anchor: name}, nil
+		}
+		xs, ys, ok := strings.Cut(expr, ",")
+		if !ok {
+			return Target{}, fmt.Errorf("invalid target '%s': expected X,Y or @anchor", expr)
+		}
+		return ParseTarget(xs, ys)
+	case 2:
+		x, err := parseAxis(args[0])
+		if err != nil {
+			return Target{}, fmt.Errorf("invalid x coordinate '%s': %w", args[0], err)
+		}
+		y, err := parseAxis(args[1])
+		if err != nil {
+			return Target{}, fmt.Errorf("invalid y coordinate '%s': %w", args[1], err)
+		}
+		return Target{x: x, y: y}, nil
+	}
+	return Target{}, fmt.Errorf("expected a target or x and y coordinates, got %d arguments", len(args))
+}
+
+// parseAxis parses a single coordinate: absolute, +/- relative or percentage
+func parseAxis(s string) (axis, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return axis{}, fmt.Errorf("must not be empty")
+	}
+
+	if pct, ok := strings.CutSuffix(s, "%"); ok {
+		v, err := strconv.ParseFloat(pct, 64)
+		if err != nil || v < 0 || v > 100 {
+			return axis{}, fmt.Errorf("must be a percentage between 0%% and 100%%")
+		}
+		return axis{kind: axisPercent, value: v}, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return axis{}, fmt.Errorf("must be an integer, +/-offset or percentage")
+	}
+	if s[0] == '+' || s[0] == '-' {
+		return axis{kind: axisRelative, value: float64(v)}, nil
+	}
+	return axis{kind: axisAbsolute, value: float64(v)}, nil
+}
+
+// Resolve returns the logical screen coordinates the target refers to. Absolute
+// coordinates and offsets are interpreted in the space of cs, percentages and
+// anchors relative to frame.
+func (t Target) Resolve(cs CoordSystem, frame Frame) (x, y int, err error) {
+	if t.anchor == "cursor" {
+		x, y = GetPosition()
+		return x, y, nil
+	}
+
+	if t.anchor != "" {
+		if pct, ok := builtinAnchors[t.anchor]; ok {
+			bounds, err := frameBounds(frame)
+			if err != nil {
+				return 0, 0, err
+			}
+			x, y = percentOf(bounds, pct[0], pct[1])
+			return x, y, nil
+		}
+		anchorsMu.RLock()
+		p, ok := anchors[t.anchor]
+		anchorsMu.RUnlock()
+		if !ok {
+			return 0, 0, fmt.Errorf("unknown anchor '@%s'", t.anchor)
+		}
+		return p.X, p.Y, nil
+	}
+
+	// The frame and cursor are only looked up when an axis needs them, so
+	// absolute targets work where the active window is unknown
+	var pctX, pctY int
+	if t.x.kind == axisPercent || t.y.kind == axisPercent {
+		bounds, err := frameBounds(frame)
+		if err != nil {
+			return 0, 0, err
+		}
+		pctX, pctY = percentOf(bounds, t.x.value, t.y.value)
+	}
+	var cursorX, cursorY int
+	if t.x.kind == axisRelative || t.y.kind == axisRelative {
+		cursorX, cursorY = GetPosition()
+	}
+
+	// Absolute values and offsets are given in the caller's space, convert them
+	// first; the mapping is linear so it applies to offsets as well
+	absX, absY := cs.ToLogical(int(t.x.value), int(t.y.value))
+
+	x = resolveAxis(t.x, absX, cursorX, pctX)
+	y = resolveAxis(t.y, absY, cursorY, pctY)
+	return x, y, nil
+}
+
+// resolveAxis picks the resolved value of one coordinate by its kind
+func resolveAxis(a axis, abs, cursor, pct int) int {
+	switch a.kind {
+	case axisRelative:
+		return cursor + abs
+	case axisPercent:
+		return pct
+	}
+	return abs
+}
+
+// percentOf returns the point at the given percentages of a rectangle, where
+// 100% is the last pixel inside it
+func percentOf(r image.Rectangle, px, py float64) (int, int) {
+	x := r.Min.X + round(px/100*float64(max(r.Dx()-1, 0)))
+	y := r.Min.Y + round(py/100*float64(max(r.Dy()-1, 0)))
+	return x, y
+}
+
+// frameBounds returns the logical bounds of a frame
+func frameBounds(frame Frame) (image.Rectangle, error) {
+	if frame == FrameWindow {
+		return ActiveWindowBounds()
+	}
+	w, h := GetScreenSize()
+	return image.Rect(0, 0, w, h), nil
+}
@@ -0,0 +1,129 @@
+package automation
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGenerateTrajectory(t *testing.T) {
+	const fromX, fromY, toX, toY = 100, 200, 900, 600
+	dist := math.Hypot(toX-fromX, toY-fromY)
+
+	tests := []struct {
+		name string
+		opts TrajectoryOptions
+		// margin is how far points may leave the box spanned by start and target;
+		// an overshoot goes up to 40 pixels past the target and half that sideways
+		margin float64
+	}{
+		{"linear in-out", TrajectoryOptions{Path: PathLinear, Easing: EaseInOut, Duration: 0.5}, 1},
+		{"linear out", TrajectoryOptions{Path: PathLinear, Easing: EaseOut, Duration: 0.3}, 1},
+		{"linear none", TrajectoryOptions{Path: PathLinear, Easing: EaseNone, Duration: 1}, 1},
+		{"linear jitter", TrajectoryOptions{Path: PathLinear, Duration: 0.5, Jitter: 2}, 6 * 2},
+		{"linear overshoot", TrajectoryOptions{Path: PathLinear, Duration: 0.5, Overshoot: true}, 60 + 1},
+		{"bezier", TrajectoryOptions{Path: PathBezier, Duration: 0.5}, 200 + 0.1*dist},
+		{"bezier overshoot jitter", TrajectoryOptions{Path: PathBezier, Duration: 0.8, Overshoot: true, Jitter: 1.5}, 200 + 0.1*dist + 60 + 6*1.5},
+		{"wind", TrajectoryOptions{Path: PathWind, Easing: EaseOut, Duration: 0.5}, dist / 2},
+		{"wind overshoot", TrajectoryOptions{Path: PathWind, Duration: 0.7, Overshoot: true}, dist/2 + 60},
+	}
+	for _, tt := range tests {
+		for seed := int64(1); seed <= 20; seed++ {
+			t.Run(fmt.Sprintf("%s/seed %d", tt.name, seed), func(t *testing.T) {
+				opts := tt.opts
+				opts.Seed = seed
+				points := GenerateTrajectory(fromX, fromY, toX, toY, opts)
+
+				if again := GenerateTrajectory(fromX, fromY, toX, toY, opts); !reflect.DeepEqual(points, again) {
+					t.Fatal("the same seed gave a different trajectory")
+				}
+
+				total := time.Duration(opts.Duration * float64(time.Second))
+				if first := points[0]; first != (TrajectoryPoint{X: fromX, Y: fromY}) {
+					t.Errorf("first point = %+v, want the start at 0s", first)
+				}
+				if last := points[len(points)-1]; last != (TrajectoryPoint{X: toX, Y: toY, At: total}) {
+					t.Errorf("last point = %+v, want the target at %v", last, total)
+				}
+				if want := int(total/trajectoryStep) + 1; len(points) < want-1 || len(points) > want+1 {
+					t.Errorf("got %d points, want about %d for %v", len(points), want, total)
+				}
+
+				for i, p := range points {
+					if i > 0 && p.At < points[i-1].At {
+						t.Errorf("point %d at %v is before point %d at %v", i, p.At, i-1, points[i-1].At)
+					}
+					if p.At < 0 || p.At > total {
+						t.Errorf("point %d at %v is outside the duration %v", i, p.At, total)
+					}
+					if float64(p.X) < fromX-tt.margin || float64(p.X) > toX+tt.margin ||
+						float64(p.Y) < fromY-tt.margin || float64(p.Y) > toY+tt.margin {
+						t.Errorf("point %d (%d, %d) is more than %g pixels outside the movement", i, p.X, p.Y, tt.margin)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestGenerateTrajectoryLinearStaysOnLine(t *testing.T) {
+	points := GenerateTrajectory(0, 0, 300, 400, TrajectoryOptions{Path: PathLinear, Duration: 0.5, Seed: 1})
+	for i, p := range points {
+		// Distance from the line 4x - 3y = 0
+		if d := math.Abs(float64(4*p.X-3*p.Y)) / 5; d > 1 {
+			t.Errorf("point %d (%d, %d) is %g pixels off the line", i, p.X, p.Y, d)
+		}
+	}
+}
+
+func TestGenerateTrajectoryDifferentSeeds(t *testing.T) {
+	opts := TrajectoryOptions{Path: PathBezier, Duration: 0.5, Seed: 1}
+	a := GenerateTrajectory(0, 0, 500, 500, opts)
+	opts.Seed = 2
+	b := GenerateTrajectory(0, 0, 500, 500, opts)
+	if reflect.DeepEqual(a, b) {
+		t.Error("different seeds gave the same bezier trajectory")
+	}
+}
+
+func TestGenerateTrajectoryWithoutMovement(t *testing.T) {
+	tests := []struct {
+		name         string
+		fromX, fromY int
+		toX, toY     int
+		duration     float64
+		want         []TrajectoryPoint
+	}{
+		{"same point", 10, 10, 10, 10, 0.5, []TrajectoryPoint{{X: 10, Y: 10, At: 500 * time.Millisecond}}},
+		{"zero duration", 0, 0, 100, 100, 0, []TrajectoryPoint{{X: 100, Y: 100}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateTrajectory(tt.fromX, tt.fromY, tt.toX, tt.toY, TrajectoryOptions{Duration: tt.duration, Seed: 1})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GenerateTrajectory() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEasingApply(t *testing.T) {
+	for _, e := range []Easing{EaseInOut, EaseOut, EaseNone} {
+		if got := e.apply(0); got != 0 {
+			t.Errorf("easing %d: apply(0) = %g, want 0", e, got)
+		}
+		if got := e.apply(1); math.Abs(got-1) > 1e-9 {
+			t.Errorf("easing %d: apply(1) = %g, want 1", e, got)
+		}
+		prev := 0.0
+		for i := 1; i <= 100; i++ {
+			v := e.apply(float64(i) / 100)
+			if v < prev {
+				t.Errorf("easing %d decreases at %d%%", e, i)
+			}
+			prev = v
+		}
+	}
+}
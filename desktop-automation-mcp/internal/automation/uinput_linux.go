@@ -0,0 +1,406 @@
+package automation
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// uinput ioctl requests
+const (
+	uiDevCreate  = 0x5501
+	uiDevDestroy = 0x5502
+	uiSetEvBit   = 0x40045564
+	uiSetKeyBit  = 0x40045565
+	uiSetRelBit  = 0x40045566
+	uiSetAbsBit  = 0x40045567
+)
+
+// evdev event types and codes
+const (
+	evSyn = 0x00
+	evKey = 0x01
+	evRel = 0x02
+	evAbs = 0x03
+
+	synReport = 0
+	relHWheel = 0x06
+	relWheel  = 0x08
+	absX      = 0x00
+	absY      = 0x01
+	busVirt   = 0x06
+)
+
+// Pointer button key codes
+var evdevButtons = map[string]uint16{"left": 0x110, "right": 0x111, "middle": 0x112}
+
+// UinputBackend drives the desktop through virtual devices created with
+// /dev/uinput, so it works wherever the kernel input stack is read: on Wayland
+// compositors, X11 and the console. The pointer is an absolute tablet spanning
+// the screen, like the tablet of a VM, so coordinates keep their meaning.
+//
+// Keys are sent as evdev key codes and text is typed assuming a US keyboard
+// layout. The kernel cannot read the screen, so screenshots are taken with grim
+// on wlroots-based compositors, the cursor position is the last one set through
+// the backend, and ActiveWindowBounds is unsupported.
+type UinputBackend struct {
+	keyboard *os.File
+	pointer  *os.File
+	width    int
+	height   int
+
+	mu   sync.Mutex
+	x, y int
+}
+
+var _ Backend = (*UinputBackend)(nil)
+
+// OpenUinput creates a virtual keyboard and tablet for a screen of the given
+// size. If the size is zero it is detected from a grim screenshot or the
+// framebuffer device.
+func OpenUinput(ctx context.Context, width, height int) (*UinputBackend, error) {
+	if width <= 0 || height <= 0 {
+		var err error
+		if width, height, err = detectScreenSize(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	keyboard, err := createUinputDevice("desktop-automation keyboard", func(fd int) error {
+		if err := unix.IoctlSetInt(fd, uiSetEvBit, evKey); err != nil {
+			return err
+		}
+		for code := 1; code < 256; code++ {
+			if err := unix.IoctlSetInt(fd, uiSetKeyBit, code); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	absMax := map[uint16]int32{absX: int32(width - 1), absY: int32(height - 1)}
+	pointer, err := createUinputDevice("desktop-automation tablet", func(fd int) error {
+		for _, bit := range []int{evKey, evRel, evAbs} {
+			if err := unix.IoctlSetInt(fd, uiSetEvBit, bit); err != nil {
+				return err
+			}
+		}
+		for _, code := range evdevButtons {
+			if err := unix.IoctlSetInt(fd, uiSetKeyBit, int(code)); err != nil {
+				return err
+			}
+		}
+		for _, code := range []int{relWheel, relHWheel} {
+			if err := unix.IoctlSetInt(fd, uiSetRelBit, code); err != nil {
+				return err
+			}
+		}
+		for code := range absMax {
+			if err := unix.IoctlSetInt(fd, uiSetAbsBit, int(code)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, absMax)
+	if err != nil {
+		destroyUinputDevice(keyboard)
+		return nil, err
+	}
+
+	// Give udev and the compositor time to pick up the new devices, events sent
+	// before that are lost
+	select {
+	case <-time.After(250 * time.Millisecond):
+	case <-ctx.Done():
+		destroyUinputDevice(pointer)
+		destroyUinputDevice(keyboard)
+		return nil, ctx.Err()
+	}
+
+	return &UinputBackend{keyboard: keyboard, pointer: pointer, width: width, height: height}, nil
+}
+
+// Close removes the virtual devices
+func (u *UinputBackend) Close() error {
+	destroyUinputDevice(u.pointer)
+	destroyUinputDevice(u.keyboard)
+	return nil
+}
+
+// createUinputDevice creates a virtual input device after setup has enabled
+// its event types and codes
+func createUinputDevice(name string, setup func(fd int) error, absMax map[uint16]int32) (*os.File, error) {
+	f, err := os.OpenFile("/dev/uinput", os.O_WRONLY|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open /dev/uinput (load the uinput module and check permissions): %w", err)
+	}
+	fd := int(f.Fd())
+
+	if err := setup(fd); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to configure uinput device: %w", err)
+	}
+
+	// struct uinput_user_dev
+	var dev struct {
+		Name                             [80]byte
+		Bustype, Vendor, Product, Ver    uint16
+		FFEffectsMax                     uint32
+		AbsMax, AbsMin, AbsFuzz, AbsFlat [64]int32
+	}
+	copy(dev.Name[:], name)
+	dev.Bustype, dev.Vendor, dev.Product, dev.Ver = busVirt, 0x1, 0x1, 1
+	for code, limit := range absMax {
+		dev.AbsMax[code] = limit
+	}
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.NativeEndian, &dev)
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to configure uinput device: %w", err)
+	}
+
+	if _, err := unix.IoctlRetInt(fd, uiDevCreate); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to create uinput device: %w", err)
+	}
+	return f, nil
+}
+
+// destroyUinputDevice removes a virtual input device
+func destroyUinputDevice(f *os.File) {
+	unix.IoctlRetInt(int(f.Fd()), uiDevDestroy)
+	f.Close()
+}
+
+// send writes events to a device followed by a report that delivers them
+func (u *UinputBackend) send(f *os.File, events ...[3]int32) error {
+	// struct input_event starts with a timeval, which the kernel fills in
+	timeSize := int(unsafe.Sizeof(unix.Timeval{}))
+	var buf bytes.Buffer
+	for _, ev := range append(events, [3]int32{evSyn, synReport, 0}) {
+		buf.Write(make([]byte, timeSize))
+		binary.Write(&buf, binary.NativeEndian, uint16(ev[0]))
+		binary.Write(&buf, binary.NativeEndian, uint16(ev[1]))
+		binary.Write(&buf, binary.NativeEndian, ev[2])
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to send uinput event: %w", err)
+	}
+	return nil
+}
+
+// key presses or releases a key code on the keyboard
+func (u *UinputBackend) key(code uint16, down bool) error {
+	value := int32(0)
+	if down {
+		value = 1
+	}
+	return u.send(u.keyboard, [3]int32{evKey, int32(code), value})
+}
+
+func (u *UinputBackend) MoveMouse(ctx context.Context, x, y int) error {
+	x = max(0, min(x, u.width-1))
+	y = max(0, min(y, u.height-1))
+
+	u.mu.Lock()
+	u.x, u.y = x, y
+	u.mu.Unlock()
+	return u.send(u.pointer, [3]int32{evAbs, absX, int32(x)}, [3]int32{evAbs, absY, int32(y)})
+}
+
+func (u *UinputBackend) MouseToggle(ctx context.Context, button string, down bool) error {
+	code, ok := evdevButtons[button]
+	if !ok {
+		return fmt.Errorf("unknown mouse button '%s'", button)
+	}
+	value := int32(0)
+	if down {
+		value = 1
+	}
+	return u.send(u.pointer, [3]int32{evKey, int32(code), value})
+}
+
+func (u *UinputBackend) Click(ctx context.Context, button string, double bool) error {
+	clicks := 1
+	if double {
+		clicks = 2
+	}
+	for i := 0; i < clicks; i++ {
+		if err := u.MouseToggle(ctx, button, true); err != nil {
+			return err
+		}
+		if err := u.MouseToggle(ctx, button, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UinputBackend) Scroll(ctx context.Context, dx, dy int) error {
+	// REL_WHEEL is positive up, REL_HWHEEL positive right
+	step := func(code, value int32, n int) error {
+		for i := 0; i < n; i++ {
+			if err := u.send(u.pointer, [3]int32{evRel, code, value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if dy > 0 {
+		if err := step(relWheel, -1, dy); err != nil {
+			return err
+		}
+	} else if err := step(relWheel, 1, -dy); err != nil {
+		return err
+	}
+	if dx > 0 {
+		return step(relHWheel, 1, dx)
+	}
+	return step(relHWheel, -1, -dx)
+}
+
+func (u *UinputBackend) KeyTap(ctx context.Context, key string, modifiers ...string) error {
+	keys := make([]evdevKey, 0, len(modifiers)+1)
+	for _, name := range append(modifiers, key) {
+		k, err := evdevKeyForName(name)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+
+	codes := evdevComboCodes(keys)
+	for _, code := range codes {
+		if err := u.key(code, true); err != nil {
+			return err
+		}
+	}
+	for i := len(codes) - 1; i >= 0; i-- {
+		if err := u.key(codes[i], false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UinputBackend) KeyToggle(ctx context.Context, key string, down bool) error {
+	k, err := evdevKeyForName(key)
+	if err != nil {
+		return err
+	}
+	return u.key(k.code, down)
+}
+
+func (u *UinputBackend) TypeString(ctx context.Context, text string) error {
+	shift := namedEvdevKeys["shift"]
+	for _, r := range text {
+		k, err := evdevKeyForRune(r)
+		if err != nil {
+			return err
+		}
+
+		if k.shift {
+			if err := u.key(shift, true); err != nil {
+				return err
+			}
+		}
+		err = u.key(k.code, true)
+		if err == nil {
+			err = u.key(k.code, false)
+		}
+		if k.shift {
+			if serr := u.key(shift, false); err == nil {
+				err = serr
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UinputBackend) MousePosition(ctx context.Context) (int, int, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.x, u.y, nil
+}
+
+func (u *UinputBackend) ScreenSize(ctx context.Context) (int, int, error) {
+	return u.width, u.height, nil
+}
+
+func (u *UinputBackend) ScaleFactor(ctx context.Context) (float64, error) {
+	return 1, nil
+}
+
+func (u *UinputBackend) CaptureScreen(ctx context.Context, region image.Rectangle) (image.Image, error) {
+	return captureGrim(ctx, region)
+}
+
+func (u *UinputBackend) ActiveWindowBounds(ctx context.Context) (image.Rectangle, error) {
+	return image.Rectangle{}, fmt.Errorf("window information is not available with uinput")
+}
+
+// captureGrim takes a screenshot with grim, the screenshot tool of wlroots-based
+// Wayland compositors
+func captureGrim(ctx context.Context, region image.Rectangle) (image.Image, error) {
+	if _, err := exec.LookPath("grim"); err != nil {
+		return nil, fmt.Errorf("screenshots with the uinput backend require grim: %w", err)
+	}
+
+	args := []string{"-t", "png"}
+	if !region.Empty() {
+		args = append(args, "-g", fmt.Sprintf("%d,%d %dx%d", region.Min.X, region.Min.Y, region.Dx(), region.Dy()))
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "grim", append(args, "-")...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("grim failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode grim screenshot: %w", err)
+	}
+	return img, nil
+}
+
+// detectScreenSize returns the screen size from a grim screenshot or, on the
+// console, the framebuffer device
+func detectScreenSize(ctx context.Context) (int, int, error) {
+	if img, err := captureGrim(ctx, image.Rectangle{}); err == nil {
+		return img.Bounds().Dx(), img.Bounds().Dy(), nil
+	}
+
+	if data, err := os.ReadFile("/sys/class/graphics/fb0/virtual_size"); err == nil {
+		var w, h int
+		if _, err := fmt.Sscanf(strings.TrimSpace(string(data)), "%d,%d", &w, &h); err == nil && w > 0 && h > 0 {
+			return w, h, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("failed to detect the screen size, give it as uinput:WIDTHxHEIGHT")
+}
+
+// openUinput opens the uinput backend
+func openUinput(ctx context.Context, width, height int) (Backend, error) {
+	return OpenUinput(ctx, width, height)
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package automation
+
+import (
+	"context"
+	"fmt"
+)
+
+// openUinput fails because uinput is a Linux interface
+func openUinput(ctx context.Context, width, height int) (Backend, error) {
+	return nil, fmt.Errorf("the uinput backend is only available on Linux")
+}
@@ -0,0 +1,678 @@
+package automation
+
+import (
+	"bufio"
+	"context"
+	"crypto/des"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// RFB client message types
+const (
+	rfbSetPixelFormat           = 0
+	rfbSetEncodings             = 2
+	rfbFramebufferUpdateRequest = 3
+	rfbKeyEvent                 = 4
+	rfbPointerEvent             = 5
+)
+
+// RFB server message types
+const (
+	rfbFramebufferUpdate   = 0
+	rfbSetColourMapEntries = 1
+	rfbBell                = 2
+	rfbServerCutText       = 3
+)
+
+// RFB encodings
+const (
+	rfbEncodingRaw         = 0
+	rfbEncodingCopyRect    = 1
+	rfbEncodingDesktopSize = -223
+)
+
+// RFB security types
+const (
+	rfbSecurityNone    = 1
+	rfbSecurityVNCAuth = 2
+)
+
+// Pointer button mask bits
+var vncButtons = map[string]uint8{"left": 1, "middle": 2, "right": 4}
+
+const (
+	vncWheelUp    = 8
+	vncWheelDown  = 16
+	vncWheelLeft  = 32
+	vncWheelRight = 64
+)
+
+// VNCBackend drives a remote desktop as an RFB (VNC) client. Pointer and key
+// events are sent as RFB messages, and the framebuffer is kept up to date by
+// continuous incremental update requests so screenshots are served locally.
+//
+// RFB does not report the cursor position or window geometry: the position is
+// the last one set through the backend and ActiveWindowBounds is unsupported.
+type VNCBackend struct {
+	conn net.Conn
+
+	writeMu sync.Mutex
+	w       *bufio.Writer
+
+	mu      sync.Mutex
+	fb      *image.RGBA
+	x, y    int
+	buttons uint8
+	err     error
+
+	// ready is closed when the first complete framebuffer has been received,
+	// done when the connection has failed
+	ready     chan struct{}
+	readyOnce sync.Once
+	done      chan struct{}
+}
+
+var _ Backend = (*VNCBackend)(nil)
+
+// DialVNC connects to the VNC server at address (host:port) and authenticates
+// with password if the server requires it
+func DialVNC(ctx context.Context, address, password string) (*VNCBackend, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to VNC server %s: %w", address, err)
+	}
+
+	v := &VNCBackend{
+		conn:  conn,
+		w:     bufio.NewWriter(conn),
+		ready: make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	r := bufio.NewReader(conn)
+	if err := v.handshake(r, password); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("VNC handshake with %s failed: %w", address, err)
+	}
+	conn.SetDeadline(time.Time{})
+
+	go v.readLoop(r)
+	return v, nil
+}
+
+// Close closes the connection to the VNC server
+func (v *VNCBackend) Close() error {
+	return v.conn.Close()
+}
+
+// handshake negotiates the protocol version, authenticates and initializes the session
+func (v *VNCBackend) handshake(r *bufio.Reader, password string) error {
+	version := make([]byte, 12)
+	if _, err := io.ReadFull(r, version); err != nil {
+		return err
+	}
+	var major, minor int
+	if _, err := fmt.Sscanf(string(version), "RFB %03d.%03d\n", &major, &minor); err != nil || major != 3 {
+		return fmt.Errorf("unsupported protocol version %q", version)
+	}
+	if minor >= 8 {
+		minor = 8
+	} else if minor >= 7 {
+		minor = 7
+	} else {
+		minor = 3
+	}
+	if _, err := fmt.Fprintf(v.conn, "RFB 003.%03d\n", minor); err != nil {
+		return err
+	}
+
+	security, err := v.negotiateSecurity(r, minor)
+	if err != nil {
+		return err
+	}
+
+	if security == rfbSecurityVNCAuth {
+		if err := vncAuthenticate(r, v.conn, password); err != nil {
+			return err
+		}
+	}
+
+	// Protocol 3.8 reports the result of every security type, earlier
+	// versions only that of VNC authentication
+	if security == rfbSecurityVNCAuth || minor >= 8 {
+		var result uint32
+		if err := binary.Read(r, binary.BigEndian, &result); err != nil {
+			return err
+		}
+		if result != 0 {
+			reason := "authentication failed"
+			if minor >= 8 {
+				if msg, err := readString(r); err == nil && msg != "" {
+					reason = msg
+				}
+			}
+			return errors.New(reason)
+		}
+	}
+
+	// ClientInit: share the desktop with other clients
+	if _, err := v.conn.Write([]byte{1}); err != nil {
+		return err
+	}
+
+	var init struct {
+		Width, Height uint16
+		PixelFormat   [16]byte
+	}
+	if err := binary.Read(r, binary.BigEndian, &init); err != nil {
+		return err
+	}
+	if _, err := readString(r); err != nil {
+		return err
+	}
+	v.fb = image.NewRGBA(image.Rect(0, 0, int(init.Width), int(init.Height)))
+
+	// Request 32-bit little-endian true colour with blue in the lowest byte
+	msg := []byte{rfbSetPixelFormat, 0, 0, 0,
+		32, 24, 0, 1, 0, 255, 0, 255, 0, 255, 16, 8, 0, 0, 0, 0}
+	if _, err := v.conn.Write(msg); err != nil {
+		return err
+	}
+
+	encodings := []int32{rfbEncodingCopyRect, rfbEncodingRaw, rfbEncodingDesktopSize}
+	msg = []byte{rfbSetEncodings, 0, 0, byte(len(encodings))}
+	for _, e := range encodings {
+		msg = binary.BigEndian.AppendUint32(msg, uint32(e))
+	}
+	if _, err := v.conn.Write(msg); err != nil {
+		return err
+	}
+
+	return v.requestUpdate(false)
+}
+
+// negotiateSecurity selects a security type supported by both sides
+func (v *VNCBackend) negotiateSecurity(r *bufio.Reader, minor int) (uint8, error) {
+	if minor == 3 {
+		// The server decides
+		var security uint32
+		if err := binary.Read(r, binary.BigEndian, &security); err != nil {
+			return 0, err
+		}
+		switch security {
+		case 0:
+			reason, _ := readString(r)
+			return 0, fmt.Errorf("connection refused: %s", reason)
+		case rfbSecurityNone, rfbSecurityVNCAuth:
+			return uint8(security), nil
+		}
+		return 0, fmt.Errorf("unsupported security type %d", security)
+	}
+
+	count, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		reason, _ := readString(r)
+		return 0, fmt.Errorf("connection refused: %s", reason)
+	}
+	types := make([]byte, count)
+	if _, err := io.ReadFull(r, types); err != nil {
+		return 0, err
+	}
+
+	var chosen uint8
+	for _, t := range types {
+		if t == rfbSecurityNone {
+			chosen = t
+			break
+		}
+		if t == rfbSecurityVNCAuth {
+			chosen = t
+		}
+	}
+	if chosen == 0 {
+		return 0, fmt.Errorf("no supported security type offered (server offers %v)", types)
+	}
+	if _, err := v.conn.Write([]byte{chosen}); err != nil {
+		return 0, err
+	}
+	return chosen, nil
+}
+
+// vncAuthenticate answers the VNC authentication challenge by encrypting it
+// with the password as DES key
+func vncAuthenticate(r io.Reader, w io.Writer, password string) error {
+	if password == "" {
+		return errors.New("server requires a password")
+	}
+
+	challenge := make([]byte, 16)
+	if _, err := io.ReadFull(r, challenge); err != nil {
+		return err
+	}
+
+	// The key is the first 8 bytes of the password with the bits of every byte reversed
+	key := make([]byte, 8)
+	copy(key, password)
+	for i, b := range key {
+		var rev byte
+		for bit := 0; bit < 8; bit++ {
+			if b&(1<<bit) != 0 {
+				rev |= 1 << (7 - bit)
+			}
+		}
+		key[i] = rev
+	}
+
+	cipher, err := des.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	response := make([]byte, 16)
+	cipher.Encrypt(response[:8], challenge[:8])
+	cipher.Encrypt(response[8:], challenge[8:])
+	_, err = w.Write(response)
+	return err
+}
+
+// readString reads a string prefixed by its uint32 length
+func readString(r io.Reader) (string, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return "", err
+	}
+	if n > 1<<20 {
+		return "", fmt.Errorf("string too long: %d bytes", n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// readLoop processes server messages until the connection fails
+func (v *VNCBackend) readLoop(r *bufio.Reader) {
+	err := v.processMessages(r)
+
+	v.mu.Lock()
+	if errors.Is(err, net.ErrClosed) {
+		err = errors.New("connection closed")
+	}
+	v.err = fmt.Errorf("VNC connection lost: %w", err)
+	v.mu.Unlock()
+	close(v.done)
+}
+
+func (v *VNCBackend) processMessages(r *bufio.Reader) error {
+	for {
+		msgType, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		switch msgType {
+		case rfbFramebufferUpdate:
+			resized, err := v.readUpdate(r)
+			if err != nil {
+				return err
+			}
+			if !resized {
+				v.readyOnce.Do(func() { close(v.ready) })
+			}
+			// A resize invalidates the framebuffer, so request it completely
+			if err := v.requestUpdate(!resized); err != nil {
+				return err
+			}
+		case rfbSetColourMapEntries:
+			// Not used with true colour, but may still be sent
+			var hdr struct {
+				Padding      uint8
+				First, Count uint16
+			}
+			if err := binary.Read(r, binary.BigEndian, &hdr); err != nil {
+				return err
+			}
+			if _, err := r.Discard(int(hdr.Count) * 6); err != nil {
+				return err
+			}
+		case rfbBell:
+		case rfbServerCutText:
+			if _, err := r.Discard(3); err != nil {
+				return err
+			}
+			if _, err := readString(r); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported server message type %d", msgType)
+		}
+	}
+}
+
+// readUpdate applies a FramebufferUpdate message to the framebuffer and
+// reports whether the desktop was resized
+func (v *VNCBackend) readUpdate(r *bufio.Reader) (resized bool, err error) {
+	var hdr struct {
+		Padding uint8
+		Rects   uint16
+	}
+	if err := binary.Read(r, binary.BigEndian, &hdr); err != nil {
+		return false, err
+	}
+
+	for i := 0; i < int(hdr.Rects); i++ {
+		var rect struct {
+			X, Y, Width, Height uint16
+			Encoding            int32
+		}
+		if err := binary.Read(r, binary.BigEndian, &rect); err != nil {
+			return false, err
+		}
+		bounds := image.Rect(int(rect.X), int(rect.Y), int(rect.X)+int(rect.Width), int(rect.Y)+int(rect.Height))
+
+		switch rect.Encoding {
+		case rfbEncodingRaw:
+			pixels := make([]byte, bounds.Dx()*bounds.Dy()*4)
+			if _, err := io.ReadFull(r, pixels); err != nil {
+				return false, err
+			}
+			v.mu.Lock()
+			for y := 0; y < bounds.Dy(); y++ {
+				for x := 0; x < bounds.Dx(); x++ {
+					p := pixels[(y*bounds.Dx()+x)*4:]
+					if !(image.Point{bounds.Min.X + x, bounds.Min.Y + y}.In(v.fb.Rect)) {
+						continue
+					}
+					o := v.fb.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)
+					v.fb.Pix[o], v.fb.Pix[o+1], v.fb.Pix[o+2], v.fb.Pix[o+3] = p[2], p[1], p[0], 0xff
+				}
+			}
+			v.mu.Unlock()
+		case rfbEncodingCopyRect:
+			var src struct{ X, Y uint16 }
+			if err := binary.Read(r, binary.BigEndian, &src); err != nil {
+				return false, err
+			}
+			v.mu.Lock()
+			// Copy through a temporary image as source and destination may overlap
+			tmp := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+			draw.Draw(tmp, tmp.Rect, v.fb, image.Pt(int(src.X), int(src.Y)), draw.Src)
+			draw.Draw(v.fb, bounds, tmp, image.Point{}, draw.Src)
+			v.mu.Unlock()
+		case rfbEncodingDesktopSize:
+			v.mu.Lock()
+			v.fb = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+			v.mu.Unlock()
+			resized = true
+		default:
+			return false, fmt.Errorf("unsupported encoding %d", rect.Encoding)
+		}
+	}
+	return resized, nil
+}
+
+// send writes a client message
+func (v *VNCBackend) send(msg []byte) error {
+	v.writeMu.Lock()
+	defer v.writeMu.Unlock()
+
+	if _, err := v.w.Write(msg); err != nil {
+		return err
+	}
+	return v.w.Flush()
+}
+
+// requestUpdate asks for an update of the whole framebuffer
+func (v *VNCBackend) requestUpdate(incremental bool) error {
+	v.mu.Lock()
+	w, h := v.fb.Rect.Dx(), v.fb.Rect.Dy()
+	v.mu.Unlock()
+
+	msg := []byte{rfbFramebufferUpdateRequest, 0, 0, 0, 0, 0}
+	if incremental {
+		msg[1] = 1
+	}
+	msg = binary.BigEndian.AppendUint16(msg, uint16(w))
+	msg = binary.BigEndian.AppendUint16(msg, uint16(h))
+	return v.send(msg)
+}
+
+// check returns the connection error, if any
+func (v *VNCBackend) check() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.err
+}
+
+// pointer sends a pointer event with the given button mask at the current position
+func (v *VNCBackend) pointer(buttons uint8) error {
+	if err := v.check(); err != nil {
+		return err
+	}
+	v.mu.Lock()
+	// The framebuffer may have shrunk since the cursor was moved
+	x, y := v.clampToScreen(v.x, v.y)
+	v.mu.Unlock()
+
+	msg := []byte{rfbPointerEvent, buttons}
+	msg = binary.BigEndian.AppendUint16(msg, uint16(x))
+	msg = binary.BigEndian.AppendUint16(msg, uint16(y))
+	return v.send(msg)
+}
+
+// clampToScreen moves a position onto the framebuffer, as pointer events
+// carry unsigned 16-bit coordinates; v.mu must be held
+func (v *VNCBackend) clampToScreen(x, y int) (int, int) {
+	r := v.fb.Rect
+	return clamp(x, r.Min.X, r.Max.X-1), clamp(y, r.Min.Y, r.Max.Y-1)
+}
+
+// key sends a key event for a keysym
+func (v *VNCBackend) key(sym uint32, down bool) error {
+	if err := v.check(); err != nil {
+		return err
+	}
+	msg := []byte{rfbKeyEvent, 0, 0, 0}
+	if down {
+		msg[1] = 1
+	}
+	msg = binary.BigEndian.AppendUint32(msg, sym)
+	return v.send(msg)
+}
+
+func (v *VNCBackend) MoveMouse(ctx context.Context, x, y int) error {
+	v.mu.Lock()
+	v.x, v.y = v.clampToScreen(x, y)
+	buttons := v.buttons
+	v.mu.Unlock()
+	return v.pointer(buttons)
+}
+
+func (v *VNCBackend) MouseToggle(ctx context.Context, button string, down bool) error {
+	bit, ok := vncButtons[button]
+	if !ok {
+		return fmt.Errorf("unknown mouse button '%s'", button)
+	}
+
+	v.mu.Lock()
+	if down {
+		v.buttons |= bit
+	} else {
+		v.buttons &^= bit
+	}
+	buttons := v.buttons
+	v.mu.Unlock()
+	return v.pointer(buttons)
+}
+
+func (v *VNCBackend) Click(ctx context.Context, button string, double bool) error {
+	clicks := 1
+	if double {
+		clicks = 2
+	}
+	for i := 0; i < clicks; i++ {
+		if err := v.MouseToggle(ctx, button, true); err != nil {
+			return err
+		}
+		if err := v.MouseToggle(ctx, button, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *VNCBackend) Scroll(ctx context.Context, dx, dy int) error {
+	v.mu.Lock()
+	buttons := v.buttons
+	v.mu.Unlock()
+
+	// Every wheel step is a press and release of the corresponding button
+	step := func(bit uint8, n int) error {
+		for i := 0; i < n; i++ {
+			if err := v.pointer(buttons | bit); err != nil {
+				return err
+			}
+			if err := v.pointer(buttons); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if dy > 0 {
+		if err := step(vncWheelDown, dy); err != nil {
+			return err
+		}
+	} else if err := step(vncWheelUp, -dy); err != nil {
+		return err
+	}
+	if dx > 0 {
+		return step(vncWheelRight, dx)
+	}
+	return step(vncWheelLeft, -dx)
+}
+
+func (v *VNCBackend) KeyTap(ctx context.Context, key string, modifiers ...string) error {
+	syms := make([]uint32, 0, len(modifiers)+1)
+	for _, k := range append(modifiers, key) {
+		sym, err := keysymForName(k)
+		if err != nil {
+			return err
+		}
+		syms = append(syms, sym)
+	}
+
+	for _, sym := range syms {
+		if err := v.key(sym, true); err != nil {
+			return err
+		}
+	}
+	for i := len(syms) - 1; i >= 0; i-- {
+		if err := v.key(syms[i], false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *VNCBackend) KeyToggle(ctx context.Context, key string, down bool) error {
+	sym, err := keysymForName(key)
+	if err != nil {
+		return err
+	}
+	return v.key(sym, down)
+}
+
+func (v *VNCBackend) TypeString(ctx context.Context, text string) error {
+	for _, r := range text {
+		sym := keysymForRune(r)
+		if err := v.key(sym, true); err != nil {
+			return err
+		}
+		if err := v.key(sym, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *VNCBackend) MousePosition(ctx context.Context) (int, int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.x, v.y, v.err
+}
+
+func (v *VNCBackend) ScreenSize(ctx context.Context) (int, int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.fb.Rect.Dx(), v.fb.Rect.Dy(), v.err
+}
+
+func (v *VNCBackend) ScaleFactor(ctx context.Context) (float64, error) {
+	return 1, nil
+}
+
+func (v *VNCBackend) CaptureScreen(ctx context.Context, region image.Rectangle) (image.Image, error) {
+	select {
+	case <-v.ready:
+	case <-v.done:
+		return nil, v.check()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	// ready stays closed after the connection fails, leaving a stale framebuffer
+	if v.err != nil {
+		return nil, v.err
+	}
+	if region.Empty() {
+		region = v.fb.Rect
+	}
+	region = region.Intersect(v.fb.Rect)
+	if region.Empty() {
+		return nil, fmt.Errorf("capture region is outside the screen")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, region.Dx(), region.Dy()))
+	draw.Draw(img, img.Rect, v.fb, region.Min, draw.Src)
+	return img, nil
+}
+
+func (v *VNCBackend) ActiveWindowBounds(ctx context.Context) (image.Rectangle, error) {
+	return image.Rectangle{}, fmt.Errorf("window information is not available over VNC")
+}
+
+// parseVNCAddress splits a vnc://[:password@]host[:port] URL into the address
+// to dial and the password; the port defaults to 5900
+func parseVNCAddress(spec string) (address, password string, err error) {
+	u, err := url.Parse(spec)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Hostname() == "" {
+		return "", "", fmt.Errorf("missing host in %q", spec)
+	}
+	password, _ = u.User.Password()
+
+	port := u.Port()
+	if port == "" {
+		port = "5900"
+	}
+	return net.JoinHostPort(u.Hostname(), port), password, nil
+}
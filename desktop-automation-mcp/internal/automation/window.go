@@ -0,0 +1,11 @@
+package automation
+
+import (
+	"context"
+	"image"
+)
+
+// ActiveWindowBounds returns the bounds of the focused window in logical coordinates
+func ActiveWindowBounds() (image.Rectangle, error) {
+	return CurrentBackend().ActiveWindowBounds(context.Background())
+}
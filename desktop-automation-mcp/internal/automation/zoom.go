@@ -0,0 +1,138 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font/basicfont"
+)
+
+const (
+	// maxZoomScale bounds the magnification of Zoom
+	maxZoomScale = 16
+	// maxZoomSize bounds the width and height of a zoomed region in pixels
+	maxZoomSize = 4096
+	// legendSpacing is the minimum distance in pixels between legend lines
+	legendSpacing = 60
+)
+
+// legendSteps are the distances in units between legend lines Zoom picks
+// from, multiplied by powers of ten
+var legendSteps = []int{1, 2, 5}
+
+// ZoomFilter selects how Zoom upscales the region
+type ZoomFilter int
+
+const (
+	// ZoomNearest repeats pixels, keeping edges sharp and colours exact
+	ZoomNearest ZoomFilter = iota
+	// ZoomSmooth interpolates with a Catmull-Rom filter
+	ZoomSmooth
+)
+
+// ParseZoomFilter parses a zoom filter name: "nearest" or "smooth"
+func ParseZoomFilter(s string) (ZoomFilter, error) {
+	switch strings.ToLower(s) {
+	case "nearest", "":
+		return ZoomNearest, nil
+	case "smooth":
+		return ZoomSmooth, nil
+	}
+	return ZoomNearest, fmt.Errorf("invalid zoom filter '%s': must be nearest or smooth", s)
+}
+
+// Zoomed is a magnified region of the screen with a coordinate legend
+type Zoomed struct {
+	Image *image.RGBA
+	// Region is the zoomed region in the coordinate space it was given in
+	Region image.Rectangle
+	// Scale is the number of pixels per unit of the coordinate space
+	Scale int
+	// Offset is the position of the region's top-left corner in Image, below
+	// and right of the legend
+	Offset image.Point
+	// Step is the distance in units between the legend's lines
+	Step int
+}
+
+// Zoom captures a region given in the coordinate system's space and upscales
+// it so each unit is scale pixels large. Lines labelled with their
+// coordinates along the top and left edges map the image back to that space.
+func Zoom(ctx context.Context, cs CoordSystem, region image.Rectangle, scale int, filter ZoomFilter) (*Zoomed, error) {
+	if scale < 1 || scale > maxZoomScale {
+		return nil, fmt.Errorf("scale must be between 1 and %d", maxZoomScale)
+	}
+	w, h := cs.Size()
+	if region.Empty() || !region.In(image.Rect(0, 0, w, h)) {
+		return nil, fmt.Errorf("region (%d, %d) %dx%d is not inside the %dx%d screen",
+			region.Min.X, region.Min.Y, region.Dx(), region.Dy(), w, h)
+	}
+	if region.Dx()*scale > maxZoomSize || region.Dy()*scale > maxZoomSize {
+		return nil, fmt.Errorf("zoomed region %dx%d is larger than %dx%d pixels: zoom a smaller region or lower the scale",
+			region.Dx()*scale, region.Dy()*scale, maxZoomSize, maxZoomSize)
+	}
+
+	x0, y0 := cs.ToLogical(region.Min.X, region.Min.Y)
+	x1, y1 := cs.ToLogical(region.Max.X, region.Max.Y)
+	captured, err := CaptureImage(ctx, image.Rect(x0, y0, x1, y1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture region: %w", err)
+	}
+
+	z := &Zoomed{Region: region, Scale: scale, Step: legendStep(scale)}
+
+	// Leave room for the widest label left of the image and one row above it
+	face := basicfont.Face7x13
+	widest := strconv.Itoa(max(region.Max.Y, region.Max.X))
+	z.Offset = image.Pt(len(widest)*face.Advance+4, face.Height+2)
+
+	size := region.Size().Mul(scale)
+	z.Image = image.NewRGBA(image.Rectangle{Max: z.Offset.Add(size)})
+	draw.Draw(z.Image, z.Image.Rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	var scaler draw.Scaler = draw.NearestNeighbor
+	if filter == ZoomSmooth {
+		scaler = draw.CatmullRom
+	}
+	scaler.Scale(z.Image, image.Rectangle{Min: z.Offset, Max: z.Offset.Add(size)}, captured, captured.Bounds(), draw.Src, nil)
+
+	z.drawLegend()
+	return z, nil
+}
+
+// drawLegend draws lines every Step units over the zoomed region, labelled
+// with their coordinates in the margins
+func (z *Zoomed) drawLegend() {
+	line := color.RGBA{255, 0, 255, 160}
+	label := color.RGBA{128, 0, 128, 255}
+	area := z.Image.Rect
+
+	first := func(v int) int { return (v + z.Step - 1) / z.Step * z.Step }
+	for v := first(z.Region.Min.X); v < z.Region.Max.X; v += z.Step {
+		x := z.Offset.X + (v-z.Region.Min.X)*z.Scale
+		draw.Draw(z.Image, image.Rect(x, z.Offset.Y, x+1, area.Max.Y), image.NewUniform(line), image.Point{}, draw.Over)
+		drawLabel(z.Image, image.Pt(x, 0), strconv.Itoa(v), label, 1)
+	}
+	for v := first(z.Region.Min.Y); v < z.Region.Max.Y; v += z.Step {
+		y := z.Offset.Y + (v-z.Region.Min.Y)*z.Scale
+		draw.Draw(z.Image, image.Rect(z.Offset.X, y, area.Max.X, y+1), image.NewUniform(line), image.Point{}, draw.Over)
+		drawLabel(z.Image, image.Pt(0, y), strconv.Itoa(v), label, 1)
+	}
+}
+
+// legendStep returns the smallest step of 1, 2 or 5 times a power of ten
+// that puts legend lines at least legendSpacing pixels apart
+func legendStep(scale int) int {
+	for power := 1; ; power *= 10 {
+		for _, step := range legendSteps {
+			if step*power*scale >= legendSpacing {
+				return step * power
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Backend performs the primitive input and screen operations all functions of
+// this package are built on. Coordinates are logical unless noted otherwise.
+type Backend interface {
+	// MoveMouse moves the cursor to the specified coordinates instantly
+	MoveMouse(ctx context.Context, x, y int) error
+	// MouseToggle presses or releases a mouse button ("left", "right" or "middle")
+	MouseToggle(ctx context.Context, button string, down bool) error
+	// Click clicks a mouse button at the current position, twice if double is set
+	Click(ctx context.Context, button string, double bool) error
+	// Scroll scrolls the wheel by dx and dy steps (positive is right and down)
+	Scroll(ctx context.Context, dx, dy int) error
+
+	// KeyTap presses and releases a key while holding the given modifiers
+	KeyTap(ctx context.Context, key string, modifiers ...string) error
+	// KeyToggle presses or releases a key
+	KeyToggle(ctx context.Context, key string, down bool) error
+	// TypeString types text at the current keyboard focus
+	TypeString(ctx context.Context, text string) error
+
+	// MousePosition returns the cursor position
+	MousePosition(ctx context.Context) (x, y int, err error)
+	// ScreenSize returns the size of the main display
+	ScreenSize(ctx context.Context) (width, height int, err error)
+	// ScaleFactor returns the number of physical pixels per logical unit
+	ScaleFactor(ctx context.Context) (float64, error)
+	// CaptureScreen captures a region of the screen given in logical coordinates,
+	// or the whole screen if the region is empty; the image is in physical pixels
+	CaptureScreen(ctx context.Context, region image.Rectangle) (image.Image, error)
+	// ActiveWindowBounds returns the bounds of the focused window
+	ActiveWindowBounds(ctx context.Context) (image.Rectangle, error)
+}
+
+// ClickEvent is a mouse button press made through a backend
+type ClickEvent struct {
+	// Seq numbers the presses observed by a backend in increasing order
+	Seq    uint64
+	X, Y   int
+	Button string
+	Time   time.Time
+}
+
+// ClickObserver is implemented by backends that report the mouse button presses
+// made through them, including presses made by other processes
+type ClickObserver interface {
+	// ClicksSince returns the presses numbered after seq and the number of the
+	// latest press
+	ClicksSince(ctx context.Context, seq uint64) ([]ClickEvent, uint64, error)
+}
+
+var (
+	backendMu sync.RWMutex
+	backend   Backend = defaultBackend()
+)
+
+// SetBackend replaces the backend used by all functions of this package
+func SetBackend(b Backend) {
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	backend = b
+}
+
+// CurrentBackend returns the backend used by all functions of this package
+func CurrentBackend() Backend {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return backend
+}
+
+// OpenBackend opens the backend selected by spec: "" for the default backend,
+// "robotgo" for the local desktop through robotgo, "x11" or "x11:DISPLAY" for
+// an X11 display driven without cgo, "uinput" or "uinput:WIDTHxHEIGHT" for
+// virtual Linux input devices that also work on Wayland, or
+// "vnc://[:password@]host[:port]" for a remote desktop served over VNC (the
+// password may also be given in $VNC_PASSWORD). Backends holding a connection
+// or devices implement io.Closer.
+func OpenBackend(ctx context.Context, spec string) (Backend, error) {
+	switch {
+	case spec == "":
+		return defaultBackend(), nil
+	case spec == "robotgo":
+		return openRobotgo()
+	case spec == "x11" || strings.HasPrefix(spec, "x11:"):
+		return DialX11(ctx, strings.TrimPrefix(strings.TrimPrefix(spec, "x11"), ":"))
+	case spec == "uinput":
+		return openUinput(ctx, 0, 0)
+	case strings.HasPrefix(spec, "uinput:"):
+		var width, height int
+		size := strings.TrimPrefix(spec, "uinput:")
+		if _, err := fmt.Sscanf(size, "%dx%d", &width, &height); err != nil || width <= 0 || height <= 0 {
+			return nil, fmt.Errorf("invalid uinput backend %q: screen size must be WIDTHxHEIGHT", spec)
+		}
+		return openUinput(ctx, width, height)
+	case strings.HasPrefix(spec, "vnc://"):
+		address, password, err := parseVNCAddress(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VNC backend %q: %w", spec, err)
+		}
+		if password == "" {
+			password = os.Getenv("VNC_PASSWORD")
+		}
+		return DialVNC(ctx, address, password)
+	}
+	return nil, fmt.Errorf("unknown backend %q: must be robotgo, x11[:DISPLAY], uinput[:WIDTHxHEIGHT] or vnc://host[:port]", spec)
+}
@@ -0,0 +1,175 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CoordSpace identifies the unit a pair of screen coordinates is expressed in
+type CoordSpace int
+
+const (
+	// CoordLogical is the unit used by Move, Click and GetPosition
+	// (points on macOS, scaled pixels on HiDPI Windows and Linux)
+	CoordLogical CoordSpace = iota
+	// CoordPhysical is device pixels, the unit of images returned by CaptureScreenshot
+	CoordPhysical
+	// CoordScreenshot is pixels of a screenshot that was resized to an arbitrary size
+	CoordScreenshot
+)
+
+// String returns the name used for the space on the command line
+func (s CoordSpace) String() string {
+	switch s {
+	case CoordLogical:
+		return "logical"
+	case CoordPhysical:
+		return "physical"
+	case CoordScreenshot:
+		return "screenshot"
+	}
+	return fmt.Sprintf("CoordSpace(%d)", int(s))
+}
+
+// CoordSystem describes how the coordinate spaces relate on the current display
+// and which space incoming and outgoing coordinates are expressed in
+type CoordSystem struct {
+	// Space is the space coordinates passed to ToLogical and returned by FromLogical are in
+	Space CoordSpace
+	// LogicalWidth and LogicalHeight are the screen size in logical units
+	LogicalWidth, LogicalHeight int
+	// Scale is the number of physical pixels per logical unit
+	Scale float64
+	// ScreenshotWidth and ScreenshotHeight are the size of the screenshot space;
+	// zero means an unscaled screenshot, i.e. the physical screen size
+	ScreenshotWidth, ScreenshotHeight int
+}
+
+// NewCoordSystem returns the coordinate system of the main display for the given space
+func NewCoordSystem(space CoordSpace) CoordSystem {
+	w, h := GetScreenSize()
+	scale, err := CurrentBackend().ScaleFactor(context.Background())
+	if err != nil || scale <= 0 {
+		scale = 1.0
+	}
+	return CoordSystem{
+		Space:         space,
+		LogicalWidth:  w,
+		LogicalHeight: h,
+		Scale:         scale,
+	}
+}
+
+// ParseCoordSpace parses a coordinate space name: "logical", "physical", "screenshot"
+// or "screenshot:WIDTHxHEIGHT" for a screenshot resized to that size
+func ParseCoordSpace(spec string) (CoordSystem, error) {
+	name, size, hasSize := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), ":")
+
+	switch name {
+	case "logical", "":
+		if hasSize {
+			return CoordSystem{}, fmt.Errorf("coordinate space %q does not take a size", name)
+		}
+		return NewCoordSystem(CoordLogical), nil
+	case "physical":
+		if hasSize {
+			return CoordSystem{}, fmt.Errorf("coordinate space %q does not take a size", name)
+		}
+		return NewCoordSystem(CoordPhysical), nil
+	case "screenshot":
+		cs := NewCoordSystem(CoordScreenshot)
+		if hasSize {
+			w, h, err := parseSize(size)
+			if err != nil {
+				return CoordSystem{}, fmt.Errorf("invalid screenshot size %q: %w", size, err)
+			}
+			cs.ScreenshotWidth, cs.ScreenshotHeight = w, h
+		}
+		return cs, nil
+	}
+
+	return CoordSystem{}, fmt.Errorf("unknown coordinate space %q: must be logical, physical or screenshot[:WxH]", spec)
+}
+
+// PhysicalSize returns the screen size in physical pixels
+func (c CoordSystem) PhysicalSize() (width, height int) {
+	return round(float64(c.LogicalWidth) * c.Scale), round(float64(c.LogicalHeight) * c.Scale)
+}
+
+// ScreenshotSize returns the size of the screenshot space
+func (c CoordSystem) ScreenshotSize() (width, height int) {
+	if c.ScreenshotWidth > 0 && c.ScreenshotHeight > 0 {
+		return c.ScreenshotWidth, c.ScreenshotHeight
+	}
+	return c.PhysicalSize()
+}
+
+// Size returns the screen size expressed in the system's space
+func (c CoordSystem) Size() (width, height int) {
+	switch c.Space {
+	case CoordPhysical:
+		return c.PhysicalSize()
+	case CoordScreenshot:
+		return c.ScreenshotSize()
+	}
+	return c.LogicalWidth, c.LogicalHeight
+}
+
+// factors returns the number of units of the system's space per logical unit on each axis
+func (c CoordSystem) factors() (fx, fy float64) {
+	switch c.Space {
+	case CoordPhysical:
+		return c.Scale, c.Scale
+	case CoordScreenshot:
+		if c.LogicalWidth <= 0 || c.LogicalHeight <= 0 {
+			return 1, 1
+		}
+		w, h := c.ScreenshotSize()
+		return float64(w) / float64(c.LogicalWidth), float64(h) / float64(c.LogicalHeight)
+	}
+	return 1, 1
+}
+
+// ToLogical converts a point from the system's space to logical coordinates
+func (c CoordSystem) ToLogical(x, y int) (int, int) {
+	fx, fy := c.factors()
+	return round(float64(x) / fx), round(float64(y) / fy)
+}
+
+// FromLogical converts a point from logical coordinates to the system's space
+func (c CoordSystem) FromLogical(x, y int) (int, int) {
+	fx, fy := c.factors()
+	return round(float64(x) * fx), round(float64(y) * fy)
+}
+
+// Convert converts a point between two arbitrary spaces of the same display
+func (c CoordSystem) Convert(x, y int, from, to CoordSpace) (int, int) {
+	src, dst := c, c
+	src.Space, dst.Space = from, to
+	return dst.FromLogical(src.ToLogical(x, y))
+}
+
+// parseSize parses a size of the form WIDTHxHEIGHT
+func parseSize(s string) (width, height int, err error) {
+	ws, hs, ok := strings.Cut(strings.ToLower(s), "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected WIDTHxHEIGHT")
+	}
+	width, err = strconv.Atoi(ws)
+	if err != nil || width <= 0 {
+		return 0, 0, fmt.Errorf("width must be a positive integer")
+	}
+	height, err = strconv.Atoi(hs)
+	if err != nil || height <= 0 {
+		return 0, 0, fmt.Errorf("height must be a positive integer")
+	}
+	return width, height, nil
+}
+
+// round rounds a float to the nearest integer
+func round(f float64) int {
+	return int(math.Round(f))
+}
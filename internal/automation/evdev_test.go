@@ -0,0 +1,100 @@
+package automation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEvdevKeyForName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    evdevKey
+		wantErr bool
+	}{
+		{"enter", evdevKey{code: 28}, false},
+		{"Enter", evdevKey{code: 28}, false},
+		{"ctrl", evdevKey{code: 29}, false},
+		{"rshift", evdevKey{code: 54}, false},
+		{"a", evdevKey{code: 30}, false},
+		{"A", evdevKey{code: 30, shift: true}, false},
+		{"1", evdevKey{code: 2}, false},
+		{"!", evdevKey{code: 2, shift: true}, false},
+		{"/", evdevKey{code: 53}, false},
+		{"?", evdevKey{code: 53, shift: true}, false},
+		{"f1", evdevKey{code: 59}, false},
+		{"F10", evdevKey{code: 68}, false},
+		{"f11", evdevKey{code: 87}, false},
+		{"f12", evdevKey{code: 88}, false},
+		{"f13", evdevKey{code: 183}, false},
+		{"f24", evdevKey{code: 194}, false},
+		{"f25", evdevKey{}, true},
+		{"f1x", evdevKey{}, true},
+		{"é", evdevKey{}, true},
+		{"nosuchkey", evdevKey{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evdevKeyForName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evdevKeyForName(%q) error = %v, want error %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("evdevKeyForName(%q) = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvdevKeyForRune(t *testing.T) {
+	tests := []struct {
+		r       rune
+		want    evdevKey
+		wantErr bool
+	}{
+		{'q', evdevKey{code: 16}, false},
+		{'Q', evdevKey{code: 16, shift: true}, false},
+		{'{', evdevKey{code: 26, shift: true}, false},
+		{'"', evdevKey{code: 40, shift: true}, false},
+		{'~', evdevKey{code: 41, shift: true}, false},
+		{'\\', evdevKey{code: 43}, false},
+		{'|', evdevKey{code: 43, shift: true}, false},
+		{' ', evdevKey{code: 57}, false},
+		{'\n', evdevKey{code: 28}, false},
+		{'\t', evdevKey{code: 15}, false},
+		{'€', evdevKey{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.r), func(t *testing.T) {
+			got, err := evdevKeyForRune(tt.r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evdevKeyForRune(%q) error = %v, want error %v", tt.r, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("evdevKeyForRune(%q) = %+v, want %+v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvdevComboCodes(t *testing.T) {
+	const shift, rshift, ctrl, a, slash = 42, 54, 29, 30, 53
+	tests := []struct {
+		name string
+		keys []evdevKey
+		want []uint16
+	}{
+		{"plain key", []evdevKey{{code: a}}, []uint16{a}},
+		{"shifted key", []evdevKey{{code: a, shift: true}}, []uint16{shift, a}},
+		{"shifted symbol", []evdevKey{{code: slash, shift: true}}, []uint16{shift, slash}},
+		{"modifier and shifted key", []evdevKey{{code: ctrl}, {code: a, shift: true}}, []uint16{shift, ctrl, a}},
+		{"shift already held", []evdevKey{{code: shift}, {code: a, shift: true}}, []uint16{shift, a}},
+		{"right shift held", []evdevKey{{code: rshift}, {code: a, shift: true}}, []uint16{rshift, a}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evdevComboCodes(tt.keys); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("evdevComboCodes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
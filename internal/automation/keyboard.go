@@ -1,83 +1,96 @@
 package automation
 
 import (
-	"github.com/go-vgo/robotgo"
+	"context"
+	"time"
 )
 
 // TypeText types the specified text at the current cursor position
-func TypeText(text string) error {
-	robotgo.TypeStr(text)
-	return nil
+func TypeText(ctx context.Context, text string) error {
+	return TypeString(ctx, text)
 }
 
 // PressKey presses a single key
-func PressKey(key string) error {
-	robotgo.KeyTap(key)
-	return nil
+func PressKey(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return CurrentBackend().KeyTap(ctx, key)
 }
 
 // PressKeyCombo presses a key combination (e.g., "ctrl", "c")
-func PressKeyCombo(keys ...string) error {
-	if len(keys) == 0 {
-		return nil
+func PressKeyCombo(ctx context.Context, keys ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
-	if len(keys) == 1 {
-		robotgo.KeyTap(keys[0])
+	if len(keys) == 0 {
 		return nil
 	}
-
-	// Convert []string to []interface{} for robotgo.KeyTap
-	modifiers := make([]interface{}, len(keys)-1)
-	for i, key := range keys[:len(keys)-1] {
-		modifiers[i] = key
-	}
-	robotgo.KeyTap(keys[len(keys)-1], modifiers...)
-	return nil
+	return CurrentBackend().KeyTap(ctx, keys[len(keys)-1], keys[:len(keys)-1]...)
 }
 
 // HoldKey holds down a key
-func HoldKey(key string) error {
-	robotgo.KeyToggle(key, "down")
-	return nil
+func HoldKey(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return CurrentBackend().KeyToggle(ctx, key, true)
 }
 
-// ReleaseKey releases a held key
-func ReleaseKey(key string) error {
-	robotgo.KeyToggle(key, "up")
-	return nil
+// ReleaseKey releases a held key. It does not check ctx so held keys can
+// always be released during cleanup of a cancelled operation.
+func ReleaseKey(ctx context.Context, key string) error {
+	return CurrentBackend().KeyToggle(context.WithoutCancel(ctx), key, false)
 }
 
 // TypeWithDelay types text with a delay between characters (milliseconds)
-func TypeWithDelay(text string, delay int) error {
-	for _, char := range text {
-		robotgo.TypeStr(string(char))
-		robotgo.MilliSleep(delay)
-	}
-	return nil
+func TypeWithDelay(ctx context.Context, text string, delay int) error {
+	return TypeStringWithDelay(ctx, text, delay)
 }
 
-// TypeString types the specified text using robotgo.TypeStr with safety checks
-func TypeString(text string) error {
-	// Safety check for empty strings
-	if text == "" {
-		return nil
-	}
-	robotgo.TypeStr(text)
-	return nil
+// TypeString types the specified text with safety checks, one character at a
+// time so it can be cancelled between keystrokes
+func TypeString(ctx context.Context, text string) error {
+	return TypeStringWithDelay(ctx, text, 0)
 }
 
-// TypeStringWithDelay types text with a delay between characters using robotgo.TypeStr
-func TypeStringWithDelay(text string, delayMs int) error {
+// TypeStringWithDelay types text with a delay between characters
+func TypeStringWithDelay(ctx context.Context, text string, delayMs int) error {
 	// Safety check for empty strings
 	if text == "" {
 		return nil
 	}
 
+	b := CurrentBackend()
 	for _, char := range text {
-		robotgo.TypeStr(string(char))
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := b.TypeString(ctx, string(char)); err != nil {
+			return err
+		}
 		if delayMs > 0 {
-			robotgo.MilliSleep(delayMs)
+			if err := sleep(ctx, time.Duration(delayMs)*time.Millisecond); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
+
+// sleep pauses for d or until ctx is done, returning ctx.Err() if it was cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,191 @@
+package automation
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// markScreen returns a white screenshot of width x height pixels with the
+// given rectangles filled black
+func markScreen(width, height int, rects ...image.Rectangle) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Rect, image.White, image.Point{}, draw.Src)
+	for _, r := range rects {
+		draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+	}
+	return img
+}
+
+// outline returns the four sides of r, one pixel wide
+func outline(r image.Rectangle) []image.Rectangle {
+	return []image.Rectangle{
+		image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+1),
+		image.Rect(r.Min.X, r.Max.Y-1, r.Max.X, r.Max.Y),
+		image.Rect(r.Min.X, r.Min.Y, r.Min.X+1, r.Max.Y),
+		image.Rect(r.Max.X-1, r.Min.Y, r.Max.X, r.Max.Y),
+	}
+}
+
+// nearRect reports whether the corners of a and b differ by at most d
+func nearRect(a, b image.Rectangle, d int) bool {
+	return abs(a.Min.X-b.Min.X) <= d && abs(a.Min.Y-b.Min.Y) <= d &&
+		abs(a.Max.X-b.Max.X) <= d && abs(a.Max.Y-b.Max.Y) <= d
+}
+
+func TestEdgeMarks(t *testing.T) {
+	button := image.Rect(50, 40, 110, 60)
+	box := image.Rect(60, 100, 160, 140)
+
+	tests := []struct {
+		name   string
+		img    *image.RGBA
+		screen image.Rectangle
+		want   []image.Rectangle
+	}{
+		{
+			name:   "blank",
+			img:    markScreen(400, 300),
+			screen: image.Rect(0, 0, 400, 300),
+		},
+		{
+			name:   "element",
+			img:    markScreen(400, 300, button),
+			screen: image.Rect(0, 0, 400, 300),
+			want:   []image.Rectangle{button},
+		},
+		{
+			name:   "speck is skipped",
+			img:    markScreen(400, 300, image.Rect(200, 200, 202, 201)),
+			screen: image.Rect(0, 0, 400, 300),
+		},
+		{
+			name:   "large area is skipped",
+			img:    markScreen(400, 300, image.Rect(20, 20, 300, 250)),
+			screen: image.Rect(0, 0, 400, 300),
+		},
+		{
+			name:   "label inside an outline is part of it",
+			img:    markScreen(400, 300, append(outline(box), image.Rect(90, 115, 120, 125))...),
+			screen: image.Rect(0, 0, 400, 300),
+			want:   []image.Rectangle{box},
+		},
+		{
+			name:   "scaled screenshot",
+			img:    markScreen(800, 600, image.Rect(100, 80, 220, 120)),
+			screen: image.Rect(0, 0, 400, 300),
+			want:   []image.Rectangle{button},
+		},
+		{
+			name:   "too small to sample",
+			img:    markScreen(1, 1),
+			screen: image.Rect(0, 0, 400, 300),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marks := edgeMarks(tt.img, tt.screen)
+			if len(marks) != len(tt.want) {
+				t.Fatalf("edgeMarks() = %v, want %d marks", marks, len(tt.want))
+			}
+			for i, m := range marks {
+				if !nearRect(m.Bounds, tt.want[i], 3) {
+					t.Errorf("mark %d bounds = %v, want about %v", i, m.Bounds, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFindMarksOrder(t *testing.T) {
+	useFakeBackend(t, &fakeBackend{width: 400, height: 300, scale: 1})
+
+	// b starts a few units below a, so they share a row and b, being further
+	// left, comes first
+	a := image.Rect(300, 50, 340, 65)
+	b := image.Rect(50, 56, 90, 71)
+	c := image.Rect(20, 150, 60, 165)
+	img := markScreen(400, 300, a, c, b)
+
+	marks, err := FindMarks(context.Background(), img, MarksEdges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []image.Rectangle{b, a, c}
+	if len(marks) != len(want) {
+		t.Fatalf("FindMarks() = %v, want %d marks", marks, len(want))
+	}
+	for i, m := range marks {
+		if m.ID != i+1 {
+			t.Errorf("mark %d has ID %d", i, m.ID)
+		}
+		if !nearRect(m.Bounds, want[i], 3) {
+			t.Errorf("mark %d bounds = %v, want about %v", m.ID, m.Bounds, want[i])
+		}
+		if center := m.Bounds.Min.Add(m.Bounds.Max).Div(2); m.X != center.X || m.Y != center.Y {
+			t.Errorf("mark %d at (%d, %d), want the centre %v", m.ID, m.X, m.Y, center)
+		}
+	}
+}
+
+func TestFindMarksLimit(t *testing.T) {
+	useFakeBackend(t, &fakeBackend{width: 1600, height: 1200, scale: 1})
+
+	var rects []image.Rectangle
+	for y := 10; y+10 < 1200; y += 25 {
+		for x := 10; x+20 < 1600; x += 40 {
+			rects = append(rects, image.Rect(x, y, x+20, y+10))
+		}
+	}
+	marks, err := FindMarks(context.Background(), markScreen(1600, 1200, rects...), MarksEdges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(marks) != maxMarks {
+		t.Fatalf("FindMarks() returned %d marks, want %d", len(marks), maxMarks)
+	}
+	if last := marks[len(marks)-1]; last.ID != maxMarks {
+		t.Errorf("last mark has ID %d, want %d", last.ID, maxMarks)
+	}
+}
+
+func TestDrawGrid(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+
+	// A 400x200 screen captured at half its size: lines every 100 units are
+	// 50 pixels apart
+	img := markScreen(200, 100)
+	DrawGrid(img, 400, 200, 100)
+
+	lines := []image.Point{{50, 80}, {100, 80}, {150, 80}, {180, 50}}
+	for _, p := range lines {
+		c := img.RGBAAt(p.X, p.Y)
+		if c == white || c.R < c.G || c.B < c.G {
+			t.Errorf("pixel %v = %v, want a grid line", p, c)
+		}
+	}
+	for _, p := range []image.Point{{25, 80}, {75, 30}, {180, 75}} {
+		if c := img.RGBAAt(p.X, p.Y); c != white {
+			t.Errorf("pixel %v = %v, want no grid line", p, c)
+		}
+	}
+	// Lines are labelled along the top and left edges
+	for _, p := range []image.Point{{53, 3}, {3, 53}} {
+		if c := img.RGBAAt(p.X, p.Y); c == white {
+			t.Errorf("pixel %v is white, want a label", p)
+		}
+	}
+
+	for _, tt := range []struct{ width, height, step int }{{400, 200, 0}, {0, 200, 100}, {400, 0, 100}} {
+		img := markScreen(200, 100)
+		DrawGrid(img, tt.width, tt.height, tt.step)
+		for i, v := range img.Pix {
+			if v != 255 {
+				t.Errorf("DrawGrid(%d, %d, %d) drew at pixel %d", tt.width, tt.height, tt.step, i/4)
+				break
+			}
+		}
+	}
+}
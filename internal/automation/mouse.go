@@ -1,14 +1,17 @@
 package automation
 
 import (
+	"context"
 	"fmt"
 	"time"
-
-	"github.com/go-vgo/robotgo"
 )
 
 // Click performs a mouse click at the specified coordinates with validation
-func Click(x, y int) error {
+func Click(ctx context.Context, x, y int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Validate coordinates are non-negative
 	if x < 0 {
 		return fmt.Errorf("x coordinate cannot be negative: %d", x)
@@ -18,7 +21,7 @@ func Click(x, y int) error {
 	}
 
 	// Get screen dimensions for validation
-	screenWidth, screenHeight := robotgo.GetScreenSize()
+	screenWidth, screenHeight := GetScreenSize()
 	if x > screenWidth {
 		return fmt.Errorf("x coordinate %d exceeds screen width %d", x, screenWidth)
 	}
@@ -27,20 +30,26 @@ func Click(x, y int) error {
 	}
 
 	// Move to position and click
-	robotgo.Move(x, y)
-	robotgo.Click()
-
-	return nil
+	b := CurrentBackend()
+	if err := b.MoveMouse(ctx, x, y); err != nil {
+		return err
+	}
+	return b.Click(ctx, "left", false)
 }
 
-// GetPosition returns the current mouse position
+// GetPosition returns the current mouse position, or (0, 0) if the backend
+// cannot report it
 func GetPosition() (x, y int) {
-	x, y = robotgo.GetMousePos()
+	x, y, _ = CurrentBackend().MousePosition(context.Background())
 	return x, y
 }
 
 // MoveMouse moves the mouse cursor to the specified coordinates
-func MoveMouse(x, y int) error {
+func MoveMouse(ctx context.Context, x, y int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Validate coordinates are non-negative
 	if x < 0 {
 		return fmt.Errorf("x coordinate cannot be negative: %d", x)
@@ -49,12 +58,15 @@ func MoveMouse(x, y int) error {
 		return fmt.Errorf("y coordinate cannot be negative: %d", y)
 	}
 
-	robotgo.Move(x, y)
-	return nil
+	return CurrentBackend().MoveMouse(ctx, x, y)
 }
 
 // DoubleClick performs a double click at the specified coordinates
-func DoubleClick(x, y int) error {
+func DoubleClick(ctx context.Context, x, y int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Validate coordinates are non-negative
 	if x < 0 {
 		return fmt.Errorf("x coordinate cannot be negative: %d", x)
@@ -63,13 +75,19 @@ func DoubleClick(x, y int) error {
 		return fmt.Errorf("y coordinate cannot be negative: %d", y)
 	}
 
-	robotgo.Move(x, y)
-	robotgo.Click("left", true)
-	return nil
+	b := CurrentBackend()
+	if err := b.MoveMouse(ctx, x, y); err != nil {
+		return err
+	}
+	return b.Click(ctx, "left", true)
 }
 
 // RightClick performs a right click at the specified coordinates
-func RightClick(x, y int) error {
+func RightClick(ctx context.Context, x, y int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Validate coordinates are non-negative
 	if x < 0 {
 		return fmt.Errorf("x coordinate cannot be negative: %d", x)
@@ -78,9 +96,33 @@ func RightClick(x, y int) error {
 		return fmt.Errorf("y coordinate cannot be negative: %d", y)
 	}
 
-	robotgo.Move(x, y)
-	robotgo.Click("right")
-	return nil
+	b := CurrentBackend()
+	if err := b.MoveMouse(ctx, x, y); err != nil {
+		return err
+	}
+	return b.Click(ctx, "right", false)
+}
+
+// Scroll scrolls the mouse wheel by dx and dy steps; positive values scroll right and down
+func Scroll(ctx context.Context, dx, dy int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return CurrentBackend().Scroll(ctx, dx, dy)
+}
+
+// MouseDown presses and holds a mouse button ("left", "right" or "middle") at the current position
+func MouseDown(ctx context.Context, button string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return CurrentBackend().MouseToggle(ctx, button, true)
+}
+
+// MouseUp releases a held mouse button. Like ReleaseKey it does not check ctx
+// so held buttons can always be released during cleanup.
+func MouseUp(ctx context.Context, button string) error {
+	return CurrentBackend().MouseToggle(context.WithoutCancel(ctx), button, false)
 }
 
 // GetMousePos returns the current mouse position (legacy function for compatibility)
@@ -89,35 +131,11 @@ func GetMousePos() (int, int) {
 }
 
 // Move moves the mouse cursor to the specified coordinates instantly
-func Move(x, y int) error {
-	// Validate coordinates are non-negative
-	if x < 0 {
-		return fmt.Errorf("x coordinate cannot be negative: %d", x)
-	}
-	if y < 0 {
-		return fmt.Errorf("y coordinate cannot be negative: %d", y)
-	}
-
-	// Get screen dimensions for validation
-	screenWidth, screenHeight := robotgo.GetScreenSize()
-	if x > screenWidth {
-		return fmt.Errorf("x coordinate %d exceeds screen width %d", x, screenWidth)
-	}
-	if y > screenHeight {
-		return fmt.Errorf("y coordinate %d exceeds screen height %d", y, screenHeight)
+func Move(ctx context.Context, x, y int) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	// Use MoveSmooth with very short duration for better reliability on macOS
-	robotgo.MoveSmooth(x, y, 0.1, 0.1)
-
-	// Add small delay to ensure movement completes
-	time.Sleep(50 * time.Millisecond)
-
-	return nil
-}
-
-// SmoothMove moves the mouse cursor to the specified coordinates with smooth animation
-func SmoothMove(x, y int, duration float64) error {
 	// Validate coordinates are non-negative
 	if x < 0 {
 		return fmt.Errorf("x coordinate cannot be negative: %d", x)
@@ -126,13 +144,8 @@ func SmoothMove(x, y int, duration float64) error {
 		return fmt.Errorf("y coordinate cannot be negative: %d", y)
 	}
 
-	// Validate duration is positive
-	if duration <= 0 {
-		return fmt.Errorf("duration must be positive: %f", duration)
-	}
-
 	// Get screen dimensions for validation
-	screenWidth, screenHeight := robotgo.GetScreenSize()
+	screenWidth, screenHeight := GetScreenSize()
 	if x > screenWidth {
 		return fmt.Errorf("x coordinate %d exceeds screen width %d", x, screenWidth)
 	}
@@ -140,10 +153,18 @@ func SmoothMove(x, y int, duration float64) error {
 		return fmt.Errorf("y coordinate %d exceeds screen height %d", y, screenHeight)
 	}
 
-	robotgo.MoveSmooth(x, y, duration, duration)
+	// Use a very short smooth movement for better reliability on macOS
+	err := MoveAlongPath(ctx, x, y, TrajectoryOptions{Path: PathLinear, Easing: EaseNone, Duration: 0.1})
+	if err != nil {
+		return err
+	}
 
 	// Add small delay to ensure movement completes
-	time.Sleep(100 * time.Millisecond)
+	return sleep(ctx, 50*time.Millisecond)
+}
 
-	return nil
+// SmoothMove moves the mouse cursor to the specified coordinates with smooth animation
+// along a straight line; use MoveAlongPath for curved, human-like trajectories
+func SmoothMove(ctx context.Context, x, y int, duration float64) error {
+	return MoveAlongPath(ctx, x, y, TrajectoryOptions{Path: PathLinear, Duration: duration})
 }
@@ -0,0 +1,175 @@
+package automation
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"reflect"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.RGBA
+		wantErr bool
+	}{
+		{"#ff8000", color.RGBA{255, 128, 0, 255}, false},
+		{"#FF8000", color.RGBA{255, 128, 0, 255}, false},
+		{"ff8000", color.RGBA{255, 128, 0, 255}, false},
+		{"#f80", color.RGBA{255, 136, 0, 255}, false},
+		{"255,128,0", color.RGBA{255, 128, 0, 255}, false},
+		{" 1, 2 , 3 ", color.RGBA{1, 2, 3, 255}, false},
+		{"#000000", color.RGBA{0, 0, 0, 255}, false},
+		{"#ff80", color.RGBA{}, true},
+		{"#ff800000", color.RGBA{}, true},
+		{"#gg0000", color.RGBA{}, true},
+		{"256,0,0", color.RGBA{}, true},
+		{"1,2", color.RGBA{}, true},
+		{"1,2,3,4", color.RGBA{}, true},
+		{"-1,0,0", color.RGBA{}, true},
+		{"red", color.RGBA{}, true},
+		{"", color.RGBA{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseColor(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColor(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColor(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHexColor(t *testing.T) {
+	for _, s := range []string{"#000000", "#ff8000", "#0a0b0c", "#ffffff"} {
+		c, err := ParseColor(s)
+		if err != nil {
+			t.Fatalf("ParseColor(%q) error = %v", s, err)
+		}
+		if got := HexColor(c); got != s {
+			t.Errorf("HexColor(ParseColor(%q)) = %q", s, got)
+		}
+	}
+}
+
+// colorScreen returns a fake screen of width x height logical units at scale
+// filled with background, with the given logical rectangles painted red
+func colorScreen(width, height int, scale float64, rects ...image.Rectangle) *fakeBackend {
+	background := color.RGBA{0, 0, 255, 255}
+	red := color.RGBA{255, 0, 0, 255}
+
+	img := image.NewRGBA(image.Rect(0, 0, int(float64(width)*scale), int(float64(height)*scale)))
+	draw.Draw(img, img.Rect, image.NewUniform(background), image.Point{}, draw.Src)
+	for _, r := range rects {
+		physical := image.Rect(int(float64(r.Min.X)*scale), int(float64(r.Min.Y)*scale),
+			int(float64(r.Max.X)*scale), int(float64(r.Max.Y)*scale))
+		draw.Draw(img, physical, image.NewUniform(red), image.Point{}, draw.Src)
+	}
+	return &fakeBackend{width: width, height: height, scale: scale, screen: img}
+}
+
+func TestFindColor(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	square := image.Rect(10, 10, 20, 20)
+	dot := image.Rect(50, 30, 52, 32)
+
+	tests := []struct {
+		name      string
+		backend   *fakeBackend
+		region    image.Rectangle
+		target    color.RGBA
+		tolerance uint8
+		want      []ColorBlob
+	}{
+		{
+			name:    "largest blob first",
+			backend: colorScreen(100, 50, 1, dot, square),
+			target:  red,
+			want: []ColorBlob{
+				{X: 15, Y: 15, Bounds: square, Pixels: 100},
+				{X: 51, Y: 31, Bounds: dot, Pixels: 4},
+			},
+		},
+		{
+			name:    "diagonal neighbours form one blob",
+			backend: colorScreen(100, 50, 1, image.Rect(0, 0, 1, 1), image.Rect(1, 1, 2, 2), image.Rect(2, 2, 3, 3)),
+			target:  red,
+			want:    []ColorBlob{{X: 1, Y: 1, Bounds: image.Rect(0, 0, 3, 3), Pixels: 3}},
+		},
+		{
+			name:    "no match",
+			backend: colorScreen(100, 50, 1, square),
+			target:  color.RGBA{0, 255, 0, 255},
+		},
+		{
+			name:      "within tolerance",
+			backend:   colorScreen(100, 50, 1, square),
+			target:    color.RGBA{245, 10, 0, 255},
+			tolerance: 10,
+			want:      []ColorBlob{{X: 15, Y: 15, Bounds: square, Pixels: 100}},
+		},
+		{
+			name:      "outside tolerance",
+			backend:   colorScreen(100, 50, 1, square),
+			target:    color.RGBA{245, 10, 0, 255},
+			tolerance: 9,
+		},
+		{
+			name:    "scaled display",
+			backend: colorScreen(100, 50, 2, square),
+			target:  red,
+			want:    []ColorBlob{{X: 15, Y: 15, Bounds: square, Pixels: 400}},
+		},
+		{
+			name:    "region",
+			backend: colorScreen(100, 50, 1, square, dot),
+			region:  image.Rect(5, 5, 25, 25),
+			target:  red,
+			want:    []ColorBlob{{X: 15, Y: 15, Bounds: square, Pixels: 100}},
+		},
+		{
+			name:    "region cutting a blob",
+			backend: colorScreen(100, 50, 1, square),
+			region:  image.Rect(15, 0, 40, 40),
+			target:  red,
+			want:    []ColorBlob{{X: 17, Y: 15, Bounds: image.Rect(15, 10, 20, 20), Pixels: 50}},
+		},
+		{
+			name:    "region beyond the screen",
+			backend: colorScreen(100, 50, 1, dot),
+			region:  image.Rect(40, 20, 140, 120),
+			target:  red,
+			want:    []ColorBlob{{X: 51, Y: 31, Bounds: dot, Pixels: 4}},
+		},
+		{
+			name:    "region beyond the origin of a scaled display",
+			backend: colorScreen(100, 50, 2, square),
+			region:  image.Rect(-50, -50, 30, 30),
+			target:  red,
+			want:    []ColorBlob{{X: 15, Y: 15, Bounds: square, Pixels: 400}},
+		},
+		{
+			name:    "region outside the screen",
+			backend: colorScreen(100, 50, 1, square),
+			region:  image.Rect(200, 200, 300, 300),
+			target:  red,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeBackend(t, tt.backend)
+			got, err := FindColor(context.Background(), tt.region, tt.target, tt.tolerance)
+			if err != nil {
+				t.Fatalf("FindColor() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindColor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
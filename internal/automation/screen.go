@@ -1,23 +1,32 @@
 package automation
 
 import (
+	"context"
 	"fmt"
+	"image"
+	"image/png"
 	"os"
 	"path/filepath"
 	"time"
 
-	"github.com/go-vgo/robotgo"
+	"golang.org/x/image/draw"
 )
 
 // CaptureScreenshot captures the full screen and saves it to a temporary location
-// Returns the path to the saved screenshot file
-func CaptureScreenshot() (string, error) {
+// Returns the path to the saved screenshot file. The image is in physical pixels,
+// use CoordSystem to map coordinates read off it to the logical space Move expects
+func CaptureScreenshot(ctx context.Context) (string, error) {
 	// Capture the full screen
-	img := robotgo.CaptureImg()
-	if img == nil {
-		return "", fmt.Errorf("failed to capture screen: image is nil")
+	img, err := CaptureImage(ctx, image.Rectangle{})
+	if err != nil {
+		return "", err
 	}
+	return SaveScreenshot(img)
+}
 
+// SaveScreenshot saves an image as a timestamped PNG file in the temporary
+// directory and returns its path
+func SaveScreenshot(img image.Image) (string, error) {
 	// Generate unique filename with timestamp
 	timestamp := time.Now().Format("20060102_150405")
 	filename := fmt.Sprintf("screenshot_%s.png", timestamp)
@@ -27,15 +36,72 @@ func CaptureScreenshot() (string, error) {
 	filePath := filepath.Join(tempDir, filename)
 
 	// Save the screenshot
-	err := robotgo.Save(img, filePath)
-	if err != nil {
+	if err := SavePNG(img, filePath); err != nil {
 		return "", fmt.Errorf("failed to save screenshot to %s: %w", filePath, err)
 	}
 
 	return filePath, nil
 }
 
-// GetScreenSize returns the screen dimensions
+// CaptureImage captures a region of the screen given in logical coordinates,
+// or the full screen if the region is empty. The image is in physical pixels.
+func CaptureImage(ctx context.Context, region image.Rectangle) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return CurrentBackend().CaptureScreen(ctx, region)
+}
+
+// ResizeImage scales an image to width x height pixels, e.g. a physical
+// capture to the size of the screenshot coordinate space
+func ResizeImage(img image.Image, width, height int) image.Image {
+	if b := img.Bounds(); b.Dx() == width && b.Dy() == height {
+		return img
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Src, nil)
+	return dst
+}
+
+// SavePNG writes an image to a PNG file
+func SavePNG(img image.Image, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// GetScreenSize returns the screen dimensions in logical coordinates, or
+// (0, 0) if the backend cannot report them
 func GetScreenSize() (width, height int) {
-	return robotgo.GetScreenSize()
+	width, height, _ = CurrentBackend().ScreenSize(context.Background())
+	return width, height
+}
+
+// MonitorLister is implemented by backends that can report the monitors
+// making up their screen
+type MonitorLister interface {
+	Monitors(ctx context.Context) ([]image.Rectangle, error)
+}
+
+// Monitors returns the bounds of the monitors in logical coordinates with the
+// primary monitor first. Backends that cannot list monitors report the whole
+// screen as a single monitor.
+func Monitors(ctx context.Context) ([]image.Rectangle, error) {
+	b := CurrentBackend()
+	if lister, ok := b.(MonitorLister); ok {
+		return lister.Monitors(ctx)
+	}
+
+	w, h, err := b.ScreenSize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []image.Rectangle{image.Rect(0, 0, w, h)}, nil
 }
@@ -0,0 +1,120 @@
+package automation
+
+import (
+	"errors"
+	"image"
+	"testing"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    Target
+		wantErr bool
+	}{
+		{args: []string{"100,200"}, want: Target{x: axis{axisAbsolute, 100}, y: axis{axisAbsolute, 200}}},
+		{args: []string{"100", "200"}, want: Target{x: axis{axisAbsolute, 100}, y: axis{axisAbsolute, 200}}},
+		{args: []string{" 100 , 200 "}, want: Target{x: axis{axisAbsolute, 100}, y: axis{axisAbsolute, 200}}},
+		{args: []string{"+50,-20"}, want: Target{x: axis{axisRelative, 50}, y: axis{axisRelative, -20}}},
+		{args: []string{"50%", "12.5%"}, want: Target{x: axis{axisPercent, 50}, y: axis{axisPercent, 12.5}}},
+		{args: []string{"10,+0"}, want: Target{x: axis{axisAbsolute, 10}, y: axis{axisRelative, 0}}},
+		{args: []string{"@Center"}, want: Target{anchor: "center"}},
+		{args: []string{"@save"}, want: Target{anchor: "save"}},
+		{args: []string{"@"}, wantErr: true},
+		{args: []string{"100"}, wantErr: true},
+		{args: []string{"100,"}, wantErr: true},
+		{args: []string{"abc", "1"}, wantErr: true},
+		{args: []string{"1.5", "1"}, wantErr: true},
+		{args: []string{"101%", "1"}, wantErr: true},
+		{args: []string{"-1%", "1"}, wantErr: true},
+		{args: []string{"1", "2", "3"}, wantErr: true},
+		{args: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTarget(tt.args...)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseTarget(%q) = %+v, want error", tt.args, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTarget(%q) failed: %v", tt.args, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTarget(%q) = %+v, want %+v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestTargetResolve(t *testing.T) {
+	useFakeBackend(t, &fakeBackend{
+		width: 1000, height: 500, scale: 2,
+		window: image.Rect(100, 100, 301, 201),
+		x:      40, y: 60,
+	})
+	if err := SetAnchor("save", 700, 30); err != nil {
+		t.Fatal(err)
+	}
+	logical := CoordSystem{Space: CoordLogical, LogicalWidth: 1000, LogicalHeight: 500, Scale: 2}
+	physical := logical
+	physical.Space = CoordPhysical
+
+	tests := []struct {
+		expr  string
+		cs    CoordSystem
+		frame Frame
+		x, y  int
+	}{
+		{"100,200", logical, FrameScreen, 100, 200},
+		{"100,200", physical, FrameScreen, 50, 100},
+		{"+10,-10", logical, FrameScreen, 50, 50},
+		{"+10,-10", physical, FrameScreen, 45, 55},
+		{"0%,100%", logical, FrameScreen, 0, 499},
+		{"50%,50%", logical, FrameWindow, 200, 150},
+		{"10,50%", logical, FrameWindow, 10, 150},
+		{"@center", logical, FrameScreen, 500, 250},
+		{"@bottom-right", logical, FrameWindow, 300, 200},
+		{"@cursor", physical, FrameScreen, 40, 60},
+		{"@save", logical, FrameWindow, 700, 30},
+	}
+	for _, tt := range tests {
+		target, err := ParseTarget(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseTarget(%q) failed: %v", tt.expr, err)
+		}
+		x, y, err := target.Resolve(tt.cs, tt.frame)
+		if err != nil {
+			t.Errorf("Resolve(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		if x != tt.x || y != tt.y {
+			t.Errorf("Resolve(%q, %s) = (%d, %d), want (%d, %d)", tt.expr, tt.cs.Space, x, y, tt.x, tt.y)
+		}
+	}
+
+	unknown, _ := ParseTarget("@unknown")
+	if _, _, err := unknown.Resolve(logical, FrameScreen); err == nil {
+		t.Error("Resolve(@unknown) succeeded, want error")
+	}
+}
+
+func TestTargetResolveWithoutWindow(t *testing.T) {
+	useFakeBackend(t, &fakeBackend{width: 1000, height: 500, scale: 1, windowErr: errors.New("no active window")})
+	cs := CoordSystem{Space: CoordLogical, LogicalWidth: 1000, LogicalHeight: 500, Scale: 1}
+
+	// Targets that do not use the frame resolve without the window
+	for _, expr := range []string{"100,200", "+0,+0", "@cursor"} {
+		target, _ := ParseTarget(expr)
+		if _, _, err := target.Resolve(cs, FrameWindow); err != nil {
+			t.Errorf("Resolve(%q) failed: %v", expr, err)
+		}
+	}
+	for _, expr := range []string{"50%,10", "@center"} {
+		target, _ := ParseTarget(expr)
+		if _, _, err := target.Resolve(cs, FrameWindow); err == nil {
+			t.Errorf("Resolve(%q) succeeded without an active window, want error", expr)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// PathKind selects the shape of a mouse trajectory
+type PathKind int
+
+const (
+	// PathLinear moves along a straight line
+	PathLinear PathKind = iota
+	// PathBezier moves along a cubic Bezier curve with randomized control points
+	PathBezier
+	// PathWind moves along a path simulated with gravity and random wind forces
+	PathWind
+)
+
+// ParsePathKind parses a path name: "linear", "bezier" or "wind"
+func ParsePathKind(s string) (PathKind, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "linear", "":
+		return PathLinear, nil
+	case "bezier":
+		return PathBezier, nil
+	case "wind":
+		return PathWind, nil
+	}
+	return PathLinear, fmt.Errorf("unknown path %q: must be linear, bezier or wind", s)
+}
+
+// Easing is the velocity profile along a trajectory
+type Easing int
+
+const (
+	// EaseInOut accelerates at the start and decelerates at the end
+	EaseInOut Easing = iota
+	// EaseOut starts fast and decelerates towards the target
+	EaseOut
+	// EaseNone moves at constant velocity
+	EaseNone
+)
+
+// ParseEasing parses an easing name: "in-out", "out" or "none"
+func ParseEasing(s string) (Easing, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "in-out", "":
+		return EaseInOut, nil
+	case "out":
+		return EaseOut, nil
+	case "none", "linear":
+		return EaseNone, nil
+	}
+	return EaseInOut, fmt.Errorf("unknown easing %q: must be in-out, out or none", s)
+}
+
+// apply maps linear progress t in [0, 1] to eased progress
+func (e Easing) apply(t float64) float64 {
+	switch e {
+	case EaseOut:
+		return 1 - math.Pow(1-t, 3)
+	case EaseNone:
+		return t
+	}
+	// Smoothstep-like cubic ease-in-out
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	return 1 - math.Pow(-2*t+2, 3)/2
+}
+
+// TrajectoryOptions configures a smooth mouse movement
+type TrajectoryOptions struct {
+	// Path is the shape of the trajectory
+	Path PathKind
+	// Easing is the velocity profile along the path
+	Easing Easing
+	// Duration is the total movement time in seconds
+	Duration float64
+	// Overshoot moves slightly past the target and corrects back, like a human hand
+	Overshoot bool
+	// Jitter is the standard deviation in pixels of random noise added to each step
+	Jitter float64
+	// Seed makes the randomized parts of the trajectory reproducible; 0 picks a random seed
+	Seed int64
+}
+
+// TrajectoryPoint is one step of a trajectory
+type TrajectoryPoint struct {
+	X, Y int
+	// At is the time since the start of the movement the point is reached
+	At time.Duration
+}
+
+// trajectoryStep is the interval between cursor updates
+const trajectoryStep = 10 * time.Millisecond
+
+// point is a point with sub-pixel precision used while building a path
+type point struct {
+	x, y float64
+}
+
+// GenerateTrajectory computes the timed steps of a movement between two points
+// without moving the mouse. The first point is the start at time zero and the
+// last point is always exactly the target.
+func GenerateTrajectory(fromX, fromY, toX, toY int, opts TrajectoryOptions) []TrajectoryPoint {
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	from := point{float64(fromX), float64(fromY)}
+	to := point{float64(toX), float64(toY)}
+	total := time.Duration(opts.Duration * float64(time.Second))
+
+	dist := math.Hypot(to.x-from.x, to.y-from.y)
+	if dist < 1 || total <= 0 {
+		return []TrajectoryPoint{{X: toX, Y: toY, At: total}}
+	}
+
+	// With overshoot the main movement ends slightly past the target and a short
+	// correction brings the cursor back, taking the last part of the duration
+	if opts.Overshoot && dist > 50 {
+		past := overshootPoint(from, to, dist, rng)
+		mainTime := time.Duration(float64(total) * 0.85)
+
+		steps := timedPath(buildPath(opts.Path, from, past, rng), opts.Easing, mainTime, 0, opts.Jitter, rng)
+		correction := timedPath(buildPath(PathLinear, past, to, rng), EaseOut, total-mainTime, mainTime, opts.Jitter/2, rng)
+		return finishTrajectory(fromX, fromY, append(steps, correction...), toX, toY, total)
+	}
+
+	steps := timedPath(buildPath(opts.Path, from, to, rng), opts.Easing, total, 0, opts.Jitter, rng)
+	return finishTrajectory(fromX, fromY, steps, toX, toY, total)
+}
+
+// overshootPoint returns a point a few percent past the target along the direction of movement
+func overshootPoint(from, to point, dist float64, rng *rand.Rand) point {
+	dx, dy := (to.x-from.x)/dist, (to.y-from.y)/dist
+	past := math.Min(dist*(0.03+rng.Float64()*0.05), 40)
+	side := (rng.Float64() - 0.5) * past
+	return point{to.x + dx*past - dy*side, to.y + dy*past + dx*side}
+}
+
+// buildPath returns a dense polyline of the given shape between two points
+func buildPath(kind PathKind, from, to point, rng *rand.Rand) []point {
+	switch kind {
+	case PathBezier:
+		return bezierPath(from, to, rng)
+	case PathWind:
+		return windPath(from, to, rng)
+	}
+	return []point{from, to}
+}
+
+// bezierPath samples a cubic Bezier curve whose control points are placed at
+// random distances on either side of the straight line
+func bezierPath(from, to point, rng *rand.Rand) []point {
+	dx, dy := to.x-from.x, to.y-from.y
+	dist := math.Hypot(dx, dy)
+	// Unit normal of the straight line
+	nx, ny := -dy/dist, dx/dist
+	spread := math.Min(dist*0.3, 200)
+
+	control := func(t float64) point {
+		offset := (rng.Float64()*2 - 1) * spread
+		along := t + (rng.Float64()-0.5)*0.2
+		return point{from.x + dx*along + nx*offset, from.y + dy*along + ny*offset}
+	}
+	c1, c2 := control(1.0/3), control(2.0/3)
+
+	const samples = 100
+	path := make([]point, 0, samples+1)
+	for i := 0; i <= samples; i++ {
+		t := float64(i) / samples
+		u := 1 - t
+		a, b, c, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+		path = append(path, point{
+			a*from.x + b*c1.x + c*c2.x + d*to.x,
+			a*from.y + b*c1.y + c*c2.y + d*to.y,
+		})
+	}
+	return path
+}
+
+// windPath simulates the WindMouse algorithm: the cursor is pulled towards the
+// target by gravity while a random wind force pushes it sideways, which fades
+// as the target gets closer
+func windPath(from, to point, rng *rand.Rand) []point {
+	const (
+		gravity   = 9.0
+		wind      = 3.0
+		maxStep   = 15.0
+		targetRad = 12.0
+	)
+
+	path := []point{from}
+	pos := from
+	var vx, vy, wx, wy float64
+	step := maxStep
+
+	for i := 0; i < 10000; i++ {
+		dist := math.Hypot(to.x-pos.x, to.y-pos.y)
+		if dist < 1 {
+			break
+		}
+
+		w := math.Min(wind, dist)
+		if dist >= targetRad {
+			wx = wx/math.Sqrt(3) + (rng.Float64()*2-1)*w/math.Sqrt(5)
+			wy = wy/math.Sqrt(3) + (rng.Float64()*2-1)*w/math.Sqrt(5)
+		} else {
+			wx /= math.Sqrt(3)
+			wy /= math.Sqrt(3)
+			if step < 3 {
+				step = rng.Float64()*3 + 3
+			} else {
+				step /= math.Sqrt(5)
+			}
+		}
+
+		vx += wx + gravity*(to.x-pos.x)/dist
+		vy += wy + gravity*(to.y-pos.y)/dist
+		if v := math.Hypot(vx, vy); v > step {
+			clip := step/2 + rng.Float64()*step/2
+			vx, vy = vx/v*clip, vy/v*clip
+		}
+
+		pos = point{pos.x + vx, pos.y + vy}
+		path = append(path, pos)
+	}
+
+	return append(path, to)
+}
+
+// timedPath resamples a polyline into steps at a fixed interval, distributing
+// them along the path according to the easing and adding jitter
+func timedPath(path []point, easing Easing, duration, offset time.Duration, jitter float64, rng *rand.Rand) []TrajectoryPoint {
+	// Cumulative arc length at each vertex
+	lengths := make([]float64, len(path))
+	for i := 1; i < len(path); i++ {
+		lengths[i] = lengths[i-1] + math.Hypot(path[i].x-path[i-1].x, path[i].y-path[i-1].y)
+	}
+	total := lengths[len(lengths)-1]
+
+	n := max(int(duration/trajectoryStep), 1)
+	steps := make([]TrajectoryPoint, 0, n)
+	seg := 1
+	for i := 1; i <= n; i++ {
+		t := float64(i) / float64(n)
+		target := easing.apply(t) * total
+
+		for seg < len(path)-1 && lengths[seg] < target {
+			seg++
+		}
+		p := path[len(path)-1]
+		if span := lengths[seg] - lengths[seg-1]; span > 0 {
+			f := (target - lengths[seg-1]) / span
+			p = point{
+				path[seg-1].x + (path[seg].x-path[seg-1].x)*f,
+				path[seg-1].y + (path[seg].y-path[seg-1].y)*f,
+			}
+		}
+
+		if jitter > 0 && i < n {
+			p.x += rng.NormFloat64() * jitter
+			p.y += rng.NormFloat64() * jitter
+		}
+
+		steps = append(steps, TrajectoryPoint{
+			X:  round(p.x),
+			Y:  round(p.y),
+			At: offset + time.Duration(float64(duration)*t),
+		})
+	}
+	return steps
+}
+
+// finishTrajectory starts the steps at the start point and pins the last
+// step exactly onto the target
+func finishTrajectory(fromX, fromY int, steps []TrajectoryPoint, toX, toY int, total time.Duration) []TrajectoryPoint {
+	if len(steps) == 0 {
+		return []TrajectoryPoint{{X: fromX, Y: fromY}, {X: toX, Y: toY, At: total}}
+	}
+	steps[len(steps)-1] = TrajectoryPoint{X: toX, Y: toY, At: total}
+	return append([]TrajectoryPoint{{X: fromX, Y: fromY}}, steps...)
+}
+
+// MoveAlongPath moves the mouse cursor from its current position to the specified
+// coordinates following a generated trajectory, checking ctx between steps
+func MoveAlongPath(ctx context.Context, x, y int, opts TrajectoryOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Validate coordinates are non-negative
+	if x < 0 {
+		return fmt.Errorf("x coordinate cannot be negative: %d", x)
+	}
+	if y < 0 {
+		return fmt.Errorf("y coordinate cannot be negative: %d", y)
+	}
+
+	// Validate duration is positive
+	if opts.Duration <= 0 {
+		return fmt.Errorf("duration must be positive: %f", opts.Duration)
+	}
+
+	// Get screen dimensions for validation
+	screenWidth, screenHeight := GetScreenSize()
+	if x > screenWidth {
+		return fmt.Errorf("x coordinate %d exceeds screen width %d", x, screenWidth)
+	}
+	if y > screenHeight {
+		return fmt.Errorf("y coordinate %d exceeds screen height %d", y, screenHeight)
+	}
+
+	b := CurrentBackend()
+	fromX, fromY, err := b.MousePosition(ctx)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	for _, p := range GenerateTrajectory(fromX, fromY, x, y, opts) {
+		if err := sleep(ctx, p.At-time.Since(start)); err != nil {
+			return err
+		}
+		// Jitter and overshoot may leave the screen near its edges
+		if err := b.MoveMouse(ctx, clamp(p.X, 0, screenWidth), clamp(p.Y, 0, screenHeight)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// clamp limits v to the range [lo, hi]
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
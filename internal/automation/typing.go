@@ -0,0 +1,252 @@
+package automation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TypingProfile models the cadence of a human typist
+type TypingProfile struct {
+	// Name identifies the profile on the command line
+	Name string
+	// WPM is the target typing speed in words (of five characters) per minute
+	WPM float64
+	// Jitter is the standard deviation of each delay as a fraction of the mean delay
+	Jitter float64
+	// KeyPairs enables per-key-pair timing based on hand and finger travel on a QWERTY layout
+	KeyPairs bool
+	// WordPause is the extra pause after a space, as a multiple of the mean delay
+	WordPause float64
+	// PunctuationPause is the extra pause after punctuation, as a multiple of the mean delay
+	PunctuationPause float64
+	// BurstLength is the average number of characters typed in one fast burst; 0 disables bursts
+	BurstLength int
+	// BurstSpeedup is the delay multiplier inside a burst
+	BurstSpeedup float64
+	// Seed makes the delays reproducible; 0 picks a random seed
+	Seed int64
+}
+
+// typingProfiles are the built-in profiles
+var typingProfiles = map[string]TypingProfile{
+	"uniform": {
+		Name: "uniform",
+		WPM:  60,
+	},
+	"natural": {
+		Name:             "natural",
+		WPM:              60,
+		Jitter:           0.25,
+		KeyPairs:         true,
+		WordPause:        0.8,
+		PunctuationPause: 2.5,
+		BurstLength:      6,
+		BurstSpeedup:     0.7,
+	},
+	"fast": {
+		Name:             "fast",
+		WPM:              100,
+		Jitter:           0.15,
+		KeyPairs:         true,
+		WordPause:        0.3,
+		PunctuationPause: 1.2,
+		BurstLength:      10,
+		BurstSpeedup:     0.6,
+	},
+	"hesitant": {
+		Name:             "hesitant",
+		WPM:              30,
+		Jitter:           0.4,
+		KeyPairs:         true,
+		WordPause:        2.0,
+		PunctuationPause: 4.0,
+		BurstLength:      3,
+		BurstSpeedup:     0.8,
+	},
+}
+
+// TypingProfileByName returns a built-in typing profile
+func TypingProfileByName(name string) (TypingProfile, error) {
+	p, ok := typingProfiles[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return TypingProfile{}, fmt.Errorf("unknown typing profile %q: must be one of %s",
+			name, strings.Join(TypingProfiles(), ", "))
+	}
+	return p, nil
+}
+
+// TypingProfiles returns the names of the built-in typing profiles, sorted
+func TypingProfiles() []string {
+	names := make([]string, 0, len(typingProfiles))
+	for name := range typingProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// keyPos is the position of a key on a QWERTY keyboard
+type keyPos struct {
+	row, col int
+}
+
+// qwertyRows lists the unshifted and shifted characters of each keyboard row,
+// aligned by column
+var qwertyRows = [][2]string{
+	{"`1234567890-=", "~!@#$%^&*()_+"},
+	{"qwertyuiop[]\\", "QWERTYUIOP{}|"},
+	{"asdfghjkl;'", "ASDFGHJKL:\""},
+	{"zxcvbnm,./", "ZXCVBNM<>?"},
+}
+
+// qwerty maps characters to their key position
+var qwerty = func() map[rune]keyPos {
+	m := map[rune]keyPos{' ': {row: 4, col: 5}}
+	for row, chars := range qwertyRows {
+		for _, set := range chars {
+			for col, r := range []rune(set) {
+				// The number row starts one key further left than the letter rows
+				if row == 0 {
+					col--
+				}
+				m[r] = keyPos{row: row, col: col}
+			}
+		}
+	}
+	return m
+}()
+
+// finger returns the finger (0-7, left pinky to right pinky) used for a key in touch typing
+func (k keyPos) finger() int {
+	switch {
+	case k.col <= 2:
+		return max(k.col, 0)
+	case k.col <= 4:
+		return 3
+	case k.col <= 6:
+		return 4
+	}
+	return min(k.col-2, 7)
+}
+
+// leftHand reports whether a key is typed with the left hand
+func (k keyPos) leftHand() bool {
+	return k.col <= 4
+}
+
+// pairFactor returns the delay multiplier for typing cur after prev, based on
+// which hands and fingers are involved and how far they travel
+func pairFactor(prev, cur rune) float64 {
+	p, okP := qwerty[prev]
+	c, okC := qwerty[cur]
+	if !okP || !okC || prev == ' ' || cur == ' ' {
+		return 1.0
+	}
+
+	switch {
+	case prev == cur:
+		// Repeating a key is slightly slower than an average stroke
+		return 1.1
+	case p.leftHand() != c.leftHand():
+		// Alternating hands lets the next finger prepare during the stroke
+		return 0.8
+	case p.finger() == c.finger():
+		// The same finger has to travel between keys
+		return 1.3 + 0.1*math.Abs(float64(p.row-c.row))
+	}
+	return 1.0 + 0.05*math.Hypot(float64(p.row-c.row), float64(p.col-c.col))
+}
+
+// Delays returns the pause before each character of text under the profile
+func (p TypingProfile) Delays(text string) []time.Duration {
+	seed := p.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	wpm := p.WPM
+	if wpm <= 0 {
+		wpm = 60
+	}
+	// One word is five characters
+	mean := float64(time.Minute) / (wpm * 5)
+
+	runes := []rune(text)
+	delays := make([]time.Duration, len(runes))
+	burstLeft := 0
+	for i, r := range runes {
+		if i == 0 {
+			continue
+		}
+		prev := runes[i-1]
+
+		d := mean
+		if p.KeyPairs {
+			d *= pairFactor(unicode.ToLower(prev), unicode.ToLower(r))
+		}
+
+		// Typists speed up inside familiar bursts and briefly pause between them
+		if p.BurstLength > 0 {
+			if burstLeft == 0 {
+				burstLeft = max(1, int(float64(p.BurstLength)*(0.5+rng.Float64())))
+				d *= 1.5
+			} else {
+				d *= p.BurstSpeedup
+				burstLeft--
+			}
+		}
+
+		switch {
+		case prev == ' ' || prev == '\n' || prev == '\t':
+			d += mean * p.WordPause
+		case unicode.IsPunct(prev):
+			d += mean * p.PunctuationPause
+		}
+
+		if p.Jitter > 0 {
+			d *= 1 + rng.NormFloat64()*p.Jitter
+		}
+		// Never type faster than a quarter of the mean delay
+		delays[i] = time.Duration(math.Max(d, mean/4))
+	}
+
+	// Rescale so the pauses and bursts average out to the target speed
+	var total time.Duration
+	for _, d := range delays {
+		total += d
+	}
+	if total > 0 {
+		scale := mean * float64(len(delays)-1) / float64(total)
+		for i := range delays {
+			delays[i] = time.Duration(float64(delays[i]) * scale)
+		}
+	}
+	return delays
+}
+
+// TypeWithProfile types text with the cadence of a typing profile, checking ctx between keystrokes
+func TypeWithProfile(ctx context.Context, text string, profile TypingProfile) error {
+	// Safety check for empty strings
+	if text == "" {
+		return nil
+	}
+
+	b := CurrentBackend()
+	delays := profile.Delays(text)
+	for i, char := range []rune(text) {
+		if err := sleep(ctx, delays[i]); err != nil {
+			return err
+		}
+		if err := b.TypeString(ctx, string(char)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
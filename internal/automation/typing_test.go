@@ -0,0 +1,128 @@
+package automation
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+const typingText = "The quick brown fox jumps over the lazy dog. Grüße, naïve café! 123"
+
+func TestTypingProfileDelays(t *testing.T) {
+	for _, name := range TypingProfiles() {
+		t.Run(name, func(t *testing.T) {
+			p, err := TypingProfileByName(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.Seed = 42
+			delays := p.Delays(typingText)
+
+			if n := utf8.RuneCountInString(typingText); len(delays) != n {
+				t.Fatalf("got %d delays, want one per rune (%d)", len(delays), n)
+			}
+			if again := p.Delays(typingText); !reflect.DeepEqual(delays, again) {
+				t.Error("the same seed gave different delays")
+			}
+			if delays[0] != 0 {
+				t.Errorf("first delay = %v, want 0", delays[0])
+			}
+
+			var total time.Duration
+			for i, d := range delays[1:] {
+				if d <= 0 {
+					t.Errorf("delay %d = %v, want a positive delay", i+1, d)
+				}
+				total += d
+			}
+			mean := time.Duration(float64(time.Minute) / (p.WPM * 5))
+			got := total / time.Duration(len(delays)-1)
+			if math.Abs(float64(got-mean)) > float64(time.Microsecond) {
+				t.Errorf("mean delay = %v, want %v for %g WPM", got, mean, p.WPM)
+			}
+		})
+	}
+}
+
+func TestTypingProfileDelaysVary(t *testing.T) {
+	p, _ := TypingProfileByName("natural")
+	p.Seed = 1
+	a := p.Delays(typingText)
+	p.Seed = 2
+	if b := p.Delays(typingText); reflect.DeepEqual(a, b) {
+		t.Error("different seeds gave the same delays")
+	}
+
+	uniform, _ := TypingProfileByName("uniform")
+	uniform.Seed = 1
+	delays := uniform.Delays(typingText)
+	for i, d := range delays[1:] {
+		if d != delays[1] {
+			t.Fatalf("uniform delay %d = %v, want %v like the others", i+1, d, delays[1])
+		}
+	}
+}
+
+func TestTypingProfileDelaysShortText(t *testing.T) {
+	p, _ := TypingProfileByName("natural")
+	p.Seed = 1
+	tests := []struct {
+		text string
+		want []time.Duration
+	}{
+		{"", []time.Duration{}},
+		{"a", []time.Duration{0}},
+	}
+	for _, tt := range tests {
+		if got := p.Delays(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Delays(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTypingProfileByName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"natural", "natural", false},
+		{" Fast ", "fast", false},
+		{"uniform", "uniform", false},
+		{"hesitant", "hesitant", false},
+		{"robot", "", true},
+	}
+	for _, tt := range tests {
+		p, err := TypingProfileByName(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("TypingProfileByName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if p.Name != tt.want {
+			t.Errorf("TypingProfileByName(%q) = %q, want %q", tt.name, p.Name, tt.want)
+		}
+	}
+}
+
+func TestPairFactor(t *testing.T) {
+	tests := []struct {
+		name      string
+		prev, cur rune
+		want      float64
+	}{
+		{"repeated key", 'e', 'e', 1.1},
+		{"alternating hands", 'f', 'j', 0.8},
+		{"same finger", 'f', 'r', 1.4},
+		{"same finger two rows", 'r', 'v', 1.5},
+		{"space", ' ', 'a', 1.0},
+		{"unknown key", 'ü', 'a', 1.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pairFactor(tt.prev, tt.cur); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("pairFactor(%q, %q) = %g, want %g", tt.prev, tt.cur, got, tt.want)
+			}
+		})
+	}
+}
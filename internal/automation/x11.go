@@ -0,0 +1,663 @@
+package automation
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"math/bits"
+	"sync"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/randr"
+	"github.com/jezek/xgb/shm"
+	"github.com/jezek/xgb/xproto"
+	"github.com/jezek/xgb/xtest"
+)
+
+// XTEST fake input event types
+const (
+	x11KeyPress      = 2
+	x11KeyRelease    = 3
+	x11ButtonPress   = 4
+	x11ButtonRelease = 5
+	x11MotionNotify  = 6
+)
+
+// Core pointer buttons
+var x11Buttons = map[string]byte{"left": 1, "middle": 2, "right": 3}
+
+const (
+	x11WheelUp    = 4
+	x11WheelDown  = 5
+	x11WheelLeft  = 6
+	x11WheelRight = 7
+)
+
+// x11Key is a key code and whether shift is needed to produce a keysym with it
+type x11Key struct {
+	code  xproto.Keycode
+	shift bool
+}
+
+// x11Format describes how the pixels of the root window are stored in ZPixmap
+// images: rows are padded to scanlinePad bits and pixels take bpp bits each
+type x11Format struct {
+	depth       int
+	bpp         int
+	scanlinePad int
+	msbFirst    bool
+
+	// trueColor pixels hold their color in the bits of the channel masks,
+	// pixels of other visuals index the colormap
+	trueColor                    bool
+	redMask, greenMask, blueMask uint32
+	colormapEntries              int
+}
+
+// X11Backend drives an X11 display directly over the X protocol without cgo.
+// Input is injected with the XTEST extension, screenshots use MIT-SHM when the
+// server is local and GetImage otherwise, and monitors are read with RandR.
+//
+// Characters missing from the keyboard layout are typed by temporarily
+// assigning them to an unused key code, so any text can be typed.
+type X11Backend struct {
+	conn     *xgb.Conn
+	root     xproto.Window
+	colormap xproto.Colormap
+	format   x11Format
+	hasRandR bool
+
+	// mu guards the keyboard mapping and the shared memory segment
+	mu       sync.Mutex
+	keys     map[uint32]x11Key
+	perCode  int
+	spare    xproto.Keycode
+	spareSym uint32
+	shift    xproto.Keycode
+
+	shmOK   bool
+	shmSeg  shm.Seg
+	shmData []byte
+
+	// clickMu guards the observed clicks and orders presses made through the
+	// backend with polls of the button state
+	clickMu  sync.Mutex
+	watching bool
+	pressed  uint16
+	clicks   []ClickEvent
+	clickSeq uint64
+	// done is closed when the backend is closed
+	done chan struct{}
+}
+
+var _ Backend = (*X11Backend)(nil)
+
+// DialX11 connects to the X11 display, such as ":0", or $DISPLAY if display is
+// empty. The server must support the XTEST extension.
+func DialX11(ctx context.Context, display string) (*X11Backend, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	conn, err := xgb.NewConnDisplay(display)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to X11 display %q: %w", display, err)
+	}
+	if err := xtest.Init(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("X11 display %q does not support XTEST: %w", display, err)
+	}
+
+	// Events and errors are not used, but must be consumed so the connection
+	// does not stall
+	go func() {
+		for {
+			ev, err := conn.WaitForEvent()
+			if ev == nil && err == nil {
+				return
+			}
+		}
+	}()
+
+	setup := xproto.Setup(conn)
+	screen := setup.DefaultScreen(conn)
+	x := &X11Backend{
+		conn:     conn,
+		root:     screen.Root,
+		colormap: screen.DefaultColormap,
+		format:   rootFormat(setup, screen),
+		hasRandR: randr.Init(conn) == nil,
+		shmOK:    shm.Init(conn) == nil,
+		done:     make(chan struct{}),
+	}
+
+	if err := x.loadKeyboardMapping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read X11 keyboard mapping: %w", err)
+	}
+	return x, nil
+}
+
+// rootFormat returns the pixel format of the root window of screen
+func rootFormat(setup *xproto.SetupInfo, screen *xproto.ScreenInfo) x11Format {
+	f := x11Format{
+		depth:    int(screen.RootDepth),
+		msbFirst: setup.ImageByteOrder != xproto.ImageOrderLSBFirst,
+	}
+	for _, format := range setup.PixmapFormats {
+		if format.Depth == screen.RootDepth {
+			f.bpp, f.scanlinePad = int(format.BitsPerPixel), int(format.ScanlinePad)
+		}
+	}
+	for _, depth := range screen.AllowedDepths {
+		for _, visual := range depth.Visuals {
+			if visual.VisualId != screen.RootVisual {
+				continue
+			}
+			f.trueColor = visual.Class == xproto.VisualClassTrueColor || visual.Class == xproto.VisualClassDirectColor
+			f.redMask, f.greenMask, f.blueMask = visual.RedMask, visual.GreenMask, visual.BlueMask
+			f.colormapEntries = int(visual.ColormapEntries)
+		}
+	}
+	return f
+}
+
+// Close restores the keyboard mapping and closes the connection to the display
+func (x *X11Backend) Close() error {
+	x.clickMu.Lock()
+	close(x.done)
+	x.clickMu.Unlock()
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.spareSym != 0 {
+		x.remapSpare(0)
+	}
+	x.releaseShm()
+	x.conn.Close()
+	return nil
+}
+
+// loadKeyboardMapping reads which key codes produce which keysyms
+func (x *X11Backend) loadKeyboardMapping() error {
+	setup := xproto.Setup(x.conn)
+	count := int(setup.MaxKeycode) - int(setup.MinKeycode) + 1
+	reply, err := xproto.GetKeyboardMapping(x.conn, setup.MinKeycode, byte(count)).Reply()
+	if err != nil {
+		return err
+	}
+
+	x.keys = make(map[uint32]x11Key)
+	x.perCode = int(reply.KeysymsPerKeycode)
+	for i := 0; i < count; i++ {
+		code := xproto.Keycode(int(setup.MinKeycode) + i)
+		syms := reply.Keysyms[i*x.perCode : (i+1)*x.perCode]
+
+		unused := true
+		for level, sym := range syms {
+			if sym == 0 {
+				continue
+			}
+			unused = false
+			// Only the first two levels are reachable without modifiers other than shift
+			if _, ok := x.keys[uint32(sym)]; !ok && level < 2 {
+				x.keys[uint32(sym)] = x11Key{code: code, shift: level == 1}
+			}
+		}
+		if unused && x.spare == 0 {
+			x.spare = code
+		}
+	}
+
+	x.shift = x.keys[namedKeysyms["shift"]].code
+	return nil
+}
+
+// remapSpare assigns sym to the spare key code, or clears it if sym is 0
+func (x *X11Backend) remapSpare(sym uint32) error {
+	// The keysym is set for both the plain and the shifted level
+	syms := make([]xproto.Keysym, x.perCode)
+	for i := 0; i < len(syms) && i < 2; i++ {
+		syms[i] = xproto.Keysym(sym)
+	}
+	if err := xproto.ChangeKeyboardMappingChecked(x.conn, 1, x.spare, byte(x.perCode), syms).Check(); err != nil {
+		return fmt.Errorf("failed to remap key code %d: %w", x.spare, err)
+	}
+	x.spareSym = sym
+	return nil
+}
+
+// keyFor returns the key producing sym, remapping the spare key code if the
+// keyboard layout has no key for it
+func (x *X11Backend) keyFor(sym uint32) (x11Key, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if k, ok := x.keys[sym]; ok {
+		return k, nil
+	}
+	if x.spare == 0 {
+		return x11Key{}, fmt.Errorf("keysym 0x%x is not on the keyboard and no key code is free to map it", sym)
+	}
+	if x.spareSym != sym {
+		if err := x.remapSpare(sym); err != nil {
+			return x11Key{}, err
+		}
+	}
+	return x11Key{code: x.spare}, nil
+}
+
+// fakeInput injects an input event with XTEST
+func (x *X11Backend) fakeInput(eventType, detail byte, rootX, rootY int) error {
+	err := xtest.FakeInputChecked(x.conn, eventType, detail, 0, x.root, int16(rootX), int16(rootY), 0).Check()
+	if err != nil {
+		return fmt.Errorf("failed to send X11 input: %w", err)
+	}
+	return nil
+}
+
+// key presses or releases a key code
+func (x *X11Backend) key(code xproto.Keycode, down bool) error {
+	if down {
+		return x.fakeInput(x11KeyPress, byte(code), 0, 0)
+	}
+	return x.fakeInput(x11KeyRelease, byte(code), 0, 0)
+}
+
+// button presses or releases a pointer button
+func (x *X11Backend) button(button byte, down bool) error {
+	x.clickMu.Lock()
+	defer x.clickMu.Unlock()
+
+	eventType := byte(x11ButtonRelease)
+	if down {
+		eventType = x11ButtonPress
+	}
+	if err := x.fakeInput(eventType, button, 0, 0); err != nil {
+		return err
+	}
+	if x.watching {
+		// Record the press right away, it may be shorter than a poll
+		x.pollButtons()
+	}
+	return nil
+}
+
+// tapSym presses and releases the key producing sym, holding shift if needed
+func (x *X11Backend) tapSym(sym uint32) error {
+	k, err := x.keyFor(sym)
+	if err != nil {
+		return err
+	}
+
+	shift := k.shift && x.shift != 0
+	if shift {
+		if err := x.key(x.shift, true); err != nil {
+			return err
+		}
+	}
+	err = x.key(k.code, true)
+	if err == nil {
+		err = x.key(k.code, false)
+	}
+	if shift {
+		if serr := x.key(x.shift, false); err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+func (x *X11Backend) MoveMouse(ctx context.Context, px, py int) error {
+	// Detail 0 makes the motion absolute
+	return x.fakeInput(x11MotionNotify, 0, px, py)
+}
+
+func (x *X11Backend) MouseToggle(ctx context.Context, button string, down bool) error {
+	b, ok := x11Buttons[button]
+	if !ok {
+		return fmt.Errorf("unknown mouse button '%s'", button)
+	}
+	return x.button(b, down)
+}
+
+func (x *X11Backend) Click(ctx context.Context, button string, double bool) error {
+	clicks := 1
+	if double {
+		clicks = 2
+	}
+	for i := 0; i < clicks; i++ {
+		if err := x.MouseToggle(ctx, button, true); err != nil {
+			return err
+		}
+		err := sleep(ctx, x11ClickHold)
+		if uerr := x.MouseToggle(ctx, button, false); err == nil {
+			err = uerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *X11Backend) Scroll(ctx context.Context, dx, dy int) error {
+	// Every wheel step is a press and release of the corresponding button
+	step := func(button byte, n int) error {
+		for i := 0; i < n; i++ {
+			if err := x.button(button, true); err != nil {
+				return err
+			}
+			if err := x.button(button, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if dy > 0 {
+		if err := step(x11WheelDown, dy); err != nil {
+			return err
+		}
+	} else if err := step(x11WheelUp, -dy); err != nil {
+		return err
+	}
+	if dx > 0 {
+		return step(x11WheelRight, dx)
+	}
+	return step(x11WheelLeft, -dx)
+}
+
+func (x *X11Backend) KeyTap(ctx context.Context, key string, modifiers ...string) error {
+	keys := make([]x11Key, 0, len(modifiers)+1)
+	shiftHeld := false
+	for _, name := range append(modifiers, key) {
+		sym, err := keysymForName(name)
+		if err != nil {
+			return err
+		}
+		shiftHeld = shiftHeld || sym == namedKeysyms["lshift"] || sym == namedKeysyms["rshift"]
+		k, err := x.keyFor(sym)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+
+	codes := comboCodes(keys, x.shift, shiftHeld)
+	for _, code := range codes {
+		if err := x.key(code, true); err != nil {
+			return err
+		}
+	}
+	for i := len(codes) - 1; i >= 0; i-- {
+		if err := x.key(codes[i], false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// comboCodes returns the key codes to press in order for a key combination.
+// Keys that need shift, such as "A" or "?", get shift pressed first unless the
+// combination already holds a shift key.
+func comboCodes(keys []x11Key, shift xproto.Keycode, shiftHeld bool) []xproto.Keycode {
+	codes := make([]xproto.Keycode, 0, len(keys)+1)
+	needShift := false
+	for _, k := range keys {
+		needShift = needShift || k.shift
+		codes = append(codes, k.code)
+	}
+	if needShift && !shiftHeld && shift != 0 {
+		codes = append([]xproto.Keycode{shift}, codes...)
+	}
+	return codes
+}
+
+func (x *X11Backend) KeyToggle(ctx context.Context, key string, down bool) error {
+	sym, err := keysymForName(key)
+	if err != nil {
+		return err
+	}
+	k, err := x.keyFor(sym)
+	if err != nil {
+		return err
+	}
+	return x.key(k.code, down)
+}
+
+func (x *X11Backend) TypeString(ctx context.Context, text string) error {
+	for _, r := range text {
+		if err := x.tapSym(keysymForRune(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *X11Backend) MousePosition(ctx context.Context) (int, int, error) {
+	reply, err := xproto.QueryPointer(x.conn, x.root).Reply()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query X11 pointer: %w", err)
+	}
+	return int(reply.RootX), int(reply.RootY), nil
+}
+
+func (x *X11Backend) ScreenSize(ctx context.Context) (int, int, error) {
+	bounds, err := x.screenBounds()
+	if err != nil {
+		return 0, 0, err
+	}
+	return bounds.Dx(), bounds.Dy(), nil
+}
+
+func (x *X11Backend) ScaleFactor(ctx context.Context) (float64, error) {
+	return 1, nil
+}
+
+// screenBounds returns the current bounds of the root window, which change
+// when the screen is resized with RandR
+func (x *X11Backend) screenBounds() (image.Rectangle, error) {
+	geom, err := xproto.GetGeometry(x.conn, xproto.Drawable(x.root)).Reply()
+	if err != nil {
+		return image.Rectangle{}, fmt.Errorf("failed to get X11 screen geometry: %w", err)
+	}
+	return image.Rect(0, 0, int(geom.Width), int(geom.Height)), nil
+}
+
+// Monitors returns the bounds of the active monitors with the primary monitor
+// first, or the whole screen if the server does not support RandR 1.5
+func (x *X11Backend) Monitors(ctx context.Context) ([]image.Rectangle, error) {
+	if x.hasRandR {
+		reply, err := randr.GetMonitors(x.conn, x.root, true).Reply()
+		if err == nil && len(reply.Monitors) > 0 {
+			monitors := make([]image.Rectangle, 0, len(reply.Monitors))
+			for _, m := range reply.Monitors {
+				r := image.Rect(int(m.X), int(m.Y), int(m.X)+int(m.Width), int(m.Y)+int(m.Height))
+				if m.Primary {
+					monitors = append([]image.Rectangle{r}, monitors...)
+				} else {
+					monitors = append(monitors, r)
+				}
+			}
+			return monitors, nil
+		}
+	}
+
+	bounds, err := x.screenBounds()
+	if err != nil {
+		return nil, err
+	}
+	return []image.Rectangle{bounds}, nil
+}
+
+func (x *X11Backend) CaptureScreen(ctx context.Context, region image.Rectangle) (image.Image, error) {
+	bounds, err := x.screenBounds()
+	if err != nil {
+		return nil, err
+	}
+	if region.Empty() {
+		region = bounds
+	}
+	region = region.Intersect(bounds)
+	if region.Empty() {
+		return nil, fmt.Errorf("capture region is outside the screen")
+	}
+
+	f := x.format
+	if !f.supported() {
+		return nil, fmt.Errorf("unsupported X11 pixel format: depth %d with %d bits per pixel", f.depth, f.bpp)
+	}
+
+	var palette []color.RGBA
+	if !f.trueColor {
+		if palette, err = x.palette(); err != nil {
+			return nil, err
+		}
+	}
+
+	pixels, err := x.captureShm(region, f.stride(region.Dx())*region.Dy())
+	if err != nil {
+		reply, gerr := xproto.GetImage(x.conn, xproto.ImageFormatZPixmap, xproto.Drawable(x.root),
+			int16(region.Min.X), int16(region.Min.Y), uint16(region.Dx()), uint16(region.Dy()), 0xffffffff).Reply()
+		if gerr != nil {
+			return nil, fmt.Errorf("failed to capture X11 screen: %w", gerr)
+		}
+		pixels = reply.Data
+	}
+	return decodeX11Image(pixels, region.Dx(), region.Dy(), f, palette)
+}
+
+// palette returns the colors of the default colormap, which pixels of visuals
+// without true color index
+func (x *X11Backend) palette() ([]color.RGBA, error) {
+	n := x.format.colormapEntries
+	if n <= 0 || n > 1<<x.format.depth {
+		n = 1 << x.format.depth
+	}
+	pixels := make([]uint32, n)
+	for i := range pixels {
+		pixels[i] = uint32(i)
+	}
+
+	reply, err := xproto.QueryColors(x.conn, x.colormap, pixels).Reply()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read X11 colormap: %w", err)
+	}
+	palette := make([]color.RGBA, len(reply.Colors))
+	for i, c := range reply.Colors {
+		palette[i] = color.RGBA{uint8(c.Red >> 8), uint8(c.Green >> 8), uint8(c.Blue >> 8), 0xff}
+	}
+	return palette, nil
+}
+
+// supported reports whether pixels of the format can be decoded
+func (f x11Format) supported() bool {
+	switch f.bpp {
+	case 8, 16, 24, 32:
+		return f.trueColor || f.depth <= 8
+	}
+	return false
+}
+
+// stride returns the number of bytes in a row of width pixels
+func (f x11Format) stride(width int) int {
+	pad := max(f.scanlinePad, 8)
+	return (width*f.bpp + pad - 1) / pad * pad / 8
+}
+
+// pixel reads the pixel value stored at the start of b
+func (f x11Format) pixel(b []byte) uint32 {
+	switch f.bpp {
+	case 8:
+		return uint32(b[0])
+	case 16:
+		if f.msbFirst {
+			return uint32(binary.BigEndian.Uint16(b))
+		}
+		return uint32(binary.LittleEndian.Uint16(b))
+	case 24:
+		if f.msbFirst {
+			return uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+		}
+		return uint32(b[2])<<16 | uint32(b[1])<<8 | uint32(b[0])
+	}
+	if f.msbFirst {
+		return binary.BigEndian.Uint32(b)
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+// decodeX11Image converts ZPixmap image data of the given format to RGBA.
+// Pixels of visuals without true color are looked up in palette.
+func decodeX11Image(data []byte, width, height int, f x11Format, palette []color.RGBA) (*image.RGBA, error) {
+	if !f.supported() {
+		return nil, fmt.Errorf("unsupported X11 pixel format: depth %d with %d bits per pixel", f.depth, f.bpp)
+	}
+	stride := f.stride(width)
+	if len(data) < stride*height {
+		return nil, fmt.Errorf("failed to capture X11 screen: short image data")
+	}
+
+	bytesPerPixel := f.bpp / 8
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		row := data[y*stride:]
+		for px := 0; px < width; px++ {
+			p := f.pixel(row[px*bytesPerPixel:])
+
+			var c color.RGBA
+			switch {
+			case f.trueColor:
+				c = color.RGBA{maskChannel(p, f.redMask), maskChannel(p, f.greenMask), maskChannel(p, f.blueMask), 0xff}
+			case int(p) < len(palette):
+				c = palette[p]
+			default:
+				c = color.RGBA{A: 0xff}
+			}
+			img.SetRGBA(px, y, c)
+		}
+	}
+	return img, nil
+}
+
+// maskChannel extracts the bits of a color channel from a pixel and scales
+// them to 8 bits
+func maskChannel(p, mask uint32) uint8 {
+	if mask == 0 {
+		return 0
+	}
+	shift := bits.TrailingZeros32(mask)
+	v, limit := uint64(p&mask)>>shift, uint64(mask)>>shift
+	return uint8(v * 255 / limit)
+}
+
+func (x *X11Backend) ActiveWindowBounds(ctx context.Context) (image.Rectangle, error) {
+	const name = "_NET_ACTIVE_WINDOW"
+	atom, err := xproto.InternAtom(x.conn, true, uint16(len(name)), name).Reply()
+	if err != nil || atom.Atom == 0 {
+		return image.Rectangle{}, fmt.Errorf("failed to find the active window: the window manager does not set %s", name)
+	}
+
+	prop, err := xproto.GetProperty(x.conn, false, x.root, atom.Atom, xproto.AtomWindow, 0, 1).Reply()
+	if err != nil || len(prop.Value) < 4 {
+		return image.Rectangle{}, fmt.Errorf("failed to find the active window")
+	}
+	window := xproto.Window(xgb.Get32(prop.Value))
+	if window == 0 {
+		return image.Rectangle{}, fmt.Errorf("failed to find the active window")
+	}
+
+	geom, err := xproto.GetGeometry(x.conn, xproto.Drawable(window)).Reply()
+	if err != nil {
+		return image.Rectangle{}, fmt.Errorf("failed to get bounds of the active window: %w", err)
+	}
+	pos, err := xproto.TranslateCoordinates(x.conn, window, x.root, 0, 0).Reply()
+	if err != nil {
+		return image.Rectangle{}, fmt.Errorf("failed to get bounds of the active window: %w", err)
+	}
+	return image.Rect(int(pos.DstX), int(pos.DstY), int(pos.DstX)+int(geom.Width), int(pos.DstY)+int(geom.Height)), nil
+}
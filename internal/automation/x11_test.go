@@ -0,0 +1,222 @@
+package automation
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jezek/xgb/xproto"
+)
+
+func TestComboCodes(t *testing.T) {
+	const shift, ctrl, a, question = 50, 37, 38, 61
+	tests := []struct {
+		name      string
+		keys      []x11Key
+		shift     xproto.Keycode
+		shiftHeld bool
+		want      []xproto.Keycode
+	}{
+		{"plain key", []x11Key{{code: a}}, shift, false, []xproto.Keycode{a}},
+		{"shifted key", []x11Key{{code: a, shift: true}}, shift, false, []xproto.Keycode{shift, a}},
+		{"shifted symbol", []x11Key{{code: question, shift: true}}, shift, false, []xproto.Keycode{shift, question}},
+		{"modifier and shifted key", []x11Key{{code: ctrl}, {code: a, shift: true}}, shift, false, []xproto.Keycode{shift, ctrl, a}},
+		{"shift already held", []x11Key{{code: shift}, {code: a, shift: true}}, shift, true, []xproto.Keycode{shift, a}},
+		{"no shift key", []x11Key{{code: a, shift: true}}, 0, false, []xproto.Keycode{a}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := comboCodes(tt.keys, tt.shift, tt.shiftHeld)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("comboCodes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeX11Image(t *testing.T) {
+	rgb888 := x11Format{depth: 24, bpp: 32, scanlinePad: 32, trueColor: true, redMask: 0xff0000, greenMask: 0xff00, blueMask: 0xff}
+	rgb888MSB := rgb888
+	rgb888MSB.msbFirst = true
+	packed := rgb888
+	packed.bpp = 24
+	rgb565 := x11Format{depth: 16, bpp: 16, scanlinePad: 32, trueColor: true, redMask: 0xf800, greenMask: 0x7e0, blueMask: 0x1f}
+	rgb565MSB := rgb565
+	rgb565MSB.msbFirst = true
+	indexed := x11Format{depth: 8, bpp: 8, scanlinePad: 32}
+
+	red := color.RGBA{255, 0, 0, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	black := color.RGBA{A: 255}
+	palette := []color.RGBA{black, red, green}
+
+	tests := []struct {
+		name    string
+		format  x11Format
+		width   int
+		height  int
+		data    []byte
+		want    []color.RGBA
+		wantErr bool
+	}{
+		{
+			name:   "32 bits LSB first",
+			format: rgb888, width: 3, height: 1,
+			data: []byte{0, 0, 255, 0, 0, 255, 0, 0, 255, 0, 0, 0},
+			want: []color.RGBA{red, green, blue},
+		},
+		{
+			name:   "32 bits MSB first",
+			format: rgb888MSB, width: 3, height: 1,
+			data: []byte{0, 255, 0, 0, 0, 0, 255, 0, 0, 0, 0, 255},
+			want: []color.RGBA{red, green, blue},
+		},
+		{
+			name:   "24 bits packed with padded rows",
+			format: packed, width: 3, height: 2,
+			data: []byte{
+				0, 0, 255, 0, 255, 0, 255, 0, 0, 9, 9, 9,
+				255, 0, 0, 0, 0, 255, 0, 0, 0, 9, 9, 9,
+			},
+			want: []color.RGBA{red, green, blue, blue, red, black},
+		},
+		{
+			name:   "16 bits LSB first",
+			format: rgb565, width: 3, height: 1,
+			data: []byte{0x00, 0xf8, 0xe0, 0x07, 0x1f, 0x00, 0, 0},
+			want: []color.RGBA{red, green, blue},
+		},
+		{
+			name:   "16 bits MSB first",
+			format: rgb565MSB, width: 2, height: 1,
+			data: []byte{0xf8, 0x00, 0x84, 0x10},
+			want: []color.RGBA{red, {131, 129, 131, 255}},
+		},
+		{
+			name:   "8 bits indexed",
+			format: indexed, width: 3, height: 2,
+			data: []byte{1, 2, 0, 0, 2, 200, 1, 0},
+			want: []color.RGBA{red, green, black, green, black, red},
+		},
+		{
+			name:   "short data",
+			format: rgb888, width: 2, height: 2,
+			data:    make([]byte, 12),
+			wantErr: true,
+		},
+		{
+			name:   "unsupported bits per pixel",
+			format: x11Format{depth: 4, bpp: 4, scanlinePad: 32}, width: 1, height: 1,
+			data:    make([]byte, 4),
+			wantErr: true,
+		},
+		{
+			name:   "indexed deeper than 8 bits",
+			format: x11Format{depth: 16, bpp: 16, scanlinePad: 32}, width: 1, height: 1,
+			data:    make([]byte, 4),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := decodeX11Image(tt.data, tt.width, tt.height, tt.format, palette)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("decodeX11Image() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeX11Image() error = %v", err)
+			}
+			for i, want := range tt.want {
+				x, y := i%tt.width, i/tt.width
+				if got := img.RGBAAt(x, y); got != want {
+					t.Errorf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestX11FormatStride(t *testing.T) {
+	tests := []struct {
+		bpp, pad, width, want int
+	}{
+		{32, 32, 10, 40},
+		{24, 32, 3, 12},
+		{24, 8, 3, 9},
+		{16, 32, 3, 8},
+		{16, 16, 3, 6},
+		{8, 32, 5, 8},
+		{8, 8, 5, 5},
+	}
+	for _, tt := range tests {
+		f := x11Format{bpp: tt.bpp, scanlinePad: tt.pad}
+		if got := f.stride(tt.width); got != tt.want {
+			t.Errorf("stride of %d pixels at %d bpp padded to %d bits = %d, want %d", tt.width, tt.bpp, tt.pad, got, tt.want)
+		}
+	}
+}
+
+func TestX11UpdateButtons(t *testing.T) {
+	const left, middle, right = xproto.KeyButMaskButton1, xproto.KeyButMaskButton2, xproto.KeyButMaskButton3
+	tests := []struct {
+		name  string
+		masks []uint16
+		want  []string
+	}{
+		{"no press", []uint16{0, 0}, nil},
+		{"single press", []uint16{0, left, left, 0}, []string{"left"}},
+		{"two presses", []uint16{left, 0, left}, []string{"left", "left"}},
+		{"held across polls", []uint16{right, right, right}, []string{"right"}},
+		{"second button while held", []uint16{left, left | middle, middle}, []string{"left", "middle"}},
+		{"modifiers and wheel ignored", []uint16{xproto.KeyButMaskShift, xproto.KeyButMaskButton4}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var x X11Backend
+			now := time.Now()
+			for i, mask := range tt.masks {
+				x.updateButtons(mask, i, 2*i, now)
+			}
+
+			var got []string
+			for i, c := range x.clicks {
+				got = append(got, c.Button)
+				if c.Seq != uint64(i+1) {
+					t.Errorf("click %d has number %d, want %d", i, c.Seq, i+1)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("clicks = %v, want %v", got, tt.want)
+			}
+			if x.clickSeq != uint64(len(tt.want)) {
+				t.Errorf("latest number = %d, want %d", x.clickSeq, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestX11UpdateButtonsPosition(t *testing.T) {
+	var x X11Backend
+	x.updateButtons(0, 1, 1, time.Now())
+	x.updateButtons(xproto.KeyButMaskButton1, 40, 50, time.Now())
+	if len(x.clicks) != 1 || x.clicks[0].X != 40 || x.clicks[0].Y != 50 {
+		t.Fatalf("clicks = %+v, want one click at (40, 50)", x.clicks)
+	}
+
+	// Only the most recent clicks are kept
+	for i := 0; i < maxX11Clicks+10; i++ {
+		x.updateButtons(0, 0, 0, time.Now())
+		x.updateButtons(xproto.KeyButMaskButton1, 0, 0, time.Now())
+	}
+	if len(x.clicks) != maxX11Clicks {
+		t.Errorf("kept %d clicks, want %d", len(x.clicks), maxX11Clicks)
+	}
+	if last := x.clicks[len(x.clicks)-1].Seq; last != x.clickSeq {
+		t.Errorf("last kept click = %d, want %d", last, x.clickSeq)
+	}
+}
@@ -0,0 +1,103 @@
+package automation
+
+import (
+	"context"
+	"time"
+
+	"github.com/jezek/xgb/xproto"
+)
+
+// x11ClickPoll is how often the pointer buttons are polled while clicks are
+// observed. The X protocol only reports button presses to the window under
+// the pointer, so presses elsewhere on the display are found by polling.
+const x11ClickPoll = 5 * time.Millisecond
+
+// x11ClickHold is how long Click holds a button down, so that processes
+// observing clicks by polling see it
+const x11ClickHold = 3 * x11ClickPoll
+
+// x11ClickButtons are the pointer buttons reported as clicks
+var x11ClickButtons = []struct {
+	name string
+	mask uint16
+}{
+	{"left", xproto.KeyButMaskButton1},
+	{"middle", xproto.KeyButMaskButton2},
+	{"right", xproto.KeyButMaskButton3},
+}
+
+// maxX11Clicks is the number of recent clicks kept for ClicksSince
+const maxX11Clicks = 100
+
+var _ ClickObserver = (*X11Backend)(nil)
+
+// ClicksSince returns the button presses on the display numbered after seq.
+// The first call starts watching the pointer buttons, so it only reports the
+// latest number. Presses shorter than a few milliseconds, such as instant
+// clicks injected by other processes, may be missed.
+func (x *X11Backend) ClicksSince(ctx context.Context, seq uint64) ([]ClickEvent, uint64, error) {
+	x.clickMu.Lock()
+	defer x.clickMu.Unlock()
+
+	if !x.watching {
+		x.watching = true
+		x.pollButtons()
+		go x.watchButtons()
+	}
+
+	var clicks []ClickEvent
+	for _, c := range x.clicks {
+		if c.Seq > seq {
+			clicks = append(clicks, c)
+		}
+	}
+	return clicks, x.clickSeq, nil
+}
+
+// watchButtons polls the pointer buttons until the backend is closed
+func (x *X11Backend) watchButtons() {
+	ticker := time.NewTicker(x11ClickPoll)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-x.done:
+			return
+		case <-ticker.C:
+		}
+
+		x.clickMu.Lock()
+		select {
+		case <-x.done:
+		default:
+			x.pollButtons()
+		}
+		x.clickMu.Unlock()
+	}
+}
+
+// pollButtons reads the state of the pointer buttons and records new presses.
+// The caller must hold clickMu.
+func (x *X11Backend) pollButtons() {
+	reply, err := xproto.QueryPointer(x.conn, x.root).Reply()
+	if err != nil {
+		return
+	}
+	x.updateButtons(reply.Mask, int(reply.RootX), int(reply.RootY), time.Now())
+}
+
+// updateButtons records a click for every button pressed in mask that was up
+// before. The caller must hold clickMu.
+func (x *X11Backend) updateButtons(mask uint16, px, py int, now time.Time) {
+	for _, b := range x11ClickButtons {
+		down := mask&b.mask != 0
+		if down && x.pressed&b.mask == 0 {
+			x.clickSeq++
+			x.clicks = append(x.clicks, ClickEvent{Seq: x.clickSeq, X: px, Y: py, Button: b.name, Time: now})
+		}
+	}
+	if len(x.clicks) > maxX11Clicks {
+		x.clicks = x.clicks[len(x.clicks)-maxX11Clicks:]
+	}
+	x.pressed = mask
+}
@@ -0,0 +1,79 @@
+package automation
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/jezek/xgb/shm"
+	"github.com/jezek/xgb/xproto"
+	"golang.org/x/sys/unix"
+)
+
+// captureShm captures a region of the root window into a System V shared
+// memory segment attached with MIT-SHM, which avoids sending the pixels over
+// the connection. It fails if the server is remote or lacks the extension.
+func (x *X11Backend) captureShm(region image.Rectangle, size int) ([]byte, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if !x.shmOK {
+		return nil, fmt.Errorf("MIT-SHM is not available")
+	}
+
+	if len(x.shmData) < size {
+		x.releaseShm()
+		if err := x.attachShm(size); err != nil {
+			// Typically the server is on another machine, so do not try again
+			x.shmOK = false
+			return nil, err
+		}
+	}
+
+	_, err := shm.GetImage(x.conn, xproto.Drawable(x.root), int16(region.Min.X), int16(region.Min.Y),
+		uint16(region.Dx()), uint16(region.Dy()), 0xffffffff, xproto.ImageFormatZPixmap, x.shmSeg, 0).Reply()
+	if err != nil {
+		return nil, fmt.Errorf("MIT-SHM capture failed: %w", err)
+	}
+
+	pixels := make([]byte, size)
+	copy(pixels, x.shmData)
+	return pixels, nil
+}
+
+// attachShm creates a shared memory segment of size bytes and attaches it to
+// both this process and the X server
+func (x *X11Backend) attachShm(size int) error {
+	id, err := unix.SysvShmGet(unix.IPC_PRIVATE, size, unix.IPC_CREAT|0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create shared memory segment: %w", err)
+	}
+	// The segment is removed once both sides have detached
+	defer unix.SysvShmCtl(id, unix.IPC_RMID, nil)
+
+	data, err := unix.SysvShmAttach(id, 0, 0)
+	if err != nil {
+		return fmt.Errorf("failed to attach shared memory segment: %w", err)
+	}
+
+	seg, err := shm.NewSegId(x.conn)
+	if err == nil {
+		err = shm.AttachChecked(x.conn, seg, uint32(id), false).Check()
+	}
+	if err != nil {
+		unix.SysvShmDetach(data)
+		return fmt.Errorf("X server failed to attach shared memory segment: %w", err)
+	}
+
+	x.shmSeg, x.shmData = seg, data
+	return nil
+}
+
+// releaseShm detaches the shared memory segment, if any
+func (x *X11Backend) releaseShm() {
+	if x.shmData == nil {
+		return
+	}
+	shm.DetachChecked(x.conn, x.shmSeg).Check()
+	unix.SysvShmDetach(x.shmData)
+	x.shmData = nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package automation
+
+import (
+	"fmt"
+	"image"
+)
+
+// captureShm is only implemented on Linux; elsewhere GetImage is used
+func (x *X11Backend) captureShm(region image.Rectangle, size int) ([]byte, error) {
+	return nil, fmt.Errorf("MIT-SHM is not supported on this platform")
+}
+
+func (x *X11Backend) releaseShm() {}
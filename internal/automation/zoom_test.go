@@ -0,0 +1,61 @@
+package automation
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLegendStep(t *testing.T) {
+	tests := []struct {
+		scale int
+		want  int
+	}{
+		{1, 100},
+		{2, 50},
+		{3, 20},
+		{4, 20},
+		{5, 20},
+		{6, 10},
+		{7, 10},
+		{12, 5},
+		{16, 5},
+		{30, 2},
+		{60, 1},
+		{100, 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.scale), func(t *testing.T) {
+			got := legendStep(tt.scale)
+			if got != tt.want {
+				t.Errorf("legendStep(%d) = %d, want %d", tt.scale, got, tt.want)
+			}
+			if got*tt.scale < legendSpacing {
+				t.Errorf("legendStep(%d) = %d puts lines %d pixels apart, want at least %d", tt.scale, got, got*tt.scale, legendSpacing)
+			}
+		})
+	}
+}
+
+func TestParseZoomFilter(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ZoomFilter
+		wantErr bool
+	}{
+		{"", ZoomNearest, false},
+		{"nearest", ZoomNearest, false},
+		{"Smooth", ZoomSmooth, false},
+		{"bilinear", ZoomNearest, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseZoomFilter(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseZoomFilter(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseZoomFilter(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,529 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+	"github.com/spf13/cobra"
+)
+
+// a11yHelp documents the element selection flags shared by the a11y subcommands
+const a11yHelp = `Elements are selected with --app, --role, --name and --state. Names and
+application names match case-insensitively and may use * and ? wildcards.
+A role matches its full name ("push button") or its last word ("button").
+Elements that are not showing on the screen are skipped unless --all is given.`
+
+// a11yFlags holds the element selection flags of the a11y subcommands
+type a11yFlags struct {
+	query   automation.A11yQuery
+	jsonOut bool
+	index   int
+}
+
+// register adds the selection flags to a command; selector adds the flags
+// that select elements rather than applications
+func (f *a11yFlags) register(cmd *cobra.Command, selector bool) {
+	cmd.Flags().StringVar(&f.query.App, "app", "", "Only search applications with this name")
+	cmd.Flags().BoolVar(&f.query.All, "all", false, "Include elements that are not showing")
+	cmd.Flags().IntVar(&f.query.MaxDepth, "depth", 0, "Maximum depth below the applications (default: no limit)")
+	if selector {
+		cmd.Flags().StringVar(&f.query.Role, "role", "", "Role of the element, e.g. button, text or menu item")
+		cmd.Flags().StringVar(&f.query.Name, "name", "", "Accessible name of the element, e.g. Save or 'Save*'")
+		cmd.Flags().StringArrayVar(&f.query.States, "state", nil, "State the element must have, e.g. focused (repeatable)")
+	}
+}
+
+// NewA11yCommand creates the a11y command
+func NewA11yCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "a11y",
+		Short: "Inspect and use the accessibility tree",
+		Long: `Inspect and use the accessibility tree of the desktop.
+
+Applications built with GTK, Qt and other toolkits describe their windows and
+controls over AT-SPI, the Linux accessibility interface on D-Bus. Finding an
+element by its role and accessible name is more robust than clicking pixel
+coordinates: it keeps working when the layout, theme or resolution changes.
+
+Qt applications only expose their tree when QT_ACCESSIBILITY=1 is set or
+assistive technologies are enabled in the desktop settings.`,
+		Example: `  # Show the tree of an application
+  desktop-automation a11y tree --app gedit
+
+  # Find buttons named Save
+  desktop-automation a11y find --role button --name Save
+
+  # Click the Save button
+  desktop-automation a11y click --app gedit --name Save
+
+  # Press the Save button without moving the mouse
+  desktop-automation a11y invoke --app gedit --name Save`,
+	}
+
+	cmd.AddCommand(
+		newA11yTreeCommand(),
+		newA11yFindCommand(),
+		newA11yClickCommand(),
+		newA11yInvokeCommand(),
+		newA11ySetTextCommand(),
+		newA11ySetValueCommand(),
+	)
+
+	return cmd
+}
+
+// newA11yTreeCommand creates the a11y tree subcommand
+func newA11yTreeCommand() *cobra.Command {
+	var flags a11yFlags
+
+	cmd := &cobra.Command{
+		Use:   "tree",
+		Short: "Print the accessibility tree",
+		Long: `Print the accessibility tree of all applications or those selected with --app.
+
+Every element is printed with its role, name and bounds in the space selected
+with --coords. With --json the tree is printed as JSON including states,
+actions and text.`,
+		Example: `  # Print the first three levels of an application
+  desktop-automation a11y tree --app firefox --depth 3
+
+  # Dump the tree as JSON
+  desktop-automation a11y tree --app gedit --json > tree.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runA11yTreeCommand(cmd, flags)
+		},
+	}
+
+	flags.register(cmd, false)
+	cmd.Flags().BoolVar(&flags.jsonOut, "json", false, "Print the tree as JSON")
+
+	return cmd
+}
+
+// runA11yTreeCommand handles the a11y tree command execution
+func runA11yTreeCommand(cmd *cobra.Command, flags a11yFlags) error {
+	cs, err := coordSystem()
+	if err != nil {
+		return err
+	}
+
+	a11y, err := automation.OpenA11y(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer a11y.Close()
+
+	apps, err := a11y.Tree(cmd.Context(), flags.query)
+	if err != nil {
+		return err
+	}
+	automation.ConvertA11yBounds(apps, cs)
+
+	if flags.jsonOut {
+		return printJSON(apps)
+	}
+	if len(apps) == 0 {
+		return fmt.Errorf("no accessible applications found")
+	}
+
+	var printTree func(e *automation.A11yElement, indent string)
+	printTree = func(e *automation.A11yElement, indent string) {
+		fmt.Println(indent + describeA11yElement(e))
+		for _, c := range e.Children {
+			printTree(c, indent+"  ")
+		}
+	}
+	for _, app := range apps {
+		printTree(app, "")
+	}
+	return nil
+}
+
+// newA11yFindCommand creates the a11y find subcommand
+func newA11yFindCommand() *cobra.Command {
+	var flags a11yFlags
+
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find elements in the accessibility tree",
+		Long: `Find elements in the accessibility tree.
+
+Every match is printed with its application, role, name, bounds in the space
+selected with --coords, states and text. With --json the matches are printed
+as a JSON array.
+
+` + a11yHelp,
+		Example: `  # Find all buttons named Save
+  desktop-automation a11y find --role button --name Save
+
+  # Find the focused text field of an application
+  desktop-automation a11y find --app gedit --role text --state focused --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runA11yFindCommand(cmd, flags)
+		},
+	}
+
+	flags.register(cmd, true)
+	cmd.Flags().BoolVar(&flags.jsonOut, "json", false, "Print the matches as JSON")
+
+	return cmd
+}
+
+// runA11yFindCommand handles the a11y find command execution
+func runA11yFindCommand(cmd *cobra.Command, flags a11yFlags) error {
+	cs, err := coordSystem()
+	if err != nil {
+		return err
+	}
+
+	found, err := findA11yElements(cmd.Context(), flags.query)
+	if err != nil {
+		return err
+	}
+	automation.ConvertA11yBounds(found, cs)
+
+	if flags.jsonOut {
+		if found == nil {
+			found = []*automation.A11yElement{}
+		}
+		return printJSON(found)
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("no accessible element matches %s", describeA11yQuery(flags.query))
+	}
+
+	for _, e := range found {
+		line := e.App + ": " + describeA11yElement(e)
+		if len(e.States) > 0 {
+			line += " [" + strings.Join(e.States, ", ") + "]"
+		}
+		if e.Text != "" {
+			line += fmt.Sprintf(" text %q", truncate(e.Text, 60))
+		}
+		if e.Value != nil {
+			line += fmt.Sprintf(" value %g", *e.Value)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// newA11yClickCommand creates the a11y click subcommand
+func newA11yClickCommand() *cobra.Command {
+	var flags a11yFlags
+
+	cmd := &cobra.Command{
+		Use:   "click",
+		Short: "Click an element of the accessibility tree",
+		Long: `Click the centre of an element of the accessibility tree.
+
+The element must be selected with at least --name or --role. When several
+elements match, the first in tree order is clicked unless --index selects
+another one (counting from 0).
+
+` + a11yHelp,
+		Example: `  # Click the Save button
+  desktop-automation a11y click --role button --name Save
+
+  # Click the second "OK" button of a dialog
+  desktop-automation a11y click --app gedit --name OK --index 1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runA11yClickCommand(cmd, flags)
+		},
+	}
+
+	flags.register(cmd, true)
+	cmd.Flags().IntVar(&flags.index, "index", 0, "Which of several matching elements to click, from 0")
+
+	return cmd
+}
+
+// runA11yClickCommand handles the a11y click command execution
+func runA11yClickCommand(cmd *cobra.Command, flags a11yFlags) error {
+	cs, err := coordSystem()
+	if err != nil {
+		return err
+	}
+
+	a11y, err := automation.OpenA11y(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer a11y.Close()
+
+	e, err := selectA11yElement(cmd.Context(), a11y, flags)
+	if err != nil {
+		return err
+	}
+
+	x, y, ok := e.Center()
+	if !ok {
+		return fmt.Errorf("%s has no position on the screen", a11yElementName(e))
+	}
+
+	targetX, targetY := cs.FromLogical(x, y)
+	fmt.Printf("Clicking %s at (%d, %d)...\n", a11yElementName(e), targetX, targetY)
+	if err := automation.Click(cmd.Context(), x, y); err != nil {
+		return fmt.Errorf("failed to click at (%d, %d): %w", targetX, targetY, err)
+	}
+
+	fmt.Printf("✓ Successfully clicked %s\n", a11yElementName(e))
+	return nil
+}
+
+// newA11yInvokeCommand creates the a11y invoke subcommand
+func newA11yInvokeCommand() *cobra.Command {
+	var flags a11yFlags
+	var action string
+
+	cmd := &cobra.Command{
+		Use:   "invoke",
+		Short: "Perform an action of an element without synthesizing input",
+		Long: `Perform an action of an element of the accessibility tree.
+
+The action is performed by the application itself through AT-SPI, without
+moving the mouse or pressing keys, so it works even when the window is covered
+by another one or off-screen. Without --action the element's default action is
+performed: click, press, activate, toggle, jump or open, whichever it offers
+first. The actions of an element are listed by "a11y find --json".
+
+` + a11yHelp,
+		Example: `  # Press the Save button
+  desktop-automation a11y invoke --app gedit --role button --name Save
+
+  # Expand a tree item
+  desktop-automation a11y invoke --role "table cell" --name Documents --action "expand or contract"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runA11yInvokeCommand(cmd, flags, action)
+		},
+	}
+
+	flags.register(cmd, true)
+	cmd.Flags().IntVar(&flags.index, "index", 0, "Which of several matching elements to use, from 0")
+	cmd.Flags().StringVar(&action, "action", "", "Name of the action (default: the element's default action)")
+
+	return cmd
+}
+
+// runA11yInvokeCommand handles the a11y invoke command execution
+func runA11yInvokeCommand(cmd *cobra.Command, flags a11yFlags, action string) error {
+	a11y, err := automation.OpenA11y(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer a11y.Close()
+
+	e, err := selectA11yElement(cmd.Context(), a11y, flags)
+	if err != nil {
+		return err
+	}
+
+	done, err := a11y.DoAction(cmd.Context(), e, action)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Performed %q on %s\n", done, a11yElementName(e))
+	return nil
+}
+
+// newA11ySetTextCommand creates the a11y set-text subcommand
+func newA11ySetTextCommand() *cobra.Command {
+	var flags a11yFlags
+
+	cmd := &cobra.Command{
+		Use:   "set-text <text>",
+		Short: "Replace the text of an editable element without typing",
+		Long: `Replace the text of an editable element of the accessibility tree.
+
+The text is set by the application itself through AT-SPI instead of being
+typed, so it works even when the window is covered, does not depend on the
+keyboard layout and does not fire per-key shortcuts.
+
+` + a11yHelp,
+		Example: `  # Fill a search field
+  desktop-automation a11y set-text --app firefox --role entry --name Search "desktop automation"
+
+  # Clear the focused text field
+  desktop-automation a11y set-text --role text --state focused ""`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runA11ySetTextCommand(cmd, flags, args[0])
+		},
+	}
+
+	flags.register(cmd, true)
+	cmd.Flags().IntVar(&flags.index, "index", 0, "Which of several matching elements to use, from 0")
+
+	return cmd
+}
+
+// runA11ySetTextCommand handles the a11y set-text command execution
+func runA11ySetTextCommand(cmd *cobra.Command, flags a11yFlags, text string) error {
+	a11y, err := automation.OpenA11y(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer a11y.Close()
+
+	e, err := selectA11yElement(cmd.Context(), a11y, flags)
+	if err != nil {
+		return err
+	}
+
+	if err := a11y.SetText(cmd.Context(), e, text); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Set text of %s to %q\n", a11yElementName(e), text)
+	return nil
+}
+
+// newA11ySetValueCommand creates the a11y set-value subcommand
+func newA11ySetValueCommand() *cobra.Command {
+	var flags a11yFlags
+
+	cmd := &cobra.Command{
+		Use:   "set-value <value>",
+		Short: "Set the value of a slider or spin button without dragging",
+		Long: `Set the current value of an element of the accessibility tree, such as a
+slider, spin button or progress bar.
+
+The value is set by the application itself through AT-SPI instead of dragging
+or typing, and must lie between the element's minimum and maximum value.
+
+` + a11yHelp,
+		Example: `  # Set a volume slider to 50
+  desktop-automation a11y set-value --role slider --name Volume 50`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := strconv.ParseFloat(args[0], 64)
+			if err != nil {
+				return fmt.Errorf("invalid value '%s': must be a number", args[0])
+			}
+			return runA11ySetValueCommand(cmd, flags, value)
+		},
+	}
+
+	flags.register(cmd, true)
+	cmd.Flags().IntVar(&flags.index, "index", 0, "Which of several matching elements to use, from 0")
+
+	return cmd
+}
+
+// runA11ySetValueCommand handles the a11y set-value command execution
+func runA11ySetValueCommand(cmd *cobra.Command, flags a11yFlags, value float64) error {
+	a11y, err := automation.OpenA11y(cmd.Context())
+	if err != nil {
+		return err
+	}
+	defer a11y.Close()
+
+	e, err := selectA11yElement(cmd.Context(), a11y, flags)
+	if err != nil {
+		return err
+	}
+
+	if err := a11y.SetValue(cmd.Context(), e, value); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Set value of %s to %g\n", a11yElementName(e), value)
+	return nil
+}
+
+// findA11yElements runs a query on the accessibility tree
+func findA11yElements(ctx context.Context, q automation.A11yQuery) ([]*automation.A11yElement, error) {
+	a11y, err := automation.OpenA11y(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer a11y.Close()
+
+	return a11y.Find(ctx, q)
+}
+
+// selectA11yElement finds the element selected by the flags of a subcommand
+// acting on a single element
+func selectA11yElement(ctx context.Context, a11y *automation.A11y, flags a11yFlags) (*automation.A11yElement, error) {
+	if flags.query.Name == "" && flags.query.Role == "" {
+		return nil, fmt.Errorf("--name or --role is required")
+	}
+
+	found, err := a11y.Find(ctx, flags.query)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("no accessible element matches %s", describeA11yQuery(flags.query))
+	}
+	if flags.index < 0 || flags.index >= len(found) {
+		return nil, fmt.Errorf("--index %d out of range: %d elements match %s", flags.index, len(found), describeA11yQuery(flags.query))
+	}
+	if len(found) > 1 && flags.index == 0 {
+		fmt.Printf("Found %d matching elements, using the first (select another with --index)\n", len(found))
+	}
+	return found[flags.index], nil
+}
+
+// describeA11yElement formats an element as its role, name and bounds
+func describeA11yElement(e *automation.A11yElement) string {
+	s := a11yElementName(e)
+	if b := e.Bounds; b != nil && b.Width > 0 && b.Height > 0 {
+		s += fmt.Sprintf(" at %d,%d %dx%d", b.X, b.Y, b.Width, b.Height)
+	}
+	return s
+}
+
+// a11yElementName formats an element as its role and name
+func a11yElementName(e *automation.A11yElement) string {
+	if e.Name == "" {
+		return e.Role
+	}
+	return fmt.Sprintf("%s %q", e.Role, e.Name)
+}
+
+// describeA11yQuery formats the filters of a query for error messages
+func describeA11yQuery(q automation.A11yQuery) string {
+	var parts []string
+	if q.App != "" {
+		parts = append(parts, fmt.Sprintf("app %q", q.App))
+	}
+	if q.Role != "" {
+		parts = append(parts, fmt.Sprintf("role %q", q.Role))
+	}
+	if q.Name != "" {
+		parts = append(parts, fmt.Sprintf("name %q", q.Name))
+	}
+	for _, s := range q.States {
+		parts = append(parts, fmt.Sprintf("state %q", s))
+	}
+	if len(parts) == 0 {
+		return "the query"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+// printJSON prints a value as indented JSON
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
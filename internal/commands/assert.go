@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+	"github.com/dmahlow/desktop-automation/internal/visual"
+	"github.com/spf13/cobra"
+)
+
+// assertScreenOptions holds the flags of the assert-screen command
+type assertScreenOptions struct {
+	golden    string
+	region    image.Rectangle
+	mask      string
+	tolerance visual.Tolerance
+	threshold uint8
+	diff      string
+	update    bool
+}
+
+// NewAssertScreenCommand creates the assert-screen command
+func NewAssertScreenCommand() *cobra.Command {
+	var opts assertScreenOptions
+	var region, tolerance string
+
+	cmd := &cobra.Command{
+		Use:   "assert-screen --golden <file> [flags]",
+		Short: "Compare the screen to a golden image",
+		Long: `Compare the screen to a golden image for visual regression tests.
+
+The whole screen, or the region given with --region as X,Y,WIDTH,HEIGHT in the
+space selected with --coords, is captured and compared pixel by pixel to the
+PNG file given with --golden. Both are in physical pixels.
+
+Regions that change between runs, such as clocks or avatars, can be ignored
+with --mask, a JSON file holding an array of rectangles in pixels of the
+golden image:
+
+  [{"name": "clock", "x": 1800, "y": 0, "width": 120, "height": 32}]
+
+The comparison passes when the number of differing pixels is within
+--tolerance, given as a pixel count or a percentage of the compared pixels.
+Pixels whose colour channels all differ by at most --threshold count as equal.
+
+On failure a diff image is written next to the golden image (or to --diff),
+showing the capture faded out with masked regions in blue and differing pixels
+in red, and the command exits with a non-zero status. Run with --update to
+create or replace the golden image from the current screen.`,
+		Example: `  # Record the golden image once
+  desktop-automation assert-screen --golden login.png --region 660,340,600,400 --update
+
+  # Compare, ignoring the clock and allowing 0.5% of the pixels to differ
+  desktop-automation assert-screen --golden login.png --region 660,340,600,400 \
+    --mask dynamic.json --tolerance 0.5%`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.golden == "" {
+				return fmt.Errorf("--golden is required")
+			}
+			cs, err := coordSystem()
+			if err != nil {
+				return err
+			}
+			if opts.region, err = parseRegion(cs, region); err != nil {
+				return err
+			}
+			if opts.tolerance, err = visual.ParseTolerance(tolerance); err != nil {
+				return err
+			}
+			if opts.diff == "" {
+				opts.diff = strings.TrimSuffix(opts.golden, filepath.Ext(opts.golden)) + ".diff.png"
+			}
+			return runAssertScreenCommand(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.golden, "golden", "", "Golden PNG image to compare against")
+	cmd.Flags().StringVar(&region, "region", "", "Compare only X,Y,WIDTH,HEIGHT (default: whole screen)")
+	cmd.Flags().StringVar(&opts.mask, "mask", "", "JSON file with regions of the golden image to ignore")
+	cmd.Flags().StringVar(&tolerance, "tolerance", "0", "Allowed differing pixels as a count or a percentage, e.g. 0.5%")
+	cmd.Flags().Uint8Var(&opts.threshold, "threshold", 0, "Allowed difference per colour channel (0-255)")
+	cmd.Flags().StringVar(&opts.diff, "diff", "", "Where to write the diff image on failure (default: <golden>.diff.png)")
+	cmd.Flags().BoolVar(&opts.update, "update", false, "Write the capture as the new golden image")
+
+	return cmd
+}
+
+// runAssertScreenCommand handles the assert-screen command execution
+func runAssertScreenCommand(cmd *cobra.Command, opts assertScreenOptions) error {
+	var mask []visual.MaskRegion
+	if opts.mask != "" {
+		var err error
+		if mask, err = visual.LoadMask(opts.mask); err != nil {
+			return err
+		}
+	}
+
+	actual, err := automation.CaptureImage(cmd.Context(), opts.region)
+	if err != nil {
+		return fmt.Errorf("failed to capture screen: %w", err)
+	}
+
+	if opts.update {
+		if dir := filepath.Dir(opts.golden); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", dir, err)
+			}
+		}
+		if err := automation.SavePNG(actual, opts.golden); err != nil {
+			return fmt.Errorf("failed to save golden image: %w", err)
+		}
+		size := actual.Bounds().Size()
+		fmt.Printf("✓ Updated golden image %s (%dx%d)\n", opts.golden, size.X, size.Y)
+		return nil
+	}
+
+	golden, err := loadPNG(opts.golden)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("golden image %s does not exist, create it with --update", opts.golden)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load golden image: %w", err)
+	}
+
+	result, err := visual.Compare(golden, actual, mask, opts.threshold)
+	if err != nil {
+		return err
+	}
+
+	if result.Passed(opts.tolerance) {
+		// A diff left over from an earlier failure no longer applies
+		os.Remove(opts.diff)
+		fmt.Printf("✓ Screen matches %s (%d of %d pixels differ, tolerance %s)\n",
+			opts.golden, result.Different, result.Compared, opts.tolerance)
+		return nil
+	}
+
+	if err := automation.SavePNG(result.Diff, opts.diff); err != nil {
+		return fmt.Errorf("failed to save diff image: %w", err)
+	}
+	b := result.Bounds
+	return fmt.Errorf("screen differs from %s: %d of %d pixels (%.3f%%) differ within %d,%d,%d,%d, tolerance %s; diff written to %s",
+		opts.golden, result.Different, result.Compared, result.Ratio()*100,
+		b.Min.X, b.Min.Y, b.Dx(), b.Dy(), opts.tolerance, opts.diff)
+}
+
+// loadPNG reads a PNG image from a file
+func loadPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
@@ -2,7 +2,6 @@ package commands
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/dmahlow/desktop-automation/internal/automation"
 	"github.com/spf13/cobra"
@@ -10,13 +9,23 @@ import (
 
 // NewClickCommand creates the click command
 func NewClickCommand() *cobra.Command {
+	var opts clickOptions
+
 	cmd := &cobra.Command{
-		Use:   "click <x> <y>",
+		Use:   "click <x> <y> | click <target> | click --color <color>",
 		Short: "Click at a specific screen coordinate",
 		Long: `Click at a specific screen coordinate.
 
 This command simulates a mouse click at the specified x and y coordinates on the screen.
-The coordinates are measured in pixels from the top-left corner of the screen (0,0).`,
+The coordinates are measured from the top-left corner of the screen (0,0) in the
+space selected with --coords (logical by default).
+
+` + targetHelp + `
+
+With --color no coordinates are given: the screen, or the region given with
+--region as X,Y,WIDTH,HEIGHT, is searched for pixels of that colour and the
+centre of the largest matching area is clicked. Colours are #rrggbb, #rgb or
+r,g,b, and --tolerance allows each channel to differ by that much.`,
 		Example: `  # Click at coordinates (100, 200)
   desktop-automation click 100 200
 
@@ -24,50 +33,82 @@ The coordinates are measured in pixels from the top-left corner of the screen (0
   desktop-automation click 960 540
 
   # Click at the top-left corner
-  desktop-automation click 0 0`,
-		Args: cobra.ExactArgs(2),
-		RunE: runClickCommand,
+  desktop-automation click 0 0
+
+  # Click at a position read off a screenshot on a HiDPI display
+  desktop-automation --coords physical click 1920 1080
+
+  # Click 50 pixels right of and 20 pixels above the cursor
+  desktop-automation click +50,-20
+
+  # Click the center of the active window
+  desktop-automation --relative-to window click @center
+
+  # Click at 25% of the screen width and 75% of its height
+  desktop-automation click 25% 75%
+
+  # Click the red status dot in the top bar
+  desktop-automation click --color "#ff0000" --region 0,0,1920,40`,
+		Args: cobra.RangeArgs(0, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runClickCommand(cmd, args, opts)
+		},
 	}
 
+	cmd.Flags().StringVar(&opts.color, "color", "", "Click the largest area of this colour instead of a coordinate")
+	cmd.Flags().StringVar(&opts.region, "region", "", "Search only X,Y,WIDTH,HEIGHT for --color (default: whole screen)")
+	cmd.Flags().Uint8Var(&opts.tolerance, "tolerance", 16, "Allowed difference per colour channel for --color (0-255)")
+
 	return cmd
 }
 
+// clickOptions holds the flags of the click command
+type clickOptions struct {
+	color     string
+	region    string
+	tolerance uint8
+}
+
 // runClickCommand handles the click command execution
-func runClickCommand(cmd *cobra.Command, args []string) error {
-	// Parse X coordinate
-	x, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("invalid x coordinate '%s': must be a valid integer", args[0])
+func runClickCommand(cmd *cobra.Command, args []string, opts clickOptions) error {
+	// Parse and resolve the target to logical coordinates
+	var x, y int
+	var cs automation.CoordSystem
+	var err error
+	switch {
+	case opts.color != "" && len(args) > 0:
+		return fmt.Errorf("--color cannot be combined with a target")
+	case opts.color != "":
+		if cs, err = coordSystem(); err != nil {
+			return err
+		}
+		x, y, err = findColorTarget(cmd.Context(), cs, opts.color, opts.region, opts.tolerance)
+	case opts.region != "":
+		return fmt.Errorf("--region requires --color")
+	case len(args) == 0:
+		return fmt.Errorf("a target or --color is required")
+	default:
+		x, y, cs, err = resolveTarget(cmd.Context(), args)
 	}
-
-	// Parse Y coordinate
-	y, err := strconv.Atoi(args[1])
 	if err != nil {
-		return fmt.Errorf("invalid y coordinate '%s': must be a valid integer", args[1])
-	}
-
-	// Validate coordinates are non-negative
-	if x < 0 {
-		return fmt.Errorf("x coordinate cannot be negative: %d", x)
-	}
-	if y < 0 {
-		return fmt.Errorf("y coordinate cannot be negative: %d", y)
+		return err
 	}
 
 	// Show current mouse position before click
-	currentX, currentY := automation.GetPosition()
+	currentX, currentY := cs.FromLogical(automation.GetPosition())
 	fmt.Printf("Current mouse position: (%d, %d)\n", currentX, currentY)
 
 	// Perform the click using our automation
-	fmt.Printf("Clicking at coordinates (%d, %d)...\n", x, y)
+	targetX, targetY := cs.FromLogical(x, y)
+	fmt.Printf("Clicking at coordinates (%d, %d)...\n", targetX, targetY)
 
-	err = automation.Click(x, y)
+	err = automation.Click(cmd.Context(), x, y)
 	if err != nil {
-		return fmt.Errorf("failed to click at (%d, %d): %w", x, y, err)
+		return fmt.Errorf("failed to click at (%d, %d): %w", targetX, targetY, err)
 	}
 
 	// Confirm success with coordinates
-	fmt.Printf("✓ Successfully clicked at coordinates (%d, %d)\n", x, y)
+	fmt.Printf("✓ Successfully clicked at coordinates (%d, %d)\n", targetX, targetY)
 
 	return nil
 }
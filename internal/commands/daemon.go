@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+	"github.com/dmahlow/desktop-automation/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+// NewDaemonCommand creates the daemon command
+func NewDaemonCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Run a long-lived automation daemon on a local socket",
+		Long: `Run a long-lived automation daemon on a local Unix socket.
+
+The daemon owns the automation backend and serves a JSON-RPC API mirroring the
+automation package on the socket given with --socket. While it is running all
+other commands transparently route through it, which avoids re-initializing the
+backend on every invocation and lets invocations share state: keys held with
+"key --down" stay held and anchors defined with --anchor stay defined.
+
+Pass --no-daemon to a command to bypass a running daemon, and --backend to the
+daemon to serve a backend other than the local desktop. Stop the daemon with
+Ctrl+C; keys still held through it are released on exit.`,
+		Example: `  # Start the daemon in the background
+  desktop-automation daemon &
+
+  # Subsequent commands are routed through it
+  desktop-automation move 800 600
+  desktop-automation key --down shift
+  desktop-automation click 100 200
+  desktop-automation key --up shift
+
+  # Use a custom socket
+  desktop-automation daemon --socket /tmp/automation.sock`,
+		Args: cobra.NoArgs,
+		// The daemon serves the backend itself and must not connect to another daemon
+		PersistentPreRunE: openBackend,
+		RunE:              runDaemonCommand,
+	}
+
+	return cmd
+}
+
+// runDaemonCommand handles the daemon command execution
+func runDaemonCommand(cmd *cobra.Command, args []string) error {
+	service := daemon.NewService(automation.CurrentBackend())
+
+	fmt.Printf("Listening on %s (press Ctrl+C to stop)\n", socketPath)
+	if err := daemon.Serve(cmd.Context(), socketPath, service); err != nil {
+		return fmt.Errorf("daemon failed: %w", err)
+	}
+
+	fmt.Println("✓ Daemon stopped")
+	return nil
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+	"github.com/spf13/cobra"
+)
+
+// NewKeyCommand creates the key command
+func NewKeyCommand() *cobra.Command {
+	var down, up bool
+
+	cmd := &cobra.Command{
+		Use:   "key <key>[+<key>...]",
+		Short: "Press a key or key combination",
+		Long: `Press a key or key combination.
+
+Keys are given by name (enter, tab, space, a, f5, ...) and combined with "+",
+where all but the last key are held as modifiers, e.g. ctrl+shift+t.
+
+Use --down to hold a key and --up to release it later. Held keys outlive the
+command only while the daemon is running, which also releases them on exit.`,
+		Example: `  # Press enter
+  desktop-automation key enter
+
+  # Copy the selection
+  desktop-automation key ctrl+c
+
+  # Hold shift across several commands (with the daemon running)
+  desktop-automation key --down shift
+  desktop-automation click 100 200
+  desktop-automation key --up shift`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runKeyCommand(cmd, args, down, up)
+		},
+	}
+
+	cmd.Flags().BoolVar(&down, "down", false, "Hold the key down instead of pressing it")
+	cmd.Flags().BoolVar(&up, "up", false, "Release a held key")
+	cmd.MarkFlagsMutuallyExclusive("down", "up")
+
+	return cmd
+}
+
+// runKeyCommand handles the key command execution
+func runKeyCommand(cmd *cobra.Command, args []string, down, up bool) error {
+	keys := strings.Split(strings.ToLower(args[0]), "+")
+	for _, key := range keys {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid key combination '%s': empty key name", args[0])
+		}
+	}
+
+	var err error
+	switch {
+	case down:
+		for _, key := range keys {
+			if err = automation.HoldKey(cmd.Context(), key); err != nil {
+				break
+			}
+		}
+	case up:
+		// Release in reverse order so modifiers are released last
+		for i := len(keys) - 1; i >= 0; i-- {
+			if err = automation.ReleaseKey(cmd.Context(), keys[i]); err != nil {
+				break
+			}
+		}
+	default:
+		err = automation.PressKeyCombo(cmd.Context(), keys...)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to press '%s': %w", args[0], err)
+	}
+
+	switch {
+	case down:
+		fmt.Printf("✓ Holding %s\n", args[0])
+	case up:
+		fmt.Printf("✓ Released %s\n", args[0])
+	default:
+		fmt.Printf("✓ Pressed %s\n", args[0])
+	}
+	return nil
+}
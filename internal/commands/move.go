@@ -2,7 +2,6 @@ package commands
 
 import (
 	"fmt"
-	"strconv"
 	"time"
 
 	"github.com/dmahlow/desktop-automation/internal/automation"
@@ -13,17 +12,27 @@ import (
 func NewMoveCommand() *cobra.Command {
 	var smooth bool
 	var duration float64
+	var path, easing string
+	var overshoot bool
+	var jitter float64
+	var seed int64
 
 	cmd := &cobra.Command{
-		Use:   "move <x> <y>",
+		Use:   "move <x> <y> | move <target>",
 		Short: "Move the mouse cursor to coordinates",
 		Long: `Move the mouse cursor to specific screen coordinates.
 
 This command moves the mouse cursor to the specified x and y coordinates on the screen
-without clicking. The coordinates are measured in pixels from the top-left corner of
-the screen (0,0).
+without clicking. The coordinates are measured from the top-left corner of the
+screen (0,0) in the space selected with --coords (logical by default).
 
-Use the --smooth flag for animated movement, and --duration to control the animation speed.`,
+Use the --smooth flag for animated movement, and --duration to control the animation speed.
+The --path flag selects the shape of the movement: a straight line (linear), a
+randomized curve (bezier) or a wandering, human-like path (wind). Combine it with
+--easing, --overshoot and --jitter for more natural movement, and --seed to make the
+randomized trajectory reproducible. Setting --path implies --smooth.
+
+` + targetHelp,
 		Example: `  # Move cursor instantly to coordinates (800, 600)
   desktop-automation move 800 600
 
@@ -33,48 +42,70 @@ Use the --smooth flag for animated movement, and --duration to control the anima
   # Move cursor smoothly to coordinates (800, 600) over 5 seconds
   desktop-automation move --smooth --duration 5.0 800 600
 
+  # Move cursor along a human-like curve that overshoots and corrects
+  desktop-automation move --path bezier --overshoot --jitter 0.5 800 600
+
+  # Reproduce the same randomized trajectory every time
+  desktop-automation move --path wind --seed 42 800 600
+
   # Move cursor to the center of a 1920x1080 screen
   desktop-automation move 960 540
 
   # Move cursor to the top-left corner
-  desktop-automation move 0 0`,
-		Args: cobra.ExactArgs(2),
+  desktop-automation move 0 0
+
+  # Move cursor 100 pixels to the left
+  desktop-automation move -- -100 +0
+
+  # Move cursor to the center of the screen
+  desktop-automation move @center`,
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runMoveCommand(cmd, args, smooth, duration)
+			pathKind, err := automation.ParsePathKind(path)
+			if err != nil {
+				return err
+			}
+			easingKind, err := automation.ParseEasing(easing)
+			if err != nil {
+				return err
+			}
+
+			opts := automation.TrajectoryOptions{
+				Path:      pathKind,
+				Easing:    easingKind,
+				Duration:  duration,
+				Overshoot: overshoot,
+				Jitter:    jitter,
+				Seed:      seed,
+			}
+			smooth = smooth || cmd.Flags().Changed("path")
+			return runMoveCommand(cmd, args, smooth, opts)
 		},
 	}
 
 	// Add flags
 	cmd.Flags().BoolVar(&smooth, "smooth", false, "Enable smooth animated movement")
 	cmd.Flags().Float64Var(&duration, "duration", 1.0, "Duration in seconds for smooth movement (default: 1.0)")
+	cmd.Flags().StringVar(&path, "path", "linear", "Trajectory shape for smooth movement: linear, bezier or wind")
+	cmd.Flags().StringVar(&easing, "easing", "in-out", "Velocity profile for smooth movement: in-out, out or none")
+	cmd.Flags().BoolVar(&overshoot, "overshoot", false, "Overshoot the target slightly and correct back")
+	cmd.Flags().Float64Var(&jitter, "jitter", 0, "Standard deviation in pixels of random noise added to each step")
+	cmd.Flags().Int64Var(&seed, "seed", 0, "Seed for the randomized trajectory (default: random)")
 
 	return cmd
 }
 
 // runMoveCommand handles the move command execution
-func runMoveCommand(cmd *cobra.Command, args []string, smooth bool, duration float64) error {
-	// Parse X coordinate
-	x, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("invalid x coordinate '%s': must be a valid integer", args[0])
-	}
-
-	// Parse Y coordinate
-	y, err := strconv.Atoi(args[1])
+func runMoveCommand(cmd *cobra.Command, args []string, smooth bool, opts automation.TrajectoryOptions) error {
+	// Parse and resolve the target to logical coordinates
+	targetX, targetY, cs, err := resolveTarget(cmd.Context(), args)
 	if err != nil {
-		return fmt.Errorf("invalid y coordinate '%s': must be a valid integer", args[1])
-	}
-
-	// Validate coordinates are not negative
-	if x < 0 {
-		return fmt.Errorf("x coordinate cannot be negative: %d", x)
-	}
-	if y < 0 {
-		return fmt.Errorf("y coordinate cannot be negative: %d", y)
+		return err
 	}
+	x, y := cs.FromLogical(targetX, targetY)
 
 	// Get current mouse position
-	currentX, currentY := automation.GetPosition()
+	currentX, currentY := cs.FromLogical(automation.GetPosition())
 	fmt.Printf("Current position: (%d, %d)\n", currentX, currentY)
 	fmt.Printf("Target position: (%d, %d)\n", x, y)
 
@@ -86,7 +117,7 @@ func runMoveCommand(cmd *cobra.Command, args []string, smooth bool, duration flo
 
 	// Perform the movement
 	if smooth {
-		fmt.Printf("Moving smoothly over %.1f seconds...\n", duration)
+		fmt.Printf("Moving smoothly over %.1f seconds...\n", opts.Duration)
 
 		// Start a goroutine to show progress
 		done := make(chan bool)
@@ -105,7 +136,7 @@ func runMoveCommand(cmd *cobra.Command, args []string, smooth bool, duration flo
 		}()
 
 		// Perform smooth movement
-		err = automation.SmoothMove(x, y, duration)
+		err = automation.MoveAlongPath(cmd.Context(), targetX, targetY, opts)
 		close(done)
 		fmt.Println() // New line after dots
 
@@ -114,19 +145,21 @@ func runMoveCommand(cmd *cobra.Command, args []string, smooth bool, duration flo
 		}
 	} else {
 		fmt.Println("Moving...")
-		err = automation.Move(x, y)
+		err = automation.Move(cmd.Context(), targetX, targetY)
 		if err != nil {
 			return fmt.Errorf("failed to move mouse: %v", err)
 		}
 	}
 
 	// Confirm final position
-	finalX, finalY := automation.GetPosition()
+	finalLogicalX, finalLogicalY := automation.GetPosition()
+	finalX, finalY := cs.FromLogical(finalLogicalX, finalLogicalY)
 	fmt.Printf("Final position: (%d, %d)\n", finalX, finalY)
 
-	// Check if we reached the target (allow small tolerance for smooth movement)
+	// Check if we reached the target (allow small tolerance for smooth movement),
+	// comparing in logical space so rounding between spaces does not count
 	tolerance := 2
-	if abs(finalX-x) <= tolerance && abs(finalY-y) <= tolerance {
+	if abs(finalLogicalX-targetX) <= tolerance && abs(finalLogicalY-targetY) <= tolerance {
 		fmt.Println("✓ Successfully moved to target position!")
 	} else {
 		fmt.Printf("⚠ Position may not be exact (target: %d,%d, actual: %d,%d)\n", x, y, finalX, finalY)
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+	"github.com/spf13/cobra"
+)
+
+// NewPixelCommand creates the pixel command
+func NewPixelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pixel <x> <y> | pixel <target>",
+		Short: "Print the colour of the screen at a coordinate",
+		Long: `Print the colour of the screen at a coordinate.
+
+The colour is printed as #rrggbb followed by its red, green and blue components,
+so scripts can read the hex value with cut -d' ' -f1.
+
+` + targetHelp,
+		Example: `  # Read the colour at (100, 200)
+  desktop-automation pixel 100 200
+
+  # Check a status indicator
+  [ "$(desktop-automation pixel 1880 12 | cut -d' ' -f1)" = "#00ff00" ] && echo online`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runPixelCommand,
+	}
+
+	return cmd
+}
+
+// runPixelCommand handles the pixel command execution
+func runPixelCommand(cmd *cobra.Command, args []string) error {
+	x, y, cs, err := resolveTarget(cmd.Context(), args)
+	if err != nil {
+		return err
+	}
+
+	c, err := automation.PixelAt(cmd.Context(), x, y)
+	if err != nil {
+		px, py := cs.FromLogical(x, y)
+		return fmt.Errorf("failed to read pixel at (%d, %d): %w", px, py, err)
+	}
+
+	fmt.Printf("%s rgb(%d, %d, %d)\n", automation.HexColor(c), c.R, c.G, c.B)
+	return nil
+}
+
+// findColorTarget returns the centroid of the largest blob of a colour in a
+// region given in the --coords space, in logical coordinates
+func findColorTarget(ctx context.Context, cs automation.CoordSystem, colorSpec, regionSpec string, tolerance uint8) (int, int, error) {
+	c, err := automation.ParseColor(colorSpec)
+	if err != nil {
+		return 0, 0, err
+	}
+	region, err := parseRegion(cs, regionSpec)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	blobs, err := automation.FindColor(ctx, region, c, tolerance)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to search for %s: %w", automation.HexColor(c), err)
+	}
+	if len(blobs) == 0 {
+		where := "on the screen"
+		if !region.Empty() {
+			where = "in region " + regionSpec
+		}
+		return 0, 0, fmt.Errorf("no pixels of color %s found %s (tolerance %d)", automation.HexColor(c), where, tolerance)
+	}
+
+	b := blobs[0]
+	if len(blobs) > 1 {
+		fmt.Printf("Found %d areas of %s, using the largest (%d pixels)\n", len(blobs), automation.HexColor(c), b.Pixels)
+	}
+	return b.X, b.Y, nil
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/dmahlow/desktop-automation/internal/recording"
+	"github.com/spf13/cobra"
+)
+
+// NewRecordScreenCommand creates the record-screen command
+func NewRecordScreenCommand() *cobra.Command {
+	var out, region string
+	var opts recording.Options
+
+	cmd := &cobra.Command{
+		Use:   "record-screen --out <file> [flags] [-- command [args...]]",
+		Short: "Record the screen to an animated GIF, APNG or MP4 file",
+		Long: `Record the screen to an animated GIF, APNG or MP4 file.
+
+Frames are captured --fps times per second from the whole screen or the region
+given with --region as X,Y,WIDTH,HEIGHT in the space selected with --coords.
+The format follows the extension of --out: GIF and APNG (.apng or .png) are
+encoded natively and store only the changed part of each frame, MP4 is encoded
+by a local ffmpeg.
+
+The cursor is drawn into the frames unless --cursor=false is given. Clicks are
+shown as ripples when the automation runs through the daemon, which reports
+the clicks of all commands to the recorder, or when recording with
+--backend x11, which watches the pointer buttons of the display and sees clicks
+held for a few milliseconds, such as those made by hand, by xdotool or by
+--backend x11. With other backends --clicks has no effect and a warning is
+printed.
+
+With a command after "--" the recording lasts until the command exits and the
+command's exit status is returned. Otherwise it lasts for --duration or until
+Ctrl+C.`,
+		Example: `  # Record for 10 seconds
+  desktop-automation record-screen --out demo.gif --duration 10s
+
+  # Record a region at 5 fps until Ctrl+C
+  desktop-automation record-screen --out run.apng --fps 5 --region 0,0,1280,720
+
+  # Record a CI run, with clicks routed through the daemon
+  desktop-automation daemon &
+  desktop-automation record-screen --out run.mp4 -- ./e2e.sh`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if out == "" {
+				return fmt.Errorf("--out is required")
+			}
+			cs, err := coordSystem()
+			if err != nil {
+				return err
+			}
+			if opts.Region, err = parseRegion(cs, region); err != nil {
+				return err
+			}
+			return runRecordScreenCommand(cmd, args, out, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&out, "out", "", "Output file: .gif, .apng, .png or .mp4")
+	cmd.Flags().Float64Var(&opts.FPS, "fps", 10, "Frames per second")
+	cmd.Flags().StringVar(&region, "region", "", "Record only X,Y,WIDTH,HEIGHT (default: whole screen)")
+	cmd.Flags().DurationVar(&opts.Duration, "duration", 0, "Stop after this long, e.g. 30s (default: until Ctrl+C)")
+	cmd.Flags().BoolVar(&opts.Cursor, "cursor", true, "Draw the mouse cursor")
+	cmd.Flags().BoolVar(&opts.Clicks, "clicks", true, "Draw ripples where the mouse was clicked")
+
+	return cmd
+}
+
+// runRecordScreenCommand handles the record-screen command execution
+func runRecordScreenCommand(cmd *cobra.Command, args []string, out string, opts recording.Options) error {
+	enc, err := recording.Create(out, opts.FPS)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	// Run the command concurrently and stop recording when it exits
+	exited := make(chan error, 1)
+	if len(args) > 0 {
+		child := exec.Command(args[0], args[1:]...)
+		child.Stdin, child.Stdout, child.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := child.Start(); err != nil {
+			enc.Close()
+			return fmt.Errorf("failed to run %s: %w", args[0], err)
+		}
+		go func() {
+			exited <- child.Wait()
+			cancel()
+		}()
+		fmt.Printf("Recording to %s at %g fps while %s runs\n", out, opts.FPS, strings.Join(args, " "))
+	} else {
+		fmt.Printf("Recording to %s at %g fps (press Ctrl+C to stop)\n", out, opts.FPS)
+	}
+
+	stats, err := recording.Record(ctx, opts, enc)
+	var runErr error
+	if len(args) > 0 {
+		runErr = <-exited
+	}
+	if err != nil {
+		return fmt.Errorf("recording failed: %w", err)
+	}
+	fmt.Printf("✓ Recorded %d frames (%s) to %s\n", stats.Frames, stats.Duration.Round(100*time.Millisecond), out)
+
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	if runErr != nil {
+		return fmt.Errorf("failed to run %s: %w", args[0], runErr)
+	}
+	return nil
+}
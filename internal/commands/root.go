@@ -1,15 +1,141 @@
 package commands
 
 import (
+	"fmt"
+	"io"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+	"github.com/dmahlow/desktop-automation/internal/daemon"
 	"github.com/spf13/cobra"
 )
 
+// Values of the global flags shared by all commands
+var (
+	coordSpace string
+	relativeTo string
+	anchorDefs []string
+	socketPath string
+	noDaemon   bool
+	backend    string
+)
+
+// daemonClient is the connection to a running daemon, nil when commands run
+// against the local backend
+var daemonClient *daemon.Client
+
+// backendCloser closes the backend opened for --backend, if it holds a connection
+var backendCloser io.Closer
+
 // AddCommands adds all subcommands to the root command
 func AddCommands(rootCmd *cobra.Command) {
+	rootCmd.PersistentFlags().StringVar(&coordSpace, "coords", "logical",
+		"Coordinate space for positions: logical, physical or screenshot[:WxH]")
+	rootCmd.PersistentFlags().StringVar(&relativeTo, "relative-to", "screen",
+		"Frame for percentages and anchors in targets: screen or window")
+	rootCmd.PersistentFlags().StringArrayVar(&anchorDefs, "anchor", nil,
+		"Define a named anchor as NAME=X,Y for use as @NAME in targets (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&socketPath, "socket", daemon.DefaultSocketPath(),
+		"Unix socket of the automation daemon")
+	rootCmd.PersistentFlags().BoolVar(&noDaemon, "no-daemon", false,
+		"Do not route commands through a running daemon")
+
+	rootCmd.PersistentFlags().StringVar(&backend, "backend", "",
+		"Automation backend: robotgo, x11[:DISPLAY], uinput[:WxH] or vnc://[:password@]host[:port] (default: local desktop)")
+
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if backend != "" {
+			return openBackend(cmd, args)
+		}
+		return connectDaemon(cmd, args)
+	}
+
 	rootCmd.AddCommand(
 		NewClickCommand(),
 		NewTypeCommand(),
 		NewMoveCommand(),
+		NewKeyCommand(),
 		NewScreenshotCommand(),
+		NewPixelCommand(),
+		NewZoomCommand(),
+		NewTUICommand(),
+		NewA11yCommand(),
+		NewDaemonCommand(),
+		NewServeCommand(),
+		NewViewCommand(),
+		NewSessionCommand(),
+		NewRecordScreenCommand(),
+		NewAssertScreenCommand(),
 	)
 }
+
+// connectDaemon routes all automation through the daemon if one is running.
+// Without a daemon commands silently fall back to the local backend.
+func connectDaemon(cmd *cobra.Command, args []string) error {
+	if noDaemon {
+		return nil
+	}
+
+	client, err := daemon.Dial(socketPath)
+	if err != nil {
+		return nil
+	}
+
+	// Make anchors defined in earlier invocations available to this one
+	anchors, err := client.Anchors(cmd.Context())
+	if err != nil {
+		client.Close()
+		return fmt.Errorf("failed to talk to daemon at %s: %w", socketPath, err)
+	}
+	for name, p := range anchors {
+		if err := automation.SetAnchor(name, p.X, p.Y); err != nil {
+			client.Close()
+			return err
+		}
+	}
+
+	daemonClient = client
+	automation.SetBackend(client)
+	return nil
+}
+
+// openBackend installs the backend selected with --backend
+func openBackend(cmd *cobra.Command, args []string) error {
+	if backend == "" {
+		return nil
+	}
+
+	b, err := automation.OpenBackend(cmd.Context(), backend)
+	if err != nil {
+		return err
+	}
+	if closer, ok := b.(io.Closer); ok {
+		backendCloser = closer
+	}
+	automation.SetBackend(b)
+	return nil
+}
+
+// CloseBackend closes the connection to the daemon or the backend opened for
+// the command. It is called once the command finished, whether it failed or not.
+func CloseBackend() error {
+	if daemonClient != nil {
+		client := daemonClient
+		daemonClient = nil
+		return client.Close()
+	}
+	if backendCloser != nil {
+		closer := backendCloser
+		backendCloser = nil
+		return closer.Close()
+	}
+	return nil
+}
+
+// coordSystem returns the coordinate system selected with the --coords flag
+func coordSystem() (automation.CoordSystem, error) {
+	cs, err := automation.ParseCoordSpace(coordSpace)
+	if err != nil {
+		return automation.CoordSystem{}, fmt.Errorf("invalid --coords value: %w", err)
+	}
+	return cs, nil
+}
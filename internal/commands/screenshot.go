@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"image"
 
 	"github.com/dmahlow/desktop-automation/internal/automation"
 	"github.com/spf13/cobra"
@@ -9,6 +10,8 @@ import (
 
 // NewScreenshotCommand creates the screenshot command
 func NewScreenshotCommand() *cobra.Command {
+	var opts screenshotOptions
+
 	cmd := &cobra.Command{
 		Use:   "screenshot",
 		Short: "Capture a screenshot of the entire screen",
@@ -16,30 +19,101 @@ func NewScreenshotCommand() *cobra.Command {
 
 This command captures the full screen and saves it as a PNG file in the system's
 temporary directory. The path to the saved screenshot is printed to stdout,
-making it easy to use in scripts and automation workflows.`,
+making it easy to use in scripts and automation workflows.
+
+The image is saved in physical pixels. On scaled (HiDPI) displays pass
+--coords physical to click and move when using positions read off it.
+
+--grid draws lines every N units of the --coords space, labelled with their
+coordinates. --marks draws numbered boxes around the interactive elements of
+the accessibility tree, or around elements found by edge detection where the
+tree is not available (--marks-source). The number, centre and description of
+each mark are printed after the path, so a mark can be clicked by its centre.`,
 		Example: `  # Take a screenshot
   desktop-automation screenshot
 
   # Use the screenshot path in a script
   SCREENSHOT_PATH=$(desktop-automation screenshot)
-  echo "Screenshot saved to: $SCREENSHOT_PATH"`,
+  echo "Screenshot saved to: $SCREENSHOT_PATH"
+
+  # Overlay a coordinate grid with lines every 100 pixels
+  desktop-automation screenshot --grid 100
+
+  # Number the buttons, fields and links on the screen
+  desktop-automation screenshot --marks`,
 		Args: cobra.NoArgs,
-		RunE: runScreenshotCommand,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScreenshotCommand(cmd, opts)
+		},
 	}
 
+	cmd.Flags().IntVar(&opts.grid, "grid", 0, "Draw a labelled coordinate grid with lines every N units")
+	cmd.Flags().BoolVar(&opts.marks, "marks", false, "Draw numbered marks on UI elements and print their positions")
+	cmd.Flags().StringVar(&opts.marksSource, "marks-source", "auto", "Where marks come from: auto, a11y or edges")
+
 	return cmd
 }
 
+// screenshotOptions holds the flags of the screenshot command
+type screenshotOptions struct {
+	grid        int
+	marks       bool
+	marksSource string
+}
+
 // runScreenshotCommand handles the screenshot command execution
-func runScreenshotCommand(cmd *cobra.Command, args []string) error {
-	// Capture the screenshot
-	filepath, err := automation.CaptureScreenshot()
+func runScreenshotCommand(cmd *cobra.Command, opts screenshotOptions) error {
+	ctx := cmd.Context()
+	if opts.grid < 0 {
+		return fmt.Errorf("--grid must not be negative")
+	}
+	source, err := automation.ParseMarkSource(opts.marksSource)
+	if err != nil {
+		return err
+	}
+
+	if opts.grid == 0 && !opts.marks {
+		// Capture the screenshot
+		filepath, err := automation.CaptureScreenshot(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to capture screenshot: %w", err)
+		}
+
+		// Print the file path to stdout (for scripting)
+		fmt.Println(filepath)
+		return nil
+	}
+
+	cs, err := coordSystem()
+	if err != nil {
+		return err
+	}
+	captured, err := automation.CaptureImage(ctx, image.Rectangle{})
 	if err != nil {
 		return fmt.Errorf("failed to capture screenshot: %w", err)
 	}
+	img := automation.ToRGBA(captured)
 
-	// Print the file path to stdout (for scripting)
+	var marks []automation.Mark
+	if opts.marks {
+		marks, err = automation.FindMarks(ctx, captured, source)
+		if err != nil {
+			return fmt.Errorf("failed to find marks: %w", err)
+		}
+	}
+
+	w, h := cs.Size()
+	automation.DrawGrid(img, w, h, opts.grid)
+	automation.DrawMarks(img, cs.LogicalWidth, cs.LogicalHeight, marks)
+
+	filepath, err := automation.SaveScreenshot(img)
+	if err != nil {
+		return err
+	}
 	fmt.Println(filepath)
 
+	for _, m := range automation.ConvertMarks(marks, cs) {
+		fmt.Printf("%3d  (%d, %d)  %s\n", m.ID, m.X, m.Y, m.Label)
+	}
 	return nil
 }
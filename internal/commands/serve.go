@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dmahlow/desktop-automation/internal/grpcapi"
+	"github.com/dmahlow/desktop-automation/internal/httpapi"
+	"github.com/dmahlow/desktop-automation/internal/remote"
+	"github.com/spf13/cobra"
+)
+
+// tokenEnv is the environment variable holding the API token
+const tokenEnv = "DESKTOP_AUTOMATION_TOKEN"
+
+// NewServeCommand creates the serve command
+func NewServeCommand() *cobra.Command {
+	var httpAddr, grpcAddr string
+	var token string
+	var printOpenAPI bool
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the automation API over the network",
+		Long: `Serve the automation API over the network.
+
+With --http the REST API is served on the given address. It provides endpoints
+to move the mouse, click, type, press keys, scroll, capture screenshots (as
+image/png) and query the cursor position and screen size. The OpenAPI
+description of all endpoints is served unauthenticated at /openapi.json, or
+printed with --openapi.
+
+With --grpc the gRPC services Mouse, Keyboard, Screen, Window and Input defined
+in api/proto are served on the given address; use the Go package
+github.com/dmahlow/desktop-automation/client to call them. Both APIs can be
+served at once and never interleave input. Coordinates are in the space
+selected with --coords.
+
+Every API request must carry the token as "Authorization: Bearer <token>" (as
+"authorization" metadata for gRPC). The token is read from --token or the
+` + tokenEnv + ` environment variable; if neither is set a random token
+is generated and printed on startup.`,
+		Example: `  # Serve the REST API on port 8080
+  desktop-automation serve --http :8080
+
+  # Use a fixed token
+  DESKTOP_AUTOMATION_TOKEN=secret desktop-automation serve --http 127.0.0.1:8080
+
+  # Click through the API
+  curl -H "Authorization: Bearer secret" -d '{"x": 100, "y": 200}' localhost:8080/v1/click
+
+  # Save a screenshot
+  curl -H "Authorization: Bearer secret" -o screen.png localhost:8080/v1/screenshot
+
+  # Serve the gRPC API for Go test harnesses
+  desktop-automation serve --grpc :50051
+
+  # Print the OpenAPI description
+  desktop-automation serve --openapi`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if token == "" {
+				token = os.Getenv(tokenEnv)
+			}
+			return runServeCommand(cmd, httpAddr, grpcAddr, token, printOpenAPI)
+		},
+	}
+
+	cmd.Flags().StringVar(&httpAddr, "http", "", "Address to serve the REST API on, e.g. :8080")
+	cmd.Flags().StringVar(&grpcAddr, "grpc", "", "Address to serve the gRPC API on, e.g. :50051")
+	cmd.Flags().StringVar(&token, "token", "", "Bearer token required on API requests (default: $"+tokenEnv+" or generated)")
+	cmd.Flags().BoolVar(&printOpenAPI, "openapi", false, "Print the OpenAPI description and exit")
+
+	return cmd
+}
+
+// runServeCommand handles the serve command execution
+func runServeCommand(cmd *cobra.Command, httpAddr, grpcAddr, token string, printOpenAPI bool) error {
+	cs, err := coordSystem()
+	if err != nil {
+		return err
+	}
+	controller := remote.NewController(cs)
+
+	if printOpenAPI {
+		fmt.Println(string(httpapi.NewServer(controller, token).OpenAPI()))
+		return nil
+	}
+
+	if httpAddr == "" && grpcAddr == "" {
+		return fmt.Errorf("no server to run: pass --http, --grpc or both")
+	}
+
+	if token == "" {
+		if token, err = remote.GenerateToken(); err != nil {
+			return err
+		}
+		fmt.Printf("Generated API token: %s\n", token)
+	}
+
+	// Stop all servers as soon as one of them fails
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	errs := make(chan error, 2)
+	running := 0
+
+	if httpAddr != "" {
+		running++
+		fmt.Printf("Serving REST API on %s in %s coordinates\n", httpAddr, cs.Space)
+		go func() {
+			errs <- httpapi.ListenAndServe(ctx, httpAddr, httpapi.NewServer(controller, token))
+		}()
+	}
+	if grpcAddr != "" {
+		running++
+		fmt.Printf("Serving gRPC API on %s in %s coordinates\n", grpcAddr, cs.Space)
+		go func() {
+			errs <- grpcapi.Serve(ctx, grpcAddr, grpcapi.NewServer(controller, token))
+		}()
+	}
+	fmt.Println("Press Ctrl+C to stop")
+
+	var firstErr error
+	for ; running > 0; running-- {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+	if firstErr != nil {
+		return fmt.Errorf("server failed: %w", firstErr)
+	}
+
+	fmt.Println("✓ Server stopped")
+	return nil
+}
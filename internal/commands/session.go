@@ -0,0 +1,246 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dmahlow/desktop-automation/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// NewSessionCommand creates the session command
+func NewSessionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "session",
+		Short: "Manage headless X11 sessions running on Xvfb",
+		Long: `Manage headless X11 sessions running on Xvfb.
+
+A session is an Xvfb server with an optional window manager and startup
+application. Automation pointed at its display (with DISPLAY or --backend
+x11:DISPLAY) runs without a physical screen, which is useful in CI containers.
+Sessions are recorded in ` + session.StateDir() + `
+so they can be listed and stopped from other shells.`,
+		Example: `  # Run a test script in a fresh 1280x800 session and tear it down afterwards
+  desktop-automation session start --resolution 1280x800 -- ./e2e.sh
+
+  # Start a session in the background and use it from later commands
+  desktop-automation session start --name ci --display :99 --wm openbox --app firefox --detach
+  export DISPLAY=:99
+  desktop-automation click 100 200
+  desktop-automation session stop ci`,
+		// Sessions do not use the automation backend or daemon
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	}
+
+	cmd.AddCommand(newSessionStartCommand(), newSessionStopCommand(), newSessionListCommand())
+	return cmd
+}
+
+// newSessionStartCommand creates the session start command
+func newSessionStartCommand() *cobra.Command {
+	var opts session.Options
+	var resolution string
+	var detach bool
+
+	cmd := &cobra.Command{
+		Use:   "start [flags] [-- command [args...]]",
+		Short: "Start Xvfb with an optional window manager and application",
+		Long: `Start Xvfb with an optional window manager and application.
+
+Xvfb is started with the resolution and depth given, on the display given with
+--display or the first free one. Once it accepts connections the window manager
+(--wm) and application (--app) are started on it; both are shell command lines.
+
+With a command after "--" the command runs with DISPLAY set to the session,
+and the session is torn down when it exits; the command's exit status is
+returned. With --detach the session keeps running in the background until
+"session stop". Otherwise the session runs until Ctrl+C.`,
+		Example: `  # Run a command in a session, like xvfb-run
+  desktop-automation session start -- ./e2e.sh
+
+  # Full HD session with a window manager and a browser, in the background
+  desktop-automation session start --resolution 1920x1080 --wm openbox --app "firefox about:blank" --detach
+
+  # Fixed display and name
+  desktop-automation session start --display :42 --name tests --detach`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := fmt.Sscanf(resolution, "%dx%d", &opts.Width, &opts.Height); err != nil {
+				return fmt.Errorf("invalid resolution '%s': must be WIDTHxHEIGHT", resolution)
+			}
+			if detach && len(args) > 0 {
+				return fmt.Errorf("--detach cannot be used with a command")
+			}
+			return runSessionStartCommand(cmd, args, opts, detach)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Session name (default: display number)")
+	cmd.Flags().StringVar(&opts.Display, "display", "", "X display such as :99 (default: first free)")
+	cmd.Flags().StringVar(&resolution, "resolution", "1920x1080", "Screen resolution as WIDTHxHEIGHT")
+	cmd.Flags().IntVar(&opts.Depth, "depth", 24, "Color depth: 8, 16 or 24")
+	cmd.Flags().StringVar(&opts.WindowManager, "wm", "", "Window manager command, e.g. openbox or fluxbox")
+	cmd.Flags().StringVar(&opts.App, "app", "", "Application command to start once the session is up")
+	cmd.Flags().BoolVar(&detach, "detach", false, "Keep the session running in the background")
+
+	return cmd
+}
+
+// runSessionStartCommand handles the session start command execution
+func runSessionStartCommand(cmd *cobra.Command, args []string, opts session.Options, detach bool) error {
+	s, err := session.Start(cmd.Context(), opts)
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	fmt.Printf("✓ Session '%s' started on display %s (%dx%dx%d)\n", s.Name, s.Display, s.Width, s.Height, s.Depth)
+
+	if detach {
+		fmt.Printf("export DISPLAY=%s\n", s.Display)
+		return nil
+	}
+
+	if len(args) > 0 {
+		// The command gets Ctrl+C from the terminal itself and decides when to exit
+		child := exec.Command(args[0], args[1:]...)
+		child.Env = s.Environ()
+		child.Stdin, child.Stdout, child.Stderr = os.Stdin, os.Stdout, os.Stderr
+		runErr := child.Run()
+
+		if err := s.Stop(); err != nil {
+			return fmt.Errorf("failed to stop session: %w", err)
+		}
+		fmt.Printf("✓ Session '%s' stopped\n", s.Name)
+
+		var exitErr *exec.ExitError
+		if errors.As(runErr, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		if runErr != nil {
+			return fmt.Errorf("failed to run %s: %w", args[0], runErr)
+		}
+		return nil
+	}
+
+	fmt.Printf("Session running, DISPLAY=%s (press Ctrl+C to stop)\n", s.Display)
+	select {
+	case <-cmd.Context().Done():
+	case <-s.Done():
+		fmt.Printf("Xvfb exited, see %s\n", s.LogFile)
+	}
+
+	if err := s.Stop(); err != nil {
+		return fmt.Errorf("failed to stop session: %w", err)
+	}
+	fmt.Printf("✓ Session '%s' stopped\n", s.Name)
+	return nil
+}
+
+// newSessionStopCommand creates the session stop command
+func newSessionStopCommand() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "stop [name|display...]",
+		Short: "Stop sessions and the processes started in them",
+		Long: `Stop sessions and the processes started in them.
+
+The application, window manager and Xvfb are sent SIGTERM in that order and
+killed if they do not exit within a few seconds. Sessions are selected by name
+or display; without arguments the only running session is stopped.`,
+		Example: `  # Stop a session by name or display
+  desktop-automation session stop ci
+  desktop-automation session stop :99
+
+  # Stop all sessions
+  desktop-automation session stop --all`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSessionStopCommand(args, all)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Stop all sessions")
+
+	return cmd
+}
+
+// runSessionStopCommand handles the session stop command execution
+func runSessionStopCommand(args []string, all bool) error {
+	var sessions []*session.Session
+	switch {
+	case all || len(args) == 0:
+		list, err := session.List()
+		if err != nil {
+			return fmt.Errorf("failed to list sessions: %w", err)
+		}
+		if !all && len(list) > 1 {
+			return fmt.Errorf("%d sessions are running: name one or pass --all", len(list))
+		}
+		sessions = list
+	default:
+		for _, name := range args {
+			s, err := session.Load(name)
+			if err != nil {
+				return err
+			}
+			sessions = append(sessions, s)
+		}
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No sessions running")
+		return nil
+	}
+	for _, s := range sessions {
+		fmt.Printf("Stopping session '%s' on display %s\n", s.Name, s.Display)
+		if err := s.Stop(); err != nil {
+			return fmt.Errorf("failed to stop session '%s': %w", s.Name, err)
+		}
+		fmt.Printf("✓ Session '%s' stopped\n", s.Name)
+	}
+	return nil
+}
+
+// newSessionListCommand creates the session list command
+func newSessionListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List sessions",
+		Long: `List sessions with their display, resolution and status.
+
+A session whose Xvfb server has exited is listed as "exited" until it is
+removed with "session stop".`,
+		Example: `  desktop-automation session list`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSessionListCommand()
+		},
+	}
+}
+
+// runSessionListCommand handles the session list command execution
+func runSessionListCommand() error {
+	sessions, err := session.List()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		fmt.Println("No sessions running")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDISPLAY\tSCREEN\tSTATUS\tSTARTED")
+	for _, s := range sessions {
+		status := "running"
+		if !s.Running() {
+			status = "exited"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%dx%dx%d\t%s\t%s\n",
+			s.Name, s.Display, s.Width, s.Height, s.Depth, status, s.Started.Format(time.DateTime))
+	}
+	return w.Flush()
+}
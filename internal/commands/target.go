@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+)
+
+// targetHelp documents the target syntax shared by commands taking coordinates
+const targetHelp = `Coordinates can be given as <x> <y> or as a single <x>,<y> argument, and each
+coordinate may be absolute (100), relative to the cursor (+50, -20) or a
+percentage of the screen or, with --relative-to window, the active window (50%).
+A single @anchor argument such as @center, @top-left, @bottom-right or @cursor,
+or an anchor defined with --anchor (kept by the daemon while it runs), is also
+accepted. Use -- before arguments starting with a minus sign so they are not read
+as flags.`
+
+// resolveTarget parses a target from command arguments and resolves it to
+// logical screen coordinates using the global --coords, --relative-to and
+// --anchor flags
+func resolveTarget(ctx context.Context, args []string) (x, y int, cs automation.CoordSystem, err error) {
+	cs, err = coordSystem()
+	if err != nil {
+		return 0, 0, cs, err
+	}
+
+	frame, err := automation.ParseFrame(relativeTo)
+	if err != nil {
+		return 0, 0, cs, fmt.Errorf("invalid --relative-to value: %w", err)
+	}
+
+	for _, def := range anchorDefs {
+		if err := defineAnchor(ctx, cs, def); err != nil {
+			return 0, 0, cs, err
+		}
+	}
+
+	target, err := automation.ParseTarget(args...)
+	if err != nil {
+		return 0, 0, cs, err
+	}
+
+	x, y, err = target.Resolve(cs, frame)
+	if err != nil {
+		return 0, 0, cs, err
+	}
+
+	// Validate coordinates are non-negative
+	if x < 0 {
+		return 0, 0, cs, fmt.Errorf("x coordinate cannot be negative: %d", x)
+	}
+	if y < 0 {
+		return 0, 0, cs, fmt.Errorf("y coordinate cannot be negative: %d", y)
+	}
+
+	return x, y, cs, nil
+}
+
+// defineAnchor registers an anchor from a --anchor NAME=X,Y value given in the
+// coordinate space cs, storing it in the daemon as well when one is running
+func defineAnchor(ctx context.Context, cs automation.CoordSystem, def string) error {
+	name, pos, ok := strings.Cut(def, "=")
+	if !ok {
+		return fmt.Errorf("invalid --anchor value '%s': expected NAME=X,Y", def)
+	}
+
+	target, err := automation.ParseTarget(pos)
+	if err != nil {
+		return fmt.Errorf("invalid --anchor value '%s': %w", def, err)
+	}
+	x, y, err := target.Resolve(cs, automation.FrameScreen)
+	if err != nil {
+		return fmt.Errorf("invalid --anchor value '%s': %w", def, err)
+	}
+
+	if err := automation.SetAnchor(name, x, y); err != nil {
+		return err
+	}
+	if daemonClient != nil {
+		return daemonClient.SetAnchor(ctx, name, x, y)
+	}
+	return nil
+}
+
+// parseRegion parses a region given as X,Y,WIDTH,HEIGHT in the space selected
+// with --coords and returns it in logical coordinates. An empty string is the
+// empty rectangle, which stands for the whole screen.
+func parseRegion(cs automation.CoordSystem, s string) (image.Rectangle, error) {
+	if s == "" {
+		return image.Rectangle{}, nil
+	}
+
+	var x, y, w, h int
+	if _, err := fmt.Sscanf(s, "%d,%d,%d,%d", &x, &y, &w, &h); err != nil {
+		return image.Rectangle{}, fmt.Errorf("invalid region '%s': must be X,Y,WIDTH,HEIGHT", s)
+	}
+	if x < 0 || y < 0 || w <= 0 || h <= 0 {
+		return image.Rectangle{}, fmt.Errorf("invalid region '%s': position must not be negative and size must be positive", s)
+	}
+
+	x0, y0 := cs.ToLogical(x, y)
+	x1, y1 := cs.ToLogical(x+w, y+h)
+	return image.Rect(x0, y0, x1, y1), nil
+}
@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"github.com/dmahlow/desktop-automation/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewTUICommand creates the tui command
+func NewTUICommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Drive the mouse and keyboard from an interactive terminal UI",
+		Long: `Drive the mouse and keyboard from an interactive terminal UI.
+
+Pick an action from the menu and enter its arguments: move, click, type text,
+press a key combination, scroll or take a screenshot. A status bar shows the
+live cursor position, the display and the result of the last action.
+
+Positions are entered and shown in the space selected with --coords. Typing and
+key presses start after a short delay, so the target window can be focused
+instead of the terminal running the UI.`,
+		Example: `  # Open the terminal UI
+  desktop-automation tui
+
+  # Enter and show positions in physical pixels
+  desktop-automation --coords physical tui`,
+		Args: cobra.NoArgs,
+		RunE: runTUICommand,
+	}
+
+	return cmd
+}
+
+// runTUICommand handles the tui command execution
+func runTUICommand(cmd *cobra.Command, args []string) error {
+	cs, err := coordSystem()
+	if err != nil {
+		return err
+	}
+	return ui.StartTUI(cmd.Context(), cs)
+}
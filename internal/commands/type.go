@@ -11,6 +11,9 @@ import (
 // NewTypeCommand creates the type command
 func NewTypeCommand() *cobra.Command {
 	var delayMs int
+	var profileName string
+	var wpm float64
+	var seed int64
 
 	cmd := &cobra.Command{
 		Use:   "type <text>",
@@ -20,7 +23,12 @@ func NewTypeCommand() *cobra.Command {
 This command simulates keyboard input by typing the specified text at the current
 cursor location. The text will be typed as if you were physically typing on the keyboard.
 
-Use quotes to handle multi-word text or text containing special characters.`,
+Use quotes to handle multi-word text or text containing special characters.
+
+Use --profile and --wpm to type with a human-like cadence instead of a fixed delay:
+per-key-pair timing, pauses after words and punctuation, bursts and random jitter
+around the target speed. Available profiles: ` + strings.Join(automation.TypingProfiles(), ", ") + `.
+Pass --seed to make the timing reproducible.`,
 		Example: `  # Type a simple message
   desktop-automation type "Hello, World!"
 
@@ -30,6 +38,9 @@ Use quotes to handle multi-word text or text containing special characters.`,
   # Type with delay between characters (50ms)
   desktop-automation type --delay=50 "Slow typing!"
 
+  # Type like a person at about 60 words per minute
+  desktop-automation type --wpm 60 --profile natural "Hello from a human"
+
   # Type special characters
   desktop-automation type "user@example.com"
 
@@ -37,18 +48,41 @@ Use quotes to handle multi-word text or text containing special characters.`,
   desktop-automation type "Password123!"`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runTypeCommand(cmd, args, delayMs)
+			if !cmd.Flags().Changed("profile") && !cmd.Flags().Changed("wpm") {
+				return runTypeCommand(cmd, args, delayMs, nil)
+			}
+			if delayMs > 0 {
+				return fmt.Errorf("--delay cannot be combined with --profile or --wpm")
+			}
+
+			profile, err := automation.TypingProfileByName(profileName)
+			if err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("wpm") {
+				if wpm <= 0 {
+					return fmt.Errorf("wpm must be positive: %.1f", wpm)
+				}
+				profile.WPM = wpm
+			}
+			profile.Seed = seed
+			return runTypeCommand(cmd, args, 0, &profile)
 		},
 	}
 
 	// Add delay flag
 	cmd.Flags().IntVar(&delayMs, "delay", 0, "Delay in milliseconds between each character (default: 0)")
 
+	// Add typing profile flags
+	cmd.Flags().StringVar(&profileName, "profile", "natural", "Typing cadence profile: "+strings.Join(automation.TypingProfiles(), ", "))
+	cmd.Flags().Float64Var(&wpm, "wpm", 60, "Target typing speed in words per minute (overrides the profile's speed)")
+	cmd.Flags().Int64Var(&seed, "seed", 0, "Seed for reproducible typing cadence (default: random)")
+
 	return cmd
 }
 
 // runTypeCommand handles the type command execution
-func runTypeCommand(cmd *cobra.Command, args []string, delayMs int) error {
+func runTypeCommand(cmd *cobra.Command, args []string, delayMs int, profile *automation.TypingProfile) error {
 	text := args[0]
 
 	// Validate that text is not empty or only whitespace
@@ -61,14 +95,19 @@ func runTypeCommand(cmd *cobra.Command, args []string, delayMs int) error {
 	if delayMs > 0 {
 		fmt.Printf(" (with %dms delay between characters)", delayMs)
 	}
+	if profile != nil {
+		fmt.Printf(" (with %s profile at %.0f WPM)", profile.Name, profile.WPM)
+	}
 	fmt.Println()
 
-	// Use appropriate typing function based on delay
+	// Use appropriate typing function based on delay or profile
 	var err error
-	if delayMs > 0 {
-		err = automation.TypeStringWithDelay(text, delayMs)
+	if profile != nil {
+		err = automation.TypeWithProfile(cmd.Context(), text, *profile)
+	} else if delayMs > 0 {
+		err = automation.TypeStringWithDelay(cmd.Context(), text, delayMs)
 	} else {
-		err = automation.TypeString(text)
+		err = automation.TypeString(cmd.Context(), text)
 	}
 
 	if err != nil {
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/dmahlow/desktop-automation/internal/remote"
+	"github.com/dmahlow/desktop-automation/internal/viewer"
+	"github.com/spf13/cobra"
+)
+
+// NewViewCommand creates the view command
+func NewViewCommand() *cobra.Command {
+	var listen string
+	var opts viewer.Options
+
+	cmd := &cobra.Command{
+		Use:   "view",
+		Short: "Stream the screen to a browser and accept remote input",
+		Long: `Stream the screen to a browser and accept remote input.
+
+This command serves a minimal web page on the address given with --listen that
+shows the screen live. Frames are streamed over a WebSocket as JPEG images, and
+after the first frame only the rectangles that changed are sent. Tick "Control"
+on the page to send mouse and keyboard input back to this machine, or pass
+--view-only to ignore input.
+
+The page URL includes a token that is required to connect. It is read from
+--token or the ` + tokenEnv + ` environment variable; if neither is set
+a random token is generated. The stream is not encrypted, so only use it on
+trusted networks or behind a TLS proxy.`,
+		Example: `  # Watch the screen on port 9000
+  desktop-automation view --listen :9000
+
+  # Lower frame rate and quality for slow links
+  desktop-automation view --listen :9000 --fps 2 --quality 50
+
+  # Watch without allowing input
+  desktop-automation view --listen :9000 --view-only`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Token == "" {
+				opts.Token = os.Getenv(tokenEnv)
+			}
+			return runViewCommand(cmd, listen, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", ":9000", "Address to serve the viewer on")
+	cmd.Flags().Float64Var(&opts.FPS, "fps", 5, "Maximum frames per second")
+	cmd.Flags().IntVar(&opts.Quality, "quality", 70, "JPEG quality from 1 to 100")
+	cmd.Flags().BoolVar(&opts.ViewOnly, "view-only", false, "Ignore mouse and keyboard input from viewers")
+	cmd.Flags().StringVar(&opts.Token, "token", "", "Token required to connect (default: $"+tokenEnv+" or generated)")
+
+	return cmd
+}
+
+// runViewCommand handles the view command execution
+func runViewCommand(cmd *cobra.Command, listen string, opts viewer.Options) error {
+	if opts.Token == "" {
+		token, err := remote.GenerateToken()
+		if err != nil {
+			return err
+		}
+		opts.Token = token
+	}
+
+	server, err := viewer.NewServer(opts)
+	if err != nil {
+		return err
+	}
+
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return fmt.Errorf("invalid --listen address '%s': %w", listen, err)
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	fmt.Printf("Viewer running at http://%s/?token=%s (press Ctrl+C to stop)\n", net.JoinHostPort(host, port), opts.Token)
+
+	if err := viewer.ListenAndServe(cmd.Context(), listen, server); err != nil {
+		return fmt.Errorf("viewer failed: %w", err)
+	}
+
+	fmt.Println("✓ Viewer stopped")
+	return nil
+}
@@ -0,0 +1,182 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"math/rand"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"time"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+)
+
+// Client talks to a running daemon. It implements automation.Backend so it can
+// be installed with automation.SetBackend to route all automation through the daemon.
+type Client struct {
+	rpc *rpc.Client
+}
+
+var (
+	_ automation.Backend       = (*Client)(nil)
+	_ automation.ClickObserver = (*Client)(nil)
+)
+
+// Dial connects to the daemon listening on the Unix socket at path
+func Dial(path string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to daemon at %s: %w", path, err)
+	}
+	return &Client{rpc: jsonrpc.NewClient(conn)}, nil
+}
+
+// Close closes the connection to the daemon
+func (c *Client) Close() error {
+	return c.rpc.Close()
+}
+
+// call invokes a method of the automation service, giving up when ctx is done.
+// Arguments embedding Call pass on the deadline of ctx, and the daemon is told
+// to cancel them when the client gives up.
+func (c *Client) call(ctx context.Context, method string, args, reply any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var id uint64
+	if a, ok := args.(interface{ setCall(Call) }); ok {
+		// IDs are random, so calls of different clients do not collide
+		id = rand.Uint64() | 1
+		deadline, _ := ctx.Deadline()
+		a.setCall(Call{ID: id, Deadline: deadline})
+	}
+
+	call := c.rpc.Go(ServiceName+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-ctx.Done():
+		if id != 0 {
+			// The request is written before Go returns, so it reaches the
+			// daemon even if the process exits right away
+			c.rpc.Go(ServiceName+".Cancel", CancelArgs{ID: id}, &Empty{}, make(chan *rpc.Call, 1))
+		}
+		return ctx.Err()
+	case <-call.Done:
+		if call.Error != nil {
+			return fmt.Errorf("daemon: %w", call.Error)
+		}
+		return nil
+	}
+}
+
+func (c *Client) MoveMouse(ctx context.Context, x, y int) error {
+	return c.call(ctx, "MoveMouse", &PositionArgs{PointArgs: PointArgs{X: x, Y: y}}, &Empty{})
+}
+
+func (c *Client) MouseToggle(ctx context.Context, button string, down bool) error {
+	return c.call(ctx, "MouseToggle", &MouseToggleArgs{Button: button, Down: down}, &Empty{})
+}
+
+func (c *Client) Click(ctx context.Context, button string, double bool) error {
+	return c.call(ctx, "Click", &ClickArgs{Button: button, Double: double}, &Empty{})
+}
+
+func (c *Client) Scroll(ctx context.Context, dx, dy int) error {
+	return c.call(ctx, "Scroll", &PositionArgs{PointArgs: PointArgs{X: dx, Y: dy}}, &Empty{})
+}
+
+func (c *Client) KeyTap(ctx context.Context, key string, modifiers ...string) error {
+	return c.call(ctx, "KeyTap", &KeyTapArgs{Key: key, Modifiers: modifiers}, &Empty{})
+}
+
+func (c *Client) KeyToggle(ctx context.Context, key string, down bool) error {
+	return c.call(ctx, "KeyToggle", &KeyToggleArgs{Key: key, Down: down}, &Empty{})
+}
+
+func (c *Client) TypeString(ctx context.Context, text string) error {
+	return c.call(ctx, "TypeString", &TextArgs{Text: text}, &Empty{})
+}
+
+func (c *Client) MousePosition(ctx context.Context) (int, int, error) {
+	var reply PointArgs
+	err := c.call(ctx, "MousePosition", &Call{}, &reply)
+	return reply.X, reply.Y, err
+}
+
+func (c *Client) ScreenSize(ctx context.Context) (int, int, error) {
+	var reply SizeReply
+	err := c.call(ctx, "ScreenSize", &Call{}, &reply)
+	return reply.Width, reply.Height, err
+}
+
+func (c *Client) ScaleFactor(ctx context.Context) (float64, error) {
+	var reply ScaleReply
+	err := c.call(ctx, "ScaleFactor", &Call{}, &reply)
+	return reply.Scale, err
+}
+
+func (c *Client) CaptureScreen(ctx context.Context, region image.Rectangle) (image.Image, error) {
+	var reply ImageReply
+	args := &CaptureArgs{RectArgs: RectArgs{X: region.Min.X, Y: region.Min.Y, Width: region.Dx(), Height: region.Dy()}}
+	if err := c.call(ctx, "CaptureScreen", args, &reply); err != nil {
+		return nil, err
+	}
+
+	img, err := png.Decode(bytes.NewReader(reply.PNG))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot from daemon: %w", err)
+	}
+	return img, nil
+}
+
+func (c *Client) ActiveWindowBounds(ctx context.Context) (image.Rectangle, error) {
+	var reply RectArgs
+	if err := c.call(ctx, "ActiveWindowBounds", &Call{}, &reply); err != nil {
+		return image.Rectangle{}, err
+	}
+	return image.Rect(reply.X, reply.Y, reply.X+reply.Width, reply.Y+reply.Height), nil
+}
+
+// SetAnchor stores a named anchor in the daemon
+func (c *Client) SetAnchor(ctx context.Context, name string, x, y int) error {
+	return c.call(ctx, "SetAnchor", AnchorArgs{Name: name, X: x, Y: y}, &Empty{})
+}
+
+// Anchors returns the anchors stored in the daemon
+func (c *Client) Anchors(ctx context.Context) (map[string]image.Point, error) {
+	var reply AnchorsReply
+	if err := c.call(ctx, "Anchors", Empty{}, &reply); err != nil {
+		return nil, err
+	}
+
+	anchors := make(map[string]image.Point, len(reply.Anchors))
+	for name, p := range reply.Anchors {
+		anchors[name] = image.Pt(p.X, p.Y)
+	}
+	return anchors, nil
+}
+
+// HeldKeys returns the keys currently held down through the daemon
+func (c *Client) HeldKeys(ctx context.Context) ([]string, error) {
+	var reply KeysReply
+	err := c.call(ctx, "HeldKeys", Empty{}, &reply)
+	return reply.Keys, err
+}
+
+// ClicksSince returns the clicks made through the daemon after seq
+func (c *Client) ClicksSince(ctx context.Context, seq uint64) ([]automation.ClickEvent, uint64, error) {
+	var reply ClicksReply
+	if err := c.call(ctx, "ClicksSince", ClicksArgs{Seq: seq}, &reply); err != nil {
+		return nil, seq, err
+	}
+
+	clicks := make([]automation.ClickEvent, len(reply.Clicks))
+	for i, e := range reply.Clicks {
+		clicks[i] = automation.ClickEvent{Seq: e.Seq, X: e.X, Y: e.Y, Button: e.Button, Time: e.Time}
+	}
+	return clicks, reply.Seq, nil
+}
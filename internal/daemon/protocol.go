@@ -0,0 +1,162 @@
+// Package daemon implements a long-running process that owns the automation
+// backend and serves it over a JSON-RPC API on a Unix socket, so short-lived CLI
+// invocations can share state such as held keys and anchors.
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ServiceName is the name the automation service is registered under; methods
+// are called as "Automation.<Method>"
+const ServiceName = "Automation"
+
+// DefaultSocketPath returns the socket path used when none is configured:
+// $XDG_RUNTIME_DIR/desktop-automation.sock, or a per-user path in the
+// temporary directory
+func DefaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "desktop-automation.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("desktop-automation-%d.sock", os.Getuid()))
+}
+
+// Empty is used for methods without arguments or results
+type Empty struct{}
+
+// Call identifies a backend call and carries its deadline, so the daemon stops
+// working on it once the client gives up. It is embedded in the arguments of
+// all methods that drive the backend; methods without other arguments take it
+// on its own.
+type Call struct {
+	// ID names the call for Automation.Cancel; 0 if it is never cancelled
+	ID uint64 `json:"call_id,omitempty"`
+	// Deadline is when the client gives up on the call; zero for none
+	Deadline time.Time `json:"deadline"`
+}
+
+// setCall sets the call header of arguments embedding Call
+func (c *Call) setCall(call Call) {
+	*c = call
+}
+
+// CancelArgs are the arguments of Automation.Cancel
+type CancelArgs struct {
+	ID uint64 `json:"call_id"`
+}
+
+// PointArgs holds a pair of coordinates
+type PointArgs struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// PositionArgs are the arguments of Automation.MoveMouse and Automation.Scroll
+type PositionArgs struct {
+	Call
+	PointArgs
+}
+
+// MouseToggleArgs are the arguments of Automation.MouseToggle
+type MouseToggleArgs struct {
+	Call
+	Button string `json:"button"`
+	Down   bool   `json:"down"`
+}
+
+// ClickArgs are the arguments of Automation.Click
+type ClickArgs struct {
+	Call
+	Button string `json:"button"`
+	Double bool   `json:"double"`
+}
+
+// KeyTapArgs are the arguments of Automation.KeyTap
+type KeyTapArgs struct {
+	Call
+	Key       string   `json:"key"`
+	Modifiers []string `json:"modifiers,omitempty"`
+}
+
+// KeyToggleArgs are the arguments of Automation.KeyToggle
+type KeyToggleArgs struct {
+	Call
+	Key  string `json:"key"`
+	Down bool   `json:"down"`
+}
+
+// TextArgs are the arguments of Automation.TypeString
+type TextArgs struct {
+	Call
+	Text string `json:"text"`
+}
+
+// SizeReply is the result of Automation.ScreenSize
+type SizeReply struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ScaleReply is the result of Automation.ScaleFactor
+type ScaleReply struct {
+	Scale float64 `json:"scale"`
+}
+
+// RectArgs holds a rectangle in logical coordinates; an empty one means the full screen
+type RectArgs struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// CaptureArgs are the arguments of Automation.CaptureScreen
+type CaptureArgs struct {
+	Call
+	RectArgs
+}
+
+// ImageReply holds a PNG encoded image
+type ImageReply struct {
+	PNG []byte `json:"png"`
+}
+
+// AnchorArgs are the arguments of Automation.SetAnchor
+type AnchorArgs struct {
+	Name string `json:"name"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+}
+
+// AnchorsReply is the result of Automation.Anchors
+type AnchorsReply struct {
+	Anchors map[string]PointArgs `json:"anchors"`
+}
+
+// KeysReply is the result of Automation.HeldKeys
+type KeysReply struct {
+	Keys []string `json:"keys"`
+}
+
+// ClicksArgs are the arguments of Automation.ClicksSince
+type ClicksArgs struct {
+	Seq uint64 `json:"seq"`
+}
+
+// ClickEvent is a mouse button press made through the daemon
+type ClickEvent struct {
+	Seq    uint64    `json:"seq"`
+	X      int       `json:"x"`
+	Y      int       `json:"y"`
+	Button string    `json:"button"`
+	Time   time.Time `json:"time"`
+}
+
+// ClicksReply is the result of Automation.ClicksSince
+type ClicksReply struct {
+	Clicks []ClickEvent `json:"clicks"`
+	Seq    uint64       `json:"seq"`
+}
@@ -0,0 +1,425 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+)
+
+// Service exposes an automation backend over net/rpc. Its methods mirror
+// automation.Backend and add the state shared between clients.
+type Service struct {
+	// backendMu serializes backend calls so input from concurrent clients
+	// never interleaves within a primitive
+	backendMu sync.Mutex
+	backend   automation.Backend
+
+	mu       sync.Mutex
+	held     map[string]bool
+	anchors  map[string]PointArgs
+	clicks   []ClickEvent
+	clickSeq uint64
+	// calls cancels the running calls by ID; cancelled holds the IDs of
+	// calls cancelled before they started
+	calls     map[uint64]context.CancelFunc
+	cancelled map[uint64]bool
+}
+
+const (
+	// maxClicks is the number of recent clicks kept for ClicksSince
+	maxClicks = 100
+	// maxCancelled bounds the cancellations kept for calls that have not
+	// started, which also collects those of calls that already returned
+	maxCancelled = 100
+)
+
+// NewService creates a service driving the given backend
+func NewService(backend automation.Backend) *Service {
+	return &Service{
+		backend:   backend,
+		held:      make(map[string]bool),
+		anchors:   make(map[string]PointArgs),
+		calls:     make(map[uint64]context.CancelFunc),
+		cancelled: make(map[uint64]bool),
+	}
+}
+
+// begin returns the context of a backend call, which is done at the call's
+// deadline or when the client cancels it, and a function that ends the call
+func (s *Service) begin(call Call) (context.Context, func()) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if call.Deadline.IsZero() {
+		ctx, cancel = context.WithCancel(context.Background())
+	} else {
+		ctx, cancel = context.WithDeadline(context.Background(), call.Deadline)
+	}
+	if call.ID == 0 {
+		return ctx, cancel
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancelled[call.ID] {
+		delete(s.cancelled, call.ID)
+		cancel()
+	}
+	s.calls[call.ID] = cancel
+	return ctx, func() {
+		s.mu.Lock()
+		delete(s.calls, call.ID)
+		s.mu.Unlock()
+		cancel()
+	}
+}
+
+// Cancel stops a running backend call, which then fails with the context's error
+func (s *Service) Cancel(args CancelArgs, reply *Empty) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.calls[args.ID]; ok {
+		cancel()
+		return nil
+	}
+	// The cancellation may overtake the call it belongs to
+	if len(s.cancelled) >= maxCancelled {
+		clear(s.cancelled)
+	}
+	s.cancelled[args.ID] = true
+	return nil
+}
+
+// MoveMouse moves the cursor to the given coordinates
+func (s *Service) MoveMouse(args PositionArgs, reply *Empty) error {
+	ctx, end := s.begin(args.Call)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	return s.backend.MoveMouse(ctx, args.X, args.Y)
+}
+
+// MouseToggle presses or releases a mouse button
+func (s *Service) MouseToggle(args MouseToggleArgs, reply *Empty) error {
+	ctx, end := s.begin(args.Call)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	if err := s.backend.MouseToggle(ctx, args.Button, args.Down); err != nil {
+		return err
+	}
+	if args.Down {
+		s.recordClick(ctx, args.Button)
+	}
+	return nil
+}
+
+// Click clicks a mouse button at the current position
+func (s *Service) Click(args ClickArgs, reply *Empty) error {
+	ctx, end := s.begin(args.Call)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	if err := s.backend.Click(ctx, args.Button, args.Double); err != nil {
+		return err
+	}
+	s.recordClick(ctx, args.Button)
+	return nil
+}
+
+// recordClick remembers a button press at the cursor position for ClicksSince.
+// The caller must hold backendMu.
+func (s *Service) recordClick(ctx context.Context, button string) {
+	x, y, err := s.backend.MousePosition(ctx)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clickSeq++
+	s.clicks = append(s.clicks, ClickEvent{Seq: s.clickSeq, X: x, Y: y, Button: button, Time: time.Now()})
+	if len(s.clicks) > maxClicks {
+		s.clicks = s.clicks[len(s.clicks)-maxClicks:]
+	}
+}
+
+// ClicksSince returns the recent clicks numbered after args.Seq, so screen
+// recorders can show clicks made by other clients
+func (s *Service) ClicksSince(args ClicksArgs, reply *ClicksReply) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reply.Clicks = []ClickEvent{}
+	for _, c := range s.clicks {
+		if c.Seq > args.Seq {
+			reply.Clicks = append(reply.Clicks, c)
+		}
+	}
+	reply.Seq = s.clickSeq
+	return nil
+}
+
+// Scroll scrolls the mouse wheel
+func (s *Service) Scroll(args PositionArgs, reply *Empty) error {
+	ctx, end := s.begin(args.Call)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	return s.backend.Scroll(ctx, args.X, args.Y)
+}
+
+// KeyTap presses and releases a key with modifiers
+func (s *Service) KeyTap(args KeyTapArgs, reply *Empty) error {
+	ctx, end := s.begin(args.Call)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	return s.backend.KeyTap(ctx, args.Key, args.Modifiers...)
+}
+
+// KeyToggle presses or releases a key and keeps track of held keys
+func (s *Service) KeyToggle(args KeyToggleArgs, reply *Empty) error {
+	ctx, end := s.begin(args.Call)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	if err := s.backend.KeyToggle(ctx, args.Key, args.Down); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if args.Down {
+		s.held[args.Key] = true
+	} else {
+		delete(s.held, args.Key)
+	}
+	return nil
+}
+
+// TypeString types text
+func (s *Service) TypeString(args TextArgs, reply *Empty) error {
+	ctx, end := s.begin(args.Call)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	return s.backend.TypeString(ctx, args.Text)
+}
+
+// MousePosition returns the cursor position
+func (s *Service) MousePosition(args Call, reply *PointArgs) error {
+	ctx, end := s.begin(args)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	x, y, err := s.backend.MousePosition(ctx)
+	*reply = PointArgs{X: x, Y: y}
+	return err
+}
+
+// ScreenSize returns the size of the main display
+func (s *Service) ScreenSize(args Call, reply *SizeReply) error {
+	ctx, end := s.begin(args)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	w, h, err := s.backend.ScreenSize(ctx)
+	*reply = SizeReply{Width: w, Height: h}
+	return err
+}
+
+// ScaleFactor returns the number of physical pixels per logical unit
+func (s *Service) ScaleFactor(args Call, reply *ScaleReply) error {
+	ctx, end := s.begin(args)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	scale, err := s.backend.ScaleFactor(ctx)
+	reply.Scale = scale
+	return err
+}
+
+// CaptureScreen captures a region of the screen as PNG
+func (s *Service) CaptureScreen(args CaptureArgs, reply *ImageReply) error {
+	ctx, end := s.begin(args.Call)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	region := image.Rect(args.X, args.Y, args.X+args.Width, args.Y+args.Height)
+	img, err := s.backend.CaptureScreen(ctx, region)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("failed to encode screenshot: %w", err)
+	}
+	reply.PNG = buf.Bytes()
+	return nil
+}
+
+// ActiveWindowBounds returns the bounds of the focused window
+func (s *Service) ActiveWindowBounds(args Call, reply *RectArgs) error {
+	ctx, end := s.begin(args)
+	defer end()
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	r, err := s.backend.ActiveWindowBounds(ctx)
+	*reply = RectArgs{X: r.Min.X, Y: r.Min.Y, Width: r.Dx(), Height: r.Dy()}
+	return err
+}
+
+// SetAnchor stores a named anchor shared by all clients
+func (s *Service) SetAnchor(args AnchorArgs, reply *Empty) error {
+	name := strings.ToLower(strings.TrimPrefix(args.Name, "@"))
+	if name == "" {
+		return fmt.Errorf("anchor name cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.anchors[name] = PointArgs{X: args.X, Y: args.Y}
+	return nil
+}
+
+// Anchors returns all anchors stored in the daemon
+func (s *Service) Anchors(args Empty, reply *AnchorsReply) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reply.Anchors = make(map[string]PointArgs, len(s.anchors))
+	for name, p := range s.anchors {
+		reply.Anchors[name] = p
+	}
+	return nil
+}
+
+// HeldKeys returns the keys currently held down through the daemon
+func (s *Service) HeldKeys(args Empty, reply *KeysReply) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reply.Keys = make([]string, 0, len(s.held))
+	for key := range s.held {
+		reply.Keys = append(reply.Keys, key)
+	}
+	sort.Strings(reply.Keys)
+	return nil
+}
+
+// releaseAll releases all keys still held down through the daemon
+func (s *Service) releaseAll() {
+	s.backendMu.Lock()
+	defer s.backendMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.held {
+		if err := s.backend.KeyToggle(context.Background(), key, false); err != nil {
+			log.Printf("failed to release key %s: %v", key, err)
+		}
+		delete(s.held, key)
+	}
+}
+
+// Serve listens on the Unix socket at path and serves the service until ctx is
+// cancelled. A stale socket file left behind by a crashed daemon is replaced,
+// but Serve refuses to start if another daemon is answering on it.
+func Serve(ctx context.Context, path string, service *Service) error {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("a daemon is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+	}
+
+	listener, err := listenPrivate(path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	defer os.Remove(path)
+
+	server := rpc.NewServer()
+	if err := server.RegisterName(ServiceName, service); err != nil {
+		listener.Close()
+		return fmt.Errorf("failed to register service: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	defer service.releaseAll()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// listenPrivate listens on a Unix socket at path that only the current user may
+// connect to. The socket is created inside a private directory, restricted and
+// then moved into place, so other users cannot connect before its permissions
+// are set.
+func listenPrivate(path string) (*net.UnixListener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".desktop-automation-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmp := filepath.Join(dir, "sock")
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmp, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	// The temporary path is gone once the socket is moved
+	listener.SetUnlinkOnClose(false)
+
+	if err := os.Chmod(tmp, 0o600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict permissions: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+)
+
+func TestListenPrivate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.sock")
+	listener, err := listenPrivate(path)
+	if err != nil {
+		t.Fatalf("listenPrivate failed: %v", err)
+	}
+	defer listener.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("socket is not at %s: %v", path, err)
+	}
+	if info.Mode()&os.ModeSocket == 0 || info.Mode().Perm() != 0o600 {
+		t.Errorf("socket mode is %v, want a socket with permissions 0600", info.Mode())
+	}
+
+	// The private directory the socket was created in is removed
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d entries, want only the socket", len(entries))
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("failed to connect to the moved socket: %v", err)
+	}
+	conn.Close()
+}
+
+// blockingBackend blocks in TypeString until the call's context is done
+type blockingBackend struct {
+	automation.Backend
+	started chan struct{}
+	done    chan error
+}
+
+func (b *blockingBackend) TypeString(ctx context.Context, text string) error {
+	close(b.started)
+	<-ctx.Done()
+	b.done <- ctx.Err()
+	return ctx.Err()
+}
+
+func TestCallCancelledInDaemon(t *testing.T) {
+	backend := &blockingBackend{started: make(chan struct{}), done: make(chan error, 1)}
+	path := filepath.Join(t.TempDir(), "daemon.sock")
+	serveCtx, stop := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- Serve(serveCtx, path, NewService(backend)) }()
+	defer func() {
+		stop()
+		<-served
+	}()
+
+	var client *Client
+	for i := 0; client == nil; i++ {
+		c, err := Dial(path)
+		if err == nil {
+			client = c
+		} else if i == 100 {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-backend.started
+		cancel()
+	}()
+	if err := client.TypeString(ctx, "hello"); !errors.Is(err, context.Canceled) {
+		t.Errorf("TypeString() error = %v, want %v", err, context.Canceled)
+	}
+
+	select {
+	case err := <-backend.done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("backend context error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("the call kept running in the daemon")
+	}
+}
+
+func TestServiceBegin(t *testing.T) {
+	tests := []struct {
+		name    string
+		call    Call
+		wantErr error
+	}{
+		{"no deadline", Call{ID: 1}, nil},
+		{"no ID", Call{}, nil},
+		{"future deadline", Call{ID: 2, Deadline: time.Now().Add(time.Hour)}, nil},
+		{"past deadline", Call{ID: 3, Deadline: time.Now().Add(-time.Second)}, context.DeadlineExceeded},
+		{"cancelled before it started", Call{ID: 7}, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(nil)
+			if err := s.Cancel(CancelArgs{ID: 7}, &Empty{}); err != nil {
+				t.Fatal(err)
+			}
+			ctx, end := s.begin(tt.call)
+			if err := ctx.Err(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("context error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.call.ID != 0 {
+				if err := s.Cancel(CancelArgs{ID: tt.call.ID}, &Empty{}); err != nil {
+					t.Fatal(err)
+				}
+				if ctx.Err() == nil {
+					t.Error("Cancel() left the call's context live")
+				}
+			}
+			end()
+			if len(s.calls) != 0 {
+				t.Errorf("%d calls registered after the call ended, want 0", len(s.calls))
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package grpcapi
+
+import (
+	pb "github.com/dmahlow/desktop-automation/api/desktopautomation/v1"
+	"github.com/dmahlow/desktop-automation/internal/remote"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Names of the protobuf enum values in the form the remote package expects.
+// Unspecified values map to "", which selects the default.
+var (
+	pathNames = map[pb.PathKind]string{
+		pb.PathKind_PATH_KIND_UNSPECIFIED: "",
+		pb.PathKind_PATH_KIND_LINEAR:      "linear",
+		pb.PathKind_PATH_KIND_BEZIER:      "bezier",
+		pb.PathKind_PATH_KIND_WIND:        "wind",
+	}
+	easingNames = map[pb.Easing]string{
+		pb.Easing_EASING_UNSPECIFIED: "",
+		pb.Easing_EASING_IN_OUT:      "in-out",
+		pb.Easing_EASING_OUT:         "out",
+		pb.Easing_EASING_NONE:        "none",
+	}
+	buttonNames = map[pb.MouseButton]string{
+		pb.MouseButton_MOUSE_BUTTON_UNSPECIFIED: "",
+		pb.MouseButton_MOUSE_BUTTON_LEFT:        "left",
+		pb.MouseButton_MOUSE_BUTTON_RIGHT:       "right",
+	}
+	keyActionNames = map[pb.KeyAction]string{
+		pb.KeyAction_KEY_ACTION_UNSPECIFIED: "",
+		pb.KeyAction_KEY_ACTION_PRESS:       "press",
+		pb.KeyAction_KEY_ACTION_DOWN:        "down",
+		pb.KeyAction_KEY_ACTION_UP:          "up",
+	}
+)
+
+// enumName looks up the name of an enum value, rejecting values a newer client
+// may send that this server does not know
+func enumName[E ~int32](field string, names map[E]string, value E) (string, error) {
+	name, ok := names[value]
+	if !ok {
+		return "", status.Errorf(codes.InvalidArgument, "unknown %s: %d", field, value)
+	}
+	return name, nil
+}
+
+func targetFromProto(t *pb.Target) remote.TargetRequest {
+	req := remote.TargetRequest{
+		Target:     t.GetExpression(),
+		RelativeTo: t.GetRelativeTo(),
+	}
+	if p := t.GetPoint(); p != nil {
+		x, y := int(p.GetX()), int(p.GetY())
+		req.X, req.Y = &x, &y
+	}
+	return req
+}
+
+func moveFromProto(m *pb.MoveRequest) (remote.MoveRequest, error) {
+	path, err := enumName("path", pathNames, m.GetPath())
+	if err != nil {
+		return remote.MoveRequest{}, err
+	}
+	easing, err := enumName("easing", easingNames, m.GetEasing())
+	if err != nil {
+		return remote.MoveRequest{}, err
+	}
+	return remote.MoveRequest{
+		TargetRequest: targetFromProto(m.GetTarget()),
+		Smooth:        m.GetSmooth(),
+		Duration:      m.GetDuration(),
+		Path:          path,
+		Easing:        easing,
+		Overshoot:     m.GetOvershoot(),
+		Jitter:        m.GetJitter(),
+		Seed:          m.GetSeed(),
+	}, nil
+}
+
+func clickFromProto(m *pb.ClickRequest) (remote.ClickRequest, error) {
+	button, err := enumName("button", buttonNames, m.GetButton())
+	if err != nil {
+		return remote.ClickRequest{}, err
+	}
+	return remote.ClickRequest{
+		TargetRequest: targetFromProto(m.GetTarget()),
+		Button:        button,
+		Double:        m.GetDouble(),
+	}, nil
+}
+
+func typeFromProto(m *pb.TypeRequest) remote.TypeRequest {
+	return remote.TypeRequest{
+		Text:    m.GetText(),
+		DelayMs: int(m.GetDelayMs()),
+		Profile: m.GetProfile(),
+		WPM:     m.GetWpm(),
+		Seed:    m.GetSeed(),
+	}
+}
+
+func keyFromProto(m *pb.KeyRequest) (remote.KeyRequest, error) {
+	action, err := enumName("key action", keyActionNames, m.GetAction())
+	if err != nil {
+		return remote.KeyRequest{}, err
+	}
+	return remote.KeyRequest{
+		Key:    m.GetKey(),
+		Action: action,
+	}, nil
+}
+
+func scrollFromProto(m *pb.ScrollRequest) remote.ScrollRequest {
+	return remote.ScrollRequest{DX: int(m.GetDx()), DY: int(m.GetDy())}
+}
+
+func regionFromProto(r *pb.Rect) remote.Region {
+	return remote.Region{
+		X:      int(r.GetX()),
+		Y:      int(r.GetY()),
+		Width:  int(r.GetWidth()),
+		Height: int(r.GetHeight()),
+	}
+}
+
+func pointToProto(p remote.Position) *pb.Point {
+	return &pb.Point{X: int32(p.X), Y: int32(p.Y)}
+}
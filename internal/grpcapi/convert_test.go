@@ -0,0 +1,154 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	pb "github.com/dmahlow/desktop-automation/api/desktopautomation/v1"
+	"github.com/dmahlow/desktop-automation/internal/automation"
+	"github.com/dmahlow/desktop-automation/internal/remote"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMoveFromProto(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *pb.MoveRequest
+		wantPath   string
+		wantEasing string
+		wantErr    bool
+	}{
+		{"unspecified", &pb.MoveRequest{}, "", "", false},
+		{"known", &pb.MoveRequest{Path: pb.PathKind_PATH_KIND_WIND, Easing: pb.Easing_EASING_OUT}, "wind", "out", false},
+		{"unknown path", &pb.MoveRequest{Path: pb.PathKind(42)}, "", "", true},
+		{"unknown easing", &pb.MoveRequest{Easing: pb.Easing(42)}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := moveFromProto(tt.req)
+			if tt.wantErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Errorf("moveFromProto() error = %v, want InvalidArgument", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("moveFromProto() error = %v", err)
+			}
+			if got.Path != tt.wantPath || got.Easing != tt.wantEasing {
+				t.Errorf("moveFromProto() path, easing = %q, %q, want %q, %q", got.Path, got.Easing, tt.wantPath, tt.wantEasing)
+			}
+		})
+	}
+}
+
+func TestClickFromProto(t *testing.T) {
+	tests := []struct {
+		button  pb.MouseButton
+		want    string
+		wantErr bool
+	}{
+		{pb.MouseButton_MOUSE_BUTTON_UNSPECIFIED, "", false},
+		{pb.MouseButton_MOUSE_BUTTON_LEFT, "left", false},
+		{pb.MouseButton_MOUSE_BUTTON_RIGHT, "right", false},
+		{pb.MouseButton(7), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.button.String(), func(t *testing.T) {
+			got, err := clickFromProto(&pb.ClickRequest{Button: tt.button})
+			if (status.Code(err) == codes.InvalidArgument) != tt.wantErr {
+				t.Fatalf("clickFromProto(%v) error = %v, want error %v", tt.button, err, tt.wantErr)
+			}
+			if got.Button != tt.want {
+				t.Errorf("clickFromProto(%v) button = %q, want %q", tt.button, got.Button, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyFromProto(t *testing.T) {
+	tests := []struct {
+		action  pb.KeyAction
+		want    string
+		wantErr bool
+	}{
+		{pb.KeyAction_KEY_ACTION_UNSPECIFIED, "", false},
+		{pb.KeyAction_KEY_ACTION_PRESS, "press", false},
+		{pb.KeyAction_KEY_ACTION_DOWN, "down", false},
+		{pb.KeyAction_KEY_ACTION_UP, "up", false},
+		{pb.KeyAction(9), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action.String(), func(t *testing.T) {
+			got, err := keyFromProto(&pb.KeyRequest{Key: "a", Action: tt.action})
+			if (status.Code(err) == codes.InvalidArgument) != tt.wantErr {
+				t.Fatalf("keyFromProto(%v) error = %v, want error %v", tt.action, err, tt.wantErr)
+			}
+			if got.Action != tt.want {
+				t.Errorf("keyFromProto(%v) action = %q, want %q", tt.action, got.Action, tt.want)
+			}
+		})
+	}
+}
+
+// Unknown enum values are rejected before anything reaches the backend
+func TestUnknownEnumRejected(t *testing.T) {
+	c := remote.NewController(automation.NewCoordSystem(automation.CoordLogical))
+	mouse := &mouseServer{c: c}
+	keyboard := &keyboardServer{c: c}
+	input := &inputServer{c: c}
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"Move": func() error {
+			_, err := mouse.Move(ctx, &pb.MoveRequest{Path: pb.PathKind(42)})
+			return err
+		},
+		"Click": func() error {
+			_, err := mouse.Click(ctx, &pb.ClickRequest{Button: pb.MouseButton(42)})
+			return err
+		},
+		"Key": func() error {
+			_, err := keyboard.Key(ctx, &pb.KeyRequest{Key: "a", Action: pb.KeyAction(42)})
+			return err
+		},
+		"InputEvents": func() error {
+			return input.perform(ctx, &pb.InputEvent{Event: &pb.InputEvent_Key{Key: &pb.KeyRequest{Key: "a", Action: pb.KeyAction(42)}}})
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			if err := call(); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("%s error = %v, want InvalidArgument", name, err)
+			}
+		})
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	c := remote.NewController(automation.NewCoordSystem(automation.CoordLogical))
+	inputErr := c.Type(context.Background(), remote.TypeRequest{})
+
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"nil", nil, codes.OK},
+		{"status", status.Error(codes.NotFound, "gone"), codes.NotFound},
+		{"input error", inputErr, codes.InvalidArgument},
+		{"cancelled", fmt.Errorf("move: %w", context.Canceled), codes.Canceled},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"other", errors.New("backend failed"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(toStatus(tt.err)); got != tt.want {
+				t.Errorf("toStatus(%v) code = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+// Package grpcapi serves the automation package over the gRPC services defined
+// in api/proto, so Go test harnesses can drive a desktop with typed clients,
+// deadlines and streaming screenshots.
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"net"
+	"time"
+
+	pb "github.com/dmahlow/desktop-automation/api/desktopautomation/v1"
+	"github.com/dmahlow/desktop-automation/internal/remote"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer creates a gRPC server with all services registered, performing
+// requests with controller and requiring token as bearer token in the
+// "authorization" metadata of every call
+func NewServer(controller *remote.Controller, token string) *grpc.Server {
+	auth := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, header := range md.Get("authorization") {
+			if remote.CheckAuthorization(header, token) {
+				return nil
+			}
+		}
+		return status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	}
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := auth(ctx); err != nil {
+				return nil, err
+			}
+			resp, err := handler(ctx, req)
+			return resp, toStatus(err)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := auth(ss.Context()); err != nil {
+				return err
+			}
+			return toStatus(handler(srv, ss))
+		}),
+	)
+
+	pb.RegisterMouseServer(server, &mouseServer{c: controller})
+	pb.RegisterKeyboardServer(server, &keyboardServer{c: controller})
+	pb.RegisterScreenServer(server, &screenServer{c: controller})
+	pb.RegisterWindowServer(server, &windowServer{c: controller})
+	pb.RegisterInputServer(server, &inputServer{c: controller})
+	return server
+}
+
+// toStatus converts an error to a gRPC status error with a fitting code
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if remote.IsInputError(err) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// Serve serves server on addr until ctx is cancelled
+func Serve(ctx context.Context, addr string, server *grpc.Server) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		// Cancel running calls such as screenshot streams instead of waiting for them
+		server.Stop()
+	}()
+
+	return server.Serve(listener)
+}
+
+type mouseServer struct {
+	pb.UnimplementedMouseServer
+	c *remote.Controller
+}
+
+func (s *mouseServer) Move(ctx context.Context, req *pb.MoveRequest) (*pb.Point, error) {
+	move, err := moveFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	pos, err := s.c.Move(ctx, move)
+	if err != nil {
+		return nil, err
+	}
+	return pointToProto(pos), nil
+}
+
+func (s *mouseServer) Click(ctx context.Context, req *pb.ClickRequest) (*pb.Point, error) {
+	click, err := clickFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	pos, err := s.c.Click(ctx, click)
+	if err != nil {
+		return nil, err
+	}
+	return pointToProto(pos), nil
+}
+
+func (s *mouseServer) Scroll(ctx context.Context, req *pb.ScrollRequest) (*pb.ScrollResponse, error) {
+	if err := s.c.Scroll(ctx, scrollFromProto(req)); err != nil {
+		return nil, err
+	}
+	return &pb.ScrollResponse{}, nil
+}
+
+func (s *mouseServer) Position(ctx context.Context, req *pb.PositionRequest) (*pb.Point, error) {
+	return pointToProto(s.c.Cursor()), nil
+}
+
+type keyboardServer struct {
+	pb.UnimplementedKeyboardServer
+	c *remote.Controller
+}
+
+func (s *keyboardServer) Type(ctx context.Context, req *pb.TypeRequest) (*pb.TypeResponse, error) {
+	if err := s.c.Type(ctx, typeFromProto(req)); err != nil {
+		return nil, err
+	}
+	return &pb.TypeResponse{}, nil
+}
+
+func (s *keyboardServer) Key(ctx context.Context, req *pb.KeyRequest) (*pb.KeyResponse, error) {
+	key, err := keyFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.c.Key(ctx, key); err != nil {
+		return nil, err
+	}
+	return &pb.KeyResponse{}, nil
+}
+
+type screenServer struct {
+	pb.UnimplementedScreenServer
+	c *remote.Controller
+}
+
+func (s *screenServer) Size(ctx context.Context, req *pb.SizeRequest) (*pb.SizeResponse, error) {
+	info := s.c.Screen()
+	return &pb.SizeResponse{
+		Width:  int32(info.Width),
+		Height: int32(info.Height),
+		Scale:  info.Scale,
+		Coords: info.Coords,
+	}, nil
+}
+
+func (s *screenServer) Capture(ctx context.Context, req *pb.CaptureRequest) (*pb.Image, error) {
+	return s.capture(ctx, regionFromProto(req.GetRegion()))
+}
+
+func (s *screenServer) StreamCaptures(req *pb.StreamCapturesRequest, stream pb.Screen_StreamCapturesServer) error {
+	fps := req.GetFps()
+	if fps <= 0 {
+		fps = 1
+	}
+	if fps > 60 {
+		return status.Errorf(codes.InvalidArgument, "fps must be at most 60: %g", fps)
+	}
+	region := regionFromProto(req.GetRegion())
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / fps))
+	defer ticker.Stop()
+
+	ctx := stream.Context()
+	for sent := int32(0); req.GetMaxFrames() == 0 || sent < req.GetMaxFrames(); sent++ {
+		img, err := s.capture(ctx, region)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(img); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// capture captures a region of the screen as a PNG encoded image
+func (s *screenServer) capture(ctx context.Context, region remote.Region) (*pb.Image, error) {
+	img, err := s.c.Capture(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode screenshot: %w", err)
+	}
+	return &pb.Image{
+		Png:       buf.Bytes(),
+		Width:     int32(img.Bounds().Dx()),
+		Height:    int32(img.Bounds().Dy()),
+		Timestamp: time.Now().UnixNano(),
+	}, nil
+}
+
+type windowServer struct {
+	pb.UnimplementedWindowServer
+	c *remote.Controller
+}
+
+func (s *windowServer) ActiveBounds(ctx context.Context, req *pb.ActiveBoundsRequest) (*pb.Rect, error) {
+	r, err := s.c.ActiveWindow(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Rect{X: int32(r.X), Y: int32(r.Y), Width: int32(r.Width), Height: int32(r.Height)}, nil
+}
+
+type inputServer struct {
+	pb.UnimplementedInputServer
+	c *remote.Controller
+}
+
+func (s *inputServer) InputEvents(stream pb.Input_InputEventsServer) error {
+	ctx := stream.Context()
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		result := &pb.InputEventResult{Id: event.GetId()}
+		if err := s.perform(ctx, event); err != nil {
+			// A cancelled stream ends the call, other failures are reported per event
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			result.Error = err.Error()
+		}
+		result.Position = pointToProto(s.c.Cursor())
+
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+}
+
+// perform performs a single input event
+func (s *inputServer) perform(ctx context.Context, event *pb.InputEvent) error {
+	switch e := event.GetEvent().(type) {
+	case *pb.InputEvent_Move:
+		move, err := moveFromProto(e.Move)
+		if err != nil {
+			return err
+		}
+		_, err = s.c.Move(ctx, move)
+		return err
+	case *pb.InputEvent_Click:
+		click, err := clickFromProto(e.Click)
+		if err != nil {
+			return err
+		}
+		_, err = s.c.Click(ctx, click)
+		return err
+	case *pb.InputEvent_Scroll:
+		return s.c.Scroll(ctx, scrollFromProto(e.Scroll))
+	case *pb.InputEvent_Type:
+		return s.c.Type(ctx, typeFromProto(e.Type))
+	case *pb.InputEvent_Key:
+		key, err := keyFromProto(e.Key)
+		if err != nil {
+			return err
+		}
+		return s.c.Key(ctx, key)
+	}
+	return fmt.Errorf("event %d has no action", event.GetId())
+}
@@ -0,0 +1,109 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// endpoint describes one API operation. The same description routes requests,
+// decodes their input and generates the OpenAPI document.
+type endpoint struct {
+	method      string
+	path        string
+	summary     string
+	description string
+
+	// request is the type of the input, read from the query string for GET
+	// requests and from the JSON body otherwise; nil when there is no input
+	request reflect.Type
+	// response is the type of the JSON result, or pngImage for images
+	response reflect.Type
+
+	handle func(ctx context.Context, req any) (any, error)
+}
+
+// pngImage is the result of endpoints returning a PNG encoded image
+type pngImage []byte
+
+// newEndpoint creates an endpoint from a typed handler. Req is decoded from the
+// request and Resp is the handler's result; use struct{} for either when unused.
+func newEndpoint[Req, Resp any](method, path, summary, description string, fn func(ctx context.Context, req Req) (Resp, error)) endpoint {
+	e := endpoint{
+		method:      method,
+		path:        path,
+		summary:     summary,
+		description: description,
+		response:    reflect.TypeFor[Resp](),
+		handle: func(ctx context.Context, req any) (any, error) {
+			return fn(ctx, req.(Req))
+		},
+	}
+	if t := reflect.TypeFor[Req](); t.NumField() > 0 {
+		e.request = t
+	}
+	return e
+}
+
+// decode reads the endpoint's input from r
+func (e endpoint) decode(r *http.Request) (any, error) {
+	if e.request == nil {
+		return struct{}{}, nil
+	}
+
+	v := reflect.New(e.request)
+	if e.method == http.MethodGet {
+		if err := decodeQuery(r, v.Elem()); err != nil {
+			return nil, err
+		}
+		return v.Elem().Interface(), nil
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v.Interface()); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	return v.Elem().Interface(), nil
+}
+
+// decodeQuery fills the fields of the struct v from query parameters named by their json tag
+func decodeQuery(r *http.Request, v reflect.Value) error {
+	query := r.URL.Query()
+	for _, f := range fields(v.Type()) {
+		name := fieldName(f)
+		if !query.Has(name) {
+			continue
+		}
+
+		raw := query.Get(name)
+		field := v.FieldByIndex(f.Index)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(raw)
+		case reflect.Int, reflect.Int64:
+			n, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s parameter '%s': must be an integer", name, raw)
+			}
+			field.SetInt(n)
+		case reflect.Float64:
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s parameter '%s': must be a number", name, raw)
+			}
+			field.SetFloat(f)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				return fmt.Errorf("invalid %s parameter '%s': must be true or false", name, raw)
+			}
+			field.SetBool(b)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// OpenAPI returns the OpenAPI 3 document describing the server's endpoints.
+// Field descriptions come from `doc` struct tags and allowed values from `enum` tags.
+func (s *Server) OpenAPI() []byte {
+	paths := map[string]map[string]any{}
+	for _, e := range s.endpoints {
+		if paths[e.path] == nil {
+			paths[e.path] = map[string]any{}
+		}
+		paths[e.path][strings.ToLower(e.method)] = e.operation()
+	}
+
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "desktop-automation",
+			"version": "1",
+			"description": "Control the mouse and keyboard and capture the screen. Coordinates are in the " +
+				s.controller.Coords().Space.String() + " coordinate space of the server.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []any{map[string]any{"bearerAuth": []string{}}},
+	}
+
+	b, _ := json.MarshalIndent(doc, "", "  ")
+	return b
+}
+
+// operation returns the OpenAPI operation object of the endpoint
+func (e endpoint) operation() map[string]any {
+	op := map[string]any{
+		"summary":     e.summary,
+		"description": e.description,
+		"operationId": strings.ReplaceAll(strings.Trim(e.path, "/"), "/", "_"),
+	}
+
+	if e.request != nil {
+		if e.method == http.MethodGet {
+			op["parameters"] = queryParameters(e.request)
+		} else {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": schemaFor(e.request)},
+				},
+			}
+		}
+	}
+
+	ok := map[string]any{"description": "Success"}
+	if e.response == reflect.TypeFor[pngImage]() {
+		ok["content"] = map[string]any{
+			"image/png": map[string]any{"schema": map[string]any{"type": "string", "format": "binary"}},
+		}
+	} else {
+		ok["content"] = map[string]any{
+			"application/json": map[string]any{"schema": schemaFor(e.response)},
+		}
+	}
+
+	errorResponse := func(description string) map[string]any {
+		return map[string]any{
+			"description": description,
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": schemaFor(reflect.TypeFor[ErrorResponse]())},
+			},
+		}
+	}
+
+	op["responses"] = map[string]any{
+		"200": ok,
+		"400": errorResponse("Invalid request"),
+		"401": errorResponse("Missing or invalid bearer token"),
+		"500": errorResponse("Automation failed"),
+	}
+	return op
+}
+
+// queryParameters returns the OpenAPI parameters of a request read from the query string
+func queryParameters(t reflect.Type) []any {
+	var params []any
+	for _, f := range fields(t) {
+		name := fieldName(f)
+		params = append(params, map[string]any{
+			"name":        name,
+			"in":          "query",
+			"required":    isRequired(f),
+			"description": f.Tag.Get("doc"),
+			"schema":      fieldSchema(f),
+		})
+	}
+	return params
+}
+
+// schemaFor returns the JSON schema of a Go type
+func schemaFor(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := map[string]any{}
+		var required []string
+		for _, f := range fields(t) {
+			name := fieldName(f)
+			properties[name] = fieldSchema(f)
+			if isRequired(f) {
+				required = append(required, name)
+			}
+		}
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		return map[string]any{}
+	}
+}
+
+// fieldSchema returns the JSON schema of a struct field including its tags
+func fieldSchema(f reflect.StructField) map[string]any {
+	schema := schemaFor(f.Type)
+	if doc := f.Tag.Get("doc"); doc != "" {
+		schema["description"] = doc
+	}
+	if enum := f.Tag.Get("enum"); enum != "" {
+		schema["enum"] = strings.Split(enum, ",")
+	}
+	return schema
+}
+
+// fields returns the serialized fields of a struct type, including those
+// promoted from embedded structs
+func fields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for _, f := range reflect.VisibleFields(t) {
+		if f.Anonymous || fieldName(f) == "" {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// fieldName returns the JSON name of a struct field, or "" if it is not serialized
+func fieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" || !f.IsExported() {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// isRequired reports whether a field must be present, which is the case for all
+// fields not marked omitempty
+func isRequired(f reflect.StructField) bool {
+	_, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return !strings.Contains(opts, "omitempty")
+}
@@ -0,0 +1,66 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+	"net/http"
+
+	"github.com/dmahlow/desktop-automation/internal/remote"
+)
+
+// OKResponse is the result of actions without a meaningful result
+type OKResponse struct {
+	OK bool `json:"ok" doc:"Always true"`
+}
+
+// routes returns the API endpoints
+func (s *Server) routes() []endpoint {
+	c := s.controller
+	return []endpoint{
+		newEndpoint(http.MethodPost, "/v1/move", "Move the mouse cursor",
+			"Moves the cursor to a position, instantly or along an animated trajectory.", c.Move),
+		newEndpoint(http.MethodPost, "/v1/click", "Click at a position",
+			"Moves the cursor to a position and clicks a mouse button there.", c.Click),
+		newEndpoint(http.MethodPost, "/v1/type", "Type text",
+			"Types text at the current focus, optionally with a fixed delay or a human-like cadence.", ok(c.Type)),
+		newEndpoint(http.MethodPost, "/v1/key", "Press a key or key combination",
+			"Presses, holds or releases a key or a combination such as ctrl+c.", ok(c.Key)),
+		newEndpoint(http.MethodPost, "/v1/scroll", "Scroll the mouse wheel",
+			"Scrolls the mouse wheel at the current cursor position.", ok(c.Scroll)),
+		newEndpoint(http.MethodGet, "/v1/screenshot", "Capture a screenshot",
+			"Captures the screen or a region of it as PNG in physical pixels.", s.screenshot),
+		newEndpoint(http.MethodGet, "/v1/cursor", "Get the cursor position",
+			"Returns the current cursor position.", func(ctx context.Context, req struct{}) (remote.Position, error) {
+				return c.Cursor(), nil
+			}),
+		newEndpoint(http.MethodGet, "/v1/screen", "Get the screen size",
+			"Returns the size of the main display and its scale factor.", func(ctx context.Context, req struct{}) (remote.ScreenInfo, error) {
+				return c.Screen(), nil
+			}),
+	}
+}
+
+// ok adapts an action without result to an endpoint handler returning OKResponse
+func ok[Req any](fn func(ctx context.Context, req Req) error) func(ctx context.Context, req Req) (OKResponse, error) {
+	return func(ctx context.Context, req Req) (OKResponse, error) {
+		if err := fn(ctx, req); err != nil {
+			return OKResponse{}, err
+		}
+		return OKResponse{OK: true}, nil
+	}
+}
+
+func (s *Server) screenshot(ctx context.Context, req remote.Region) (pngImage, error) {
+	img, err := s.controller.Capture(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode screenshot: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,131 @@
+// Package httpapi serves the automation package over a REST API so tools that
+// are not written in Go can drive the desktop over HTTP. The endpoints are
+// declared once in a table, which is used both to route requests and to
+// generate the OpenAPI description served at /openapi.json.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/dmahlow/desktop-automation/internal/remote"
+)
+
+// OpenAPIPath is the path the OpenAPI document is served at, without authentication
+const OpenAPIPath = "/openapi.json"
+
+// Server serves the automation API over HTTP
+type Server struct {
+	controller *remote.Controller
+	token      string
+	endpoints  []endpoint
+}
+
+// NewServer creates a server performing requests with controller that
+// requires token as bearer token on every API request
+func NewServer(controller *remote.Controller, token string) *Server {
+	s := &Server{controller: controller, token: token}
+	s.endpoints = s.routes()
+	return s
+}
+
+// Handler returns the HTTP handler serving the API and its OpenAPI document
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+OpenAPIPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(s.OpenAPI())
+	})
+	for _, e := range s.endpoints {
+		mux.Handle(e.method+" "+e.path, s.authenticate(s.serveEndpoint(e)))
+	}
+	return mux
+}
+
+// authenticate rejects requests that do not carry the server's bearer token
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !remote.CheckAuthorization(r.Header.Get("Authorization"), s.token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="desktop-automation"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveEndpoint decodes the request of an endpoint, runs it and encodes its response
+func (s *Server) serveEndpoint(e endpoint) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := e.decode(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		resp, err := e.handle(r.Context(), req)
+		switch {
+		case remote.IsInputError(err):
+			writeError(w, http.StatusBadRequest, err)
+			return
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if img, ok := resp.(pngImage); ok {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(img)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+// ErrorResponse is returned with every unsuccessful status code
+type ErrorResponse struct {
+	Error string `json:"error" doc:"Description of what went wrong"`
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+// ListenAndServe serves the API on addr until ctx is cancelled
+func ListenAndServe(ctx context.Context, addr string, s *Server) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+	"github.com/dmahlow/desktop-automation/internal/remote"
+)
+
+const testToken = "test-token"
+
+// fakeBackend is a 200x100 screen whose key presses fail with err
+type fakeBackend struct {
+	automation.Backend
+	x, y int
+	err  error
+}
+
+func (f *fakeBackend) MoveMouse(ctx context.Context, x, y int) error {
+	f.x, f.y = x, y
+	return nil
+}
+
+func (f *fakeBackend) MousePosition(ctx context.Context) (int, int, error) {
+	return f.x, f.y, nil
+}
+
+func (f *fakeBackend) ScreenSize(ctx context.Context) (int, int, error) {
+	return 200, 100, nil
+}
+
+func (f *fakeBackend) ScaleFactor(ctx context.Context) (float64, error) {
+	return 1, nil
+}
+
+func (f *fakeBackend) KeyTap(ctx context.Context, key string, modifiers ...string) error {
+	return f.err
+}
+
+func (f *fakeBackend) CaptureScreen(ctx context.Context, region image.Rectangle) (image.Image, error) {
+	if region.Empty() {
+		region = image.Rect(0, 0, 200, 100)
+	}
+	return image.NewRGBA(image.Rect(0, 0, region.Dx(), region.Dy())), nil
+}
+
+// newTestServer serves the API on a fake backend
+func newTestServer(t *testing.T, backend *fakeBackend) *httptest.Server {
+	t.Helper()
+	old := automation.CurrentBackend()
+	automation.SetBackend(backend)
+	t.Cleanup(func() { automation.SetBackend(old) })
+
+	controller := remote.NewController(automation.NewCoordSystem(automation.CoordLogical))
+	srv := httptest.NewServer(NewServer(controller, testToken).Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAuthentication(t *testing.T) {
+	srv := newTestServer(t, &fakeBackend{})
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer wrong", http.StatusUnauthorized},
+		{"wrong scheme", "Basic " + testToken, http.StatusUnauthorized},
+		{"correct token", "Bearer " + testToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/cursor", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized {
+				if resp.Header.Get("WWW-Authenticate") == "" {
+					t.Error("401 response without WWW-Authenticate header")
+				}
+				var body ErrorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == "" {
+					t.Errorf("401 response body is not an error response: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestEndpointStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		keyErr   error
+		want     int
+		wantType string
+	}{
+		{"move", http.MethodPost, "/v1/move", `{"x": 50, "y": 20}`, nil, http.StatusOK, "application/json"},
+		{"move to target", http.MethodPost, "/v1/move", `{"target": "@center"}`, nil, http.StatusOK, "application/json"},
+		{"invalid JSON", http.MethodPost, "/v1/move", `{"x": `, nil, http.StatusBadRequest, "application/json"},
+		{"unknown field", http.MethodPost, "/v1/move", `{"x": 1, "y": 2, "z": 3}`, nil, http.StatusBadRequest, "application/json"},
+		{"missing coordinates", http.MethodPost, "/v1/click", `{}`, nil, http.StatusBadRequest, "application/json"},
+		{"invalid button", http.MethodPost, "/v1/click", `{"x": 1, "y": 1, "button": "middle"}`, nil, http.StatusBadRequest, "application/json"},
+		{"empty key", http.MethodPost, "/v1/key", `{"key": "ctrl+"}`, nil, http.StatusBadRequest, "application/json"},
+		{"key", http.MethodPost, "/v1/key", `{"key": "ctrl+c"}`, nil, http.StatusOK, "application/json"},
+		{"backend failure", http.MethodPost, "/v1/key", `{"key": "ctrl+c"}`, errors.New("no keyboard"), http.StatusInternalServerError, "application/json"},
+		{"invalid query", http.MethodGet, "/v1/screenshot?width=abc", "", nil, http.StatusBadRequest, "application/json"},
+		{"negative region", http.MethodGet, "/v1/screenshot?x=-1", "", nil, http.StatusBadRequest, "application/json"},
+		{"screenshot", http.MethodGet, "/v1/screenshot?x=10&y=10&width=20&height=30", "", nil, http.StatusOK, "image/png"},
+		{"wrong method", http.MethodGet, "/v1/move", "", nil, http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t, &fakeBackend{err: tt.keyErr})
+			req, _ := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+testToken)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if got := resp.Header.Get("Content-Type"); tt.wantType != "" && got != tt.wantType {
+				t.Errorf("content type = %q, want %q", got, tt.wantType)
+			}
+
+			switch {
+			case tt.want >= 400 && tt.wantType != "":
+				var body ErrorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == "" {
+					t.Errorf("error response without message: %v", err)
+				}
+			case tt.wantType == "image/png":
+				img, err := png.Decode(resp.Body)
+				if err != nil {
+					t.Fatalf("invalid PNG: %v", err)
+				}
+				if size := img.Bounds().Size(); size != image.Pt(20, 30) {
+					t.Errorf("screenshot size = %v, want 20x30", size)
+				}
+			}
+		})
+	}
+}
+
+func TestMoveResponse(t *testing.T) {
+	srv := newTestServer(t, &fakeBackend{})
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/v1/move", strings.NewReader(`{"x": 50, "y": 20}`))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var pos remote.Position
+	if err := json.NewDecoder(resp.Body).Decode(&pos); err != nil {
+		t.Fatal(err)
+	}
+	if pos != (remote.Position{X: 50, Y: 20}) {
+		t.Errorf("position = %+v, want 50, 20", pos)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	srv := newTestServer(t, &fakeBackend{})
+
+	// The document is served without a token
+	resp, err := http.Get(srv.URL + OpenAPIPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatalf("invalid OpenAPI document: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("openapi = %q, want 3.0.3", doc.OpenAPI)
+	}
+
+	s := NewServer(remote.NewController(automation.NewCoordSystem(automation.CoordLogical)), testToken)
+	for _, e := range s.endpoints {
+		if _, ok := doc.Paths[e.path][strings.ToLower(e.method)]; !ok {
+			t.Errorf("document does not describe %s %s", e.method, e.path)
+		}
+	}
+}
+
+func TestSchemaFor(t *testing.T) {
+	schema := schemaFor(reflect.TypeFor[remote.KeyRequest]())
+	props := schema["properties"].(map[string]any)
+
+	key := props["key"].(map[string]any)
+	if key["type"] != "string" || key["description"] == "" {
+		t.Errorf("key schema = %v, want a described string", key)
+	}
+	action := props["action"].(map[string]any)
+	if enum, _ := action["enum"].([]string); len(enum) != 3 {
+		t.Errorf("action enum = %v, want press, down and up", action["enum"])
+	}
+	if required, _ := schema["required"].([]string); len(required) != 1 || required[0] != "key" {
+		t.Errorf("required = %v, want [key]", schema["required"])
+	}
+
+	// Fields of embedded structs are promoted
+	move := schemaFor(reflect.TypeFor[remote.MoveRequest]())["properties"].(map[string]any)
+	for _, name := range []string{"x", "y", "target", "relative_to", "path"} {
+		if _, ok := move[name]; !ok {
+			t.Errorf("move schema has no property %q", name)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+// Package imaging holds small pixel helpers shared by the packages that draw
+// into images. It has no dependencies, so pure image code can use it without
+// pulling in the automation backends.
+package imaging
+
+// Blend mixes the colour component over into under with the given opacity
+func Blend(under, over uint8, alpha float64) uint8 {
+	return uint8(float64(under)*(1-alpha) + float64(over)*alpha + 0.5)
+}
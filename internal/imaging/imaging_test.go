@@ -0,0 +1,23 @@
+package imaging
+
+import "testing"
+
+func TestBlend(t *testing.T) {
+	tests := []struct {
+		under, over uint8
+		alpha       float64
+		want        uint8
+	}{
+		{0, 255, 0, 0},
+		{0, 255, 1, 255},
+		{0, 255, 0.5, 128},
+		{100, 200, 0.25, 125},
+		{255, 0, 0.6, 102},
+		{37, 37, 0.4, 37},
+	}
+	for _, tt := range tests {
+		if got := Blend(tt.under, tt.over, tt.alpha); got != tt.want {
+			t.Errorf("Blend(%d, %d, %g) = %d, want %d", tt.under, tt.over, tt.alpha, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"time"
+)
+
+// apngEncoder streams an animated PNG to a file. Every frame is encoded with
+// image/png and its image data moved into APNG frame chunks; frames after the
+// first only contain the rectangle that changed.
+type apngEncoder struct {
+	f       *os.File
+	png     png.Encoder
+	prev    *image.RGBA
+	seq     uint32
+	frames  uint32
+	actlPos int64
+}
+
+func createAPNG(path string) (*apngEncoder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &apngEncoder{f: f, png: png.Encoder{CompressionLevel: png.BestSpeed}}, nil
+}
+
+func (e *apngEncoder) WriteFrame(img *image.RGBA, d time.Duration) error {
+	bounds := img.Rect
+	if e.prev != nil {
+		if changed := changedBounds(e.prev, img); !changed.Empty() {
+			bounds = changed
+		} else {
+			bounds = image.Rect(0, 0, 1, 1)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := e.png.Encode(&buf, img.SubImage(bounds)); err != nil {
+		return err
+	}
+	chunks, err := readChunks(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if e.prev == nil {
+		if err := e.writeHeader(chunks); err != nil {
+			return err
+		}
+	}
+	e.prev = img
+
+	// fcTL: sequence, size, offset, delay as a fraction of a second, no
+	// disposal and replacing the covered pixels
+	fctl := binary.BigEndian.AppendUint32(nil, e.nextSeq())
+	for _, v := range []int{bounds.Dx(), bounds.Dy(), bounds.Min.X, bounds.Min.Y} {
+		fctl = binary.BigEndian.AppendUint32(fctl, uint32(v))
+	}
+	ms := min(d.Milliseconds(), 0xffff)
+	fctl = binary.BigEndian.AppendUint16(fctl, uint16(ms))
+	fctl = binary.BigEndian.AppendUint16(fctl, 1000)
+	fctl = append(fctl, 0, 0)
+	if err := writeChunk(e.f, "fcTL", fctl); err != nil {
+		return err
+	}
+
+	// The first frame is the default image and keeps its IDAT chunks
+	for _, c := range chunks {
+		if c.typ != "IDAT" {
+			continue
+		}
+		if e.frames == 0 {
+			err = writeChunk(e.f, "IDAT", c.data)
+		} else {
+			err = writeChunk(e.f, "fdAT", append(binary.BigEndian.AppendUint32(nil, e.nextSeq()), c.data...))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	e.frames++
+	return nil
+}
+
+// writeHeader writes the PNG signature, the IHDR of the first frame and an
+// acTL chunk whose frame count is filled in by Close
+func (e *apngEncoder) writeHeader(chunks []pngChunk) error {
+	if _, err := e.f.WriteString("\x89PNG\r\n\x1a\n"); err != nil {
+		return err
+	}
+	if len(chunks) == 0 || chunks[0].typ != "IHDR" {
+		return fmt.Errorf("invalid PNG frame")
+	}
+	if err := writeChunk(e.f, "IHDR", chunks[0].data); err != nil {
+		return err
+	}
+
+	pos, err := e.f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	e.actlPos = pos
+	return writeChunk(e.f, "acTL", make([]byte, 8))
+}
+
+func (e *apngEncoder) nextSeq() uint32 {
+	seq := e.seq
+	e.seq++
+	return seq
+}
+
+func (e *apngEncoder) Close() error {
+	if e.frames == 0 {
+		return discard(e.f)
+	}
+	err := writeChunk(e.f, "IEND", nil)
+	if err == nil {
+		// Fill in the number of frames, looping forever
+		if _, err = e.f.Seek(e.actlPos, io.SeekStart); err == nil {
+			err = writeChunk(e.f, "acTL", binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, e.frames), 0))
+		}
+	}
+	if cerr := e.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// pngChunk is a chunk of a PNG stream
+type pngChunk struct {
+	typ  string
+	data []byte
+}
+
+// readChunks splits a PNG stream into its chunks
+func readChunks(b []byte) ([]pngChunk, error) {
+	if len(b) < 8 {
+		return nil, fmt.Errorf("invalid PNG frame")
+	}
+	b = b[8:]
+
+	var chunks []pngChunk
+	for len(b) >= 12 {
+		n := int(binary.BigEndian.Uint32(b))
+		if len(b) < 12+n {
+			return nil, fmt.Errorf("truncated PNG chunk")
+		}
+		chunks = append(chunks, pngChunk{typ: string(b[4:8]), data: b[8 : 8+n]})
+		b = b[12+n:]
+	}
+	return chunks, nil
+}
+
+// writeChunk writes a PNG chunk with its length and checksum
+func writeChunk(w io.Writer, typ string, data []byte) error {
+	buf := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	buf = append(buf, typ...)
+	buf = append(buf, data...)
+	buf = binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf[4:]))
+	_, err := w.Write(buf)
+	return err
+}
@@ -0,0 +1,46 @@
+package recording
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Encoder writes frames to a recording file
+type Encoder interface {
+	// WriteFrame appends a frame shown for the given duration. All frames have
+	// the same size and are opaque.
+	WriteFrame(img *image.RGBA, d time.Duration) error
+	// Close finishes the file
+	Close() error
+}
+
+// Formats lists the supported output file extensions
+var Formats = []string{".gif", ".apng", ".png", ".mp4"}
+
+// Create creates an encoder writing to path in the format given by its
+// extension: .gif, .apng or .png for an animated PNG, or .mp4, which is encoded
+// by ffmpeg at fps frames per second
+func Create(path string, fps float64) (Encoder, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".gif":
+		return createGIF(path)
+	case ".apng", ".png":
+		return createAPNG(path)
+	case ".mp4":
+		return createFFmpeg(path, fps)
+	default:
+		return nil, fmt.Errorf("unsupported output format %q: must be one of %s", ext, strings.Join(Formats, ", "))
+	}
+}
+
+// discard closes and removes a file no frame was written to, since an
+// animation without frames is not a valid image
+func discard(f *os.File) error {
+	f.Close()
+	os.Remove(f.Name())
+	return fmt.Errorf("no frames were recorded")
+}
@@ -0,0 +1,141 @@
+package recording
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testFrames returns frames of a red square moving over a white background
+func testFrames(n int) []*image.RGBA {
+	frames := make([]*image.RGBA, n)
+	for i := range frames {
+		img := image.NewRGBA(image.Rect(0, 0, 32, 24))
+		for p := 0; p < len(img.Pix); p++ {
+			img.Pix[p] = 0xff
+		}
+		for y := 4; y < 12; y++ {
+			for x := 4 * i; x < 4*i+8; x++ {
+				img.SetRGBA(x, y, color.RGBA{0xff, 0, 0, 0xff})
+			}
+		}
+		frames[i] = img
+	}
+	return frames
+}
+
+func TestEncoders(t *testing.T) {
+	frames := testFrames(3)
+	tests := []struct {
+		ext    string
+		decode func(t *testing.T, f *os.File)
+	}{
+		{".png", func(t *testing.T, f *os.File) {
+			img, err := png.Decode(f)
+			if err != nil {
+				t.Fatalf("png.Decode() error = %v", err)
+			}
+			if img.Bounds() != frames[0].Rect {
+				t.Errorf("bounds = %v, want %v", img.Bounds(), frames[0].Rect)
+			}
+			if r, g, b, _ := img.At(5, 5).RGBA(); r>>8 != 0xff || g != 0 || b != 0 {
+				t.Errorf("pixel (5,5) = %v, want red", img.At(5, 5))
+			}
+
+			data, err := os.ReadFile(f.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+			chunks, err := readChunks(data)
+			if err != nil {
+				t.Fatalf("readChunks() error = %v", err)
+			}
+			counts := map[string]int{}
+			for _, c := range chunks {
+				counts[c.typ]++
+				if c.typ == "acTL" {
+					if n := binary.BigEndian.Uint32(c.data); n != uint32(len(frames)) {
+						t.Errorf("acTL frame count = %d, want %d", n, len(frames))
+					}
+				}
+			}
+			if counts["fcTL"] != len(frames) || counts["acTL"] != 1 || counts["IEND"] != 1 {
+				t.Errorf("chunks = %v, want one acTL, %d fcTL and one IEND", counts, len(frames))
+			}
+		}},
+		{".gif", func(t *testing.T, f *os.File) {
+			g, err := gif.DecodeAll(f)
+			if err != nil {
+				t.Fatalf("gif.DecodeAll() error = %v", err)
+			}
+			if len(g.Image) != len(frames) {
+				t.Fatalf("frames = %d, want %d", len(g.Image), len(frames))
+			}
+			if g.Config.Width != 32 || g.Config.Height != 24 {
+				t.Errorf("size = %dx%d, want 32x24", g.Config.Width, g.Config.Height)
+			}
+			for i, d := range g.Delay {
+				if d != 10 {
+					t.Errorf("delay of frame %d = %d, want 10", i, d)
+				}
+			}
+			if r, gr, b, _ := g.Image[0].At(5, 5).RGBA(); r>>8 != 0xff || gr != 0 || b != 0 {
+				t.Errorf("pixel (5,5) = %v, want red", g.Image[0].At(5, 5))
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out"+tt.ext)
+			enc, err := Create(path, 10)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			for _, img := range frames {
+				if err := enc.WriteFrame(img, 100*time.Millisecond); err != nil {
+					t.Fatalf("WriteFrame() error = %v", err)
+				}
+			}
+			if err := enc.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			tt.decode(t, f)
+		})
+	}
+}
+
+func TestEncoderWithoutFrames(t *testing.T) {
+	for _, ext := range []string{".png", ".gif"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out"+ext)
+			enc, err := Create(path, 10)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if err := enc.Close(); err == nil {
+				t.Error("Close() error = nil, want an error")
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("file exists after Close() without frames")
+			}
+		})
+	}
+}
+
+func TestCreateUnsupportedFormat(t *testing.T) {
+	if _, err := Create(filepath.Join(t.TempDir(), "out.avi"), 10); err == nil {
+		t.Error("Create() error = nil, want an error")
+	}
+}
@@ -0,0 +1,91 @@
+package recording
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ffmpegEncoder pipes raw frames into a local ffmpeg that encodes them as
+// H.264. The video has a constant frame rate, so frames are repeated for as
+// long as they are shown.
+type ffmpegEncoder struct {
+	path   string
+	fps    float64
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr bytes.Buffer
+
+	elapsed time.Duration
+	written int
+}
+
+func createFFmpeg(path string, fps float64) (*ffmpegEncoder, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, fmt.Errorf("mp4 output requires ffmpeg, record to .gif or .apng instead: %w", err)
+	}
+	return &ffmpegEncoder{path: path, fps: fps}, nil
+}
+
+// start runs ffmpeg for frames of the given size
+func (e *ffmpegEncoder) start(width, height int) error {
+	e.cmd = exec.Command("ffmpeg", "-hide_banner", "-loglevel", "error", "-y",
+		"-f", "rawvideo", "-pix_fmt", "rgba", "-s", fmt.Sprintf("%dx%d", width, height),
+		"-framerate", fmt.Sprintf("%g", e.fps), "-i", "-",
+		// H.264 with 4:2:0 chroma needs even dimensions
+		"-vf", "pad=ceil(iw/2)*2:ceil(ih/2)*2",
+		"-c:v", "libx264", "-pix_fmt", "yuv420p", "-movflags", "+faststart",
+		e.path)
+	e.cmd.Stderr = &e.stderr
+
+	stdin, err := e.cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	e.stdin = stdin
+	if err := e.cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+	return nil
+}
+
+func (e *ffmpegEncoder) WriteFrame(img *image.RGBA, d time.Duration) error {
+	if e.cmd == nil {
+		if err := e.start(img.Rect.Dx(), img.Rect.Dy()); err != nil {
+			return err
+		}
+	}
+
+	e.elapsed += d
+	total := int(math.Round(e.elapsed.Seconds() * e.fps))
+	for ; e.written < total; e.written++ {
+		if _, err := e.stdin.Write(img.Pix); err != nil {
+			return e.failed(err)
+		}
+	}
+	return nil
+}
+
+func (e *ffmpegEncoder) Close() error {
+	if e.cmd == nil {
+		return nil
+	}
+	e.stdin.Close()
+	if err := e.cmd.Wait(); err != nil {
+		return e.failed(err)
+	}
+	return nil
+}
+
+// failed adds the output of ffmpeg to an error
+func (e *ffmpegEncoder) failed(err error) error {
+	if msg := strings.TrimSpace(e.stderr.String()); msg != "" {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, msg)
+	}
+	return fmt.Errorf("ffmpeg failed: %w", err)
+}
@@ -0,0 +1,172 @@
+package recording
+
+import (
+	"bufio"
+	"compress/lzw"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"os"
+	"time"
+)
+
+// gifEncoder streams an animated GIF to a file. Frames after the first only
+// contain the rectangle that changed, quantized to the web-safe Plan 9 palette.
+type gifEncoder struct {
+	f    *os.File
+	w    *bufio.Writer
+	prev *image.RGBA
+
+	// elapsed and written are the total frame time and the time written as
+	// centisecond delays, so rounding errors do not accumulate
+	elapsed time.Duration
+	written int
+
+	colorTable []byte
+	indices    map[color.RGBA]uint8
+}
+
+func createGIF(path string) (*gifEncoder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	table := make([]byte, 0, 3*len(palette.Plan9))
+	for _, c := range palette.Plan9 {
+		r, g, b, _ := c.RGBA()
+		table = append(table, byte(r>>8), byte(g>>8), byte(b>>8))
+	}
+	return &gifEncoder{
+		f:          f,
+		w:          bufio.NewWriter(f),
+		colorTable: table,
+		indices:    make(map[color.RGBA]uint8),
+	}, nil
+}
+
+func (e *gifEncoder) WriteFrame(img *image.RGBA, d time.Duration) error {
+	bounds := img.Rect
+	if e.prev == nil {
+		e.writeHeader(bounds.Dx(), bounds.Dy())
+	} else if changed := changedBounds(e.prev, img); !changed.Empty() {
+		bounds = changed
+	} else {
+		// Frames identical to the previous one still need a delay
+		bounds = image.Rect(0, 0, 1, 1)
+	}
+	e.prev = img
+
+	e.elapsed += d
+	delay := max(2, int(e.elapsed/(10*time.Millisecond))-e.written)
+	e.written += delay
+
+	// Graphic control extension: keep the previous frame under this one
+	e.w.Write([]byte{0x21, 0xf9, 4, 1 << 2})
+	binary.Write(e.w, binary.LittleEndian, uint16(delay))
+	e.w.Write([]byte{0, 0})
+
+	// Image descriptor with a local 256 colour table
+	e.w.WriteByte(0x2c)
+	for _, v := range []int{bounds.Min.X, bounds.Min.Y, bounds.Dx(), bounds.Dy()} {
+		binary.Write(e.w, binary.LittleEndian, uint16(v))
+	}
+	e.w.WriteByte(0x80 | 7)
+	e.w.Write(e.colorTable)
+
+	// LZW compressed indices in sub-blocks of at most 255 bytes
+	e.w.WriteByte(8)
+	blocks := &gifBlockWriter{w: e.w}
+	lw := lzw.NewWriter(blocks, lzw.LSB, 8)
+	row := make([]byte, bounds.Dx())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			o := img.PixOffset(x, y)
+			row[x-bounds.Min.X] = e.index(color.RGBA{img.Pix[o], img.Pix[o+1], img.Pix[o+2], 255})
+		}
+		if _, err := lw.Write(row); err != nil {
+			return err
+		}
+	}
+	if err := lw.Close(); err != nil {
+		return err
+	}
+	if err := blocks.close(); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// index returns the palette index closest to c
+func (e *gifEncoder) index(c color.RGBA) uint8 {
+	i, ok := e.indices[c]
+	if !ok {
+		i = uint8(color.Palette(palette.Plan9).Index(c))
+		e.indices[c] = i
+	}
+	return i
+}
+
+// writeHeader writes the GIF header, the logical screen and an infinite loop
+func (e *gifEncoder) writeHeader(width, height int) {
+	e.w.WriteString("GIF89a")
+	binary.Write(e.w, binary.LittleEndian, uint16(width))
+	binary.Write(e.w, binary.LittleEndian, uint16(height))
+	e.w.Write([]byte{0, 0, 0})
+
+	e.w.Write([]byte{0x21, 0xff, 11})
+	e.w.WriteString("NETSCAPE2.0")
+	e.w.Write([]byte{3, 1, 0, 0, 0})
+}
+
+func (e *gifEncoder) Close() error {
+	if e.prev == nil {
+		return discard(e.f)
+	}
+	e.w.WriteByte(0x3b)
+	err := e.w.Flush()
+	if cerr := e.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// gifBlockWriter splits data into GIF sub-blocks
+type gifBlockWriter struct {
+	w   *bufio.Writer
+	buf []byte
+}
+
+func (b *gifBlockWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		take := min(255-len(b.buf), len(p))
+		b.buf = append(b.buf, p[:take]...)
+		p = p[take:]
+		if len(b.buf) == 255 {
+			if err := b.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (b *gifBlockWriter) flush() error {
+	if len(b.buf) == 0 {
+		return nil
+	}
+	b.w.WriteByte(byte(len(b.buf)))
+	_, err := b.w.Write(b.buf)
+	b.buf = b.buf[:0]
+	return err
+}
+
+// close writes the remaining data and the block terminator
+func (b *gifBlockWriter) close() error {
+	if err := b.flush(); err != nil {
+		return err
+	}
+	return b.w.WriteByte(0)
+}
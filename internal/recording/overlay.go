@@ -0,0 +1,106 @@
+package recording
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/dmahlow/desktop-automation/internal/imaging"
+)
+
+// rippleDuration is how long a click ripple is visible
+const rippleDuration = 500 * time.Millisecond
+
+// ripple is an expanding ring drawn where a mouse button was pressed
+type ripple struct {
+	at     image.Point
+	button string
+	start  time.Time
+}
+
+// cursorShape is an arrow pointer with its hot spot at the top left: X is the
+// outline and . the fill
+var cursorShape = []string{
+	"X",
+	"XX",
+	"X.X",
+	"X..X",
+	"X...X",
+	"X....X",
+	"X.....X",
+	"X......X",
+	"X.......X",
+	"X........X",
+	"X.....XXXXX",
+	"X..X..X",
+	"X.X X..X",
+	"XX  X..X",
+	"X    X..X",
+	"     X..X",
+	"      XX",
+}
+
+// drawCursor draws the arrow pointer with its tip at p, scaled for the frame
+func drawCursor(img *image.RGBA, p image.Point, scale float64) {
+	size := max(1, int(math.Round(scale)))
+	for row, line := range cursorShape {
+		for col, c := range line {
+			var fill color.RGBA
+			switch c {
+			case 'X':
+				fill = color.RGBA{0, 0, 0, 255}
+			case '.':
+				fill = color.RGBA{255, 255, 255, 255}
+			default:
+				continue
+			}
+			for dy := 0; dy < size; dy++ {
+				for dx := 0; dx < size; dx++ {
+					x, y := p.X+col*size+dx, p.Y+row*size+dy
+					if (image.Point{x, y}).In(img.Rect) {
+						img.SetRGBA(x, y, fill)
+					}
+				}
+			}
+		}
+	}
+}
+
+// drawRipple draws a click ripple of the given age centred on p and reports
+// whether it is still visible
+func drawRipple(img *image.RGBA, p image.Point, button string, age time.Duration, scale float64) bool {
+	if age < 0 {
+		age = 0
+	}
+	if age >= rippleDuration {
+		return false
+	}
+
+	// The ring expands and fades out; clicks other than left are blue
+	progress := float64(age) / float64(rippleDuration)
+	radius := (6 + 24*progress) * scale
+	width := 3 * scale
+	alpha := 0.8 * (1 - progress)
+	ring := color.RGBA{255, 64, 32, 255}
+	if button != "" && button != "left" {
+		ring = color.RGBA{32, 128, 255, 255}
+	}
+
+	outer := int(math.Ceil(radius))
+	bounds := image.Rect(p.X-outer, p.Y-outer, p.X+outer+1, p.Y+outer+1).Intersect(img.Rect)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			d := math.Hypot(float64(x-p.X), float64(y-p.Y))
+			if d > radius || d < radius-width {
+				continue
+			}
+			o := img.PixOffset(x, y)
+			px := img.Pix[o : o+3 : o+3]
+			px[0] = imaging.Blend(px[0], ring.R, alpha)
+			px[1] = imaging.Blend(px[1], ring.G, alpha)
+			px[2] = imaging.Blend(px[2], ring.B, alpha)
+		}
+	}
+	return true
+}
@@ -0,0 +1,247 @@
+// Package recording records the screen to animated GIF, APNG or video files,
+// optionally drawing the cursor and ripples where the mouse was clicked.
+package recording
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"log"
+	"time"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+)
+
+// Options configures a recording
+type Options struct {
+	// FPS is the number of frames captured per second
+	FPS float64
+	// Region is the recorded part of the screen in logical coordinates; empty
+	// records the whole screen
+	Region image.Rectangle
+	// Duration stops the recording after this long; zero records until the
+	// context is cancelled
+	Duration time.Duration
+	// Cursor draws the mouse cursor into the frames
+	Cursor bool
+	// Clicks draws a ripple where a mouse button was pressed. Clicks are only
+	// visible if the backend implements automation.ClickObserver; otherwise a
+	// warning is logged.
+	Clicks bool
+}
+
+// Stats summarizes a finished recording
+type Stats struct {
+	Frames   int
+	Duration time.Duration
+}
+
+// Record captures frames until ctx is cancelled or the duration has passed and
+// writes them to enc, which is closed at the end. Cancelling ctx is the normal
+// way to end a recording and does not cause an error.
+func Record(ctx context.Context, opts Options, enc Encoder) (Stats, error) {
+	if opts.FPS <= 0 || opts.FPS > 60 {
+		enc.Close()
+		return Stats{}, fmt.Errorf("fps must be between 0 and 60, got %g", opts.FPS)
+	}
+
+	r, err := newRecorder(ctx, opts)
+	if err != nil {
+		enc.Close()
+		return Stats{}, err
+	}
+	stats, err := r.run(ctx, enc)
+	if cerr := enc.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("failed to finish recording: %w", cerr)
+	}
+	return stats, err
+}
+
+// recorder holds the state of a running recording
+type recorder struct {
+	opts     Options
+	region   image.Rectangle
+	observer automation.ClickObserver
+	clickSeq uint64
+	ripples  []ripple
+}
+
+func newRecorder(ctx context.Context, opts Options) (*recorder, error) {
+	b := automation.CurrentBackend()
+	r := &recorder{opts: opts, region: opts.Region}
+
+	if r.region.Empty() {
+		w, h, err := b.ScreenSize(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get screen size: %w", err)
+		}
+		r.region = image.Rect(0, 0, w, h)
+	}
+
+	if opts.Clicks {
+		observer, ok := b.(automation.ClickObserver)
+		if !ok {
+			log.Printf("warning: clicks are not drawn: the backend does not report clicks, run a daemon or use --backend x11")
+			return r, nil
+		}
+		// Only clicks made after the recording started are drawn
+		_, seq, err := observer.ClicksSince(ctx, 0)
+		if err != nil {
+			log.Printf("warning: clicks are not drawn: %v", err)
+			return r, nil
+		}
+		r.observer, r.clickSeq = observer, seq
+	}
+	return r, nil
+}
+
+func (r *recorder) run(ctx context.Context, enc Encoder) (Stats, error) {
+	interval := time.Duration(float64(time.Second) / r.opts.FPS)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var deadline <-chan time.Time
+	if r.opts.Duration > 0 {
+		timer := time.NewTimer(r.opts.Duration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	var (
+		stats        Stats
+		canvas       *image.RGBA
+		pending      *image.RGBA
+		pendingSince time.Time
+		start        = time.Now()
+	)
+
+	// flush writes the pending frame, shown until now
+	flush := func(now time.Time) error {
+		if pending == nil {
+			return nil
+		}
+		if err := enc.WriteFrame(pending, now.Sub(pendingSince)); err != nil {
+			return fmt.Errorf("failed to write frame: %w", err)
+		}
+		stats.Frames++
+		return nil
+	}
+
+loop:
+	for {
+		img, err := automation.CaptureImage(ctx, r.region)
+		now := time.Now()
+		if err != nil {
+			if ctx.Err() != nil {
+				break loop
+			}
+			return stats, fmt.Errorf("failed to capture screen: %w", err)
+		}
+
+		// All frames have the size of the first, even if the screen is resized
+		if canvas == nil {
+			canvas = image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+		}
+		frame := image.NewRGBA(canvas.Rect)
+		draw.Draw(frame, frame.Rect, img, img.Bounds().Min, draw.Src)
+		r.decorate(ctx, frame, now)
+
+		// Unchanged frames extend the display time of the pending one
+		if pending == nil || !sameImage(pending, frame) {
+			if err := flush(now); err != nil {
+				return stats, err
+			}
+			pending, pendingSince = frame, now
+		}
+
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-deadline:
+			break loop
+		case <-ticker.C:
+		}
+	}
+
+	end := time.Now()
+	if pending != nil && end.Sub(pendingSince) < interval {
+		end = pendingSince.Add(interval)
+	}
+	if err := flush(end); err != nil {
+		return stats, err
+	}
+	if stats.Frames == 0 {
+		return stats, errors.New("no frames were captured")
+	}
+	stats.Duration = end.Sub(start)
+	return stats, nil
+}
+
+// decorate draws the click ripples and the cursor into a frame
+func (r *recorder) decorate(ctx context.Context, frame *image.RGBA, now time.Time) {
+	if !r.opts.Cursor && r.observer == nil {
+		return
+	}
+
+	// Overlays are positioned in logical coordinates and drawn in frame pixels
+	scale := float64(frame.Rect.Dx()) / float64(r.region.Dx())
+	toFrame := func(x, y int) image.Point {
+		return image.Pt(int(float64(x-r.region.Min.X)*scale), int(float64(y-r.region.Min.Y)*scale))
+	}
+
+	if r.observer != nil {
+		if clicks, seq, err := r.observer.ClicksSince(ctx, r.clickSeq); err == nil {
+			r.clickSeq = seq
+			for _, c := range clicks {
+				r.ripples = append(r.ripples, ripple{at: image.Pt(c.X, c.Y), button: c.Button, start: c.Time})
+			}
+		}
+		active := r.ripples[:0]
+		for _, rp := range r.ripples {
+			if drawRipple(frame, toFrame(rp.at.X, rp.at.Y), rp.button, now.Sub(rp.start), scale) {
+				active = append(active, rp)
+			}
+		}
+		r.ripples = active
+	}
+
+	if r.opts.Cursor {
+		if x, y, err := automation.CurrentBackend().MousePosition(ctx); err == nil {
+			drawCursor(frame, toFrame(x, y), scale)
+		}
+	}
+}
+
+// sameImage reports whether two frames of the same size have identical pixels
+func sameImage(a, b *image.RGBA) bool {
+	return bytes.Equal(a.Pix, b.Pix)
+}
+
+// changedBounds returns the smallest rectangle containing all pixels that
+// differ between two frames of the same size
+func changedBounds(prev, cur *image.RGBA) image.Rectangle {
+	var changed image.Rectangle
+	w := cur.Rect.Dx()
+	for y := 0; y < cur.Rect.Dy(); y++ {
+		row := y * cur.Stride
+		a, b := prev.Pix[row:row+w*4], cur.Pix[row:row+w*4]
+		if bytes.Equal(a, b) {
+			continue
+		}
+		minX, maxX := -1, -1
+		for x := 0; x < w; x++ {
+			i := x * 4
+			if a[i] != b[i] || a[i+1] != b[i+1] || a[i+2] != b[i+2] || a[i+3] != b[i+3] {
+				if minX < 0 {
+					minX = x
+				}
+				maxX = x
+			}
+		}
+		changed = changed.Union(image.Rect(minX, y, maxX+1, y+1))
+	}
+	return changed
+}
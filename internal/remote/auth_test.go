@@ -0,0 +1,48 @@
+package remote
+
+import "testing"
+
+func TestCheckAuthorization(t *testing.T) {
+	const token = "s3cret"
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"correct token", "Bearer s3cret", true},
+		{"missing header", "", false},
+		{"wrong token", "Bearer s3cre", false},
+		{"token with suffix", "Bearer s3cret2", false},
+		{"other scheme", "Basic s3cret", false},
+		{"lower case scheme", "bearer s3cret", false},
+		{"token without scheme", "s3cret", false},
+		{"empty token", "Bearer ", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckAuthorization(tt.header, token); got != tt.want {
+				t.Errorf("CheckAuthorization(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateToken(t *testing.T) {
+	a, err := GenerateToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := GenerateToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 48 {
+		t.Errorf("token %q has %d characters, want 48", a, len(a))
+	}
+	if a == b {
+		t.Error("two generated tokens are equal")
+	}
+	if !CheckAuthorization("Bearer "+a, a) {
+		t.Error("a generated token is not accepted")
+	}
+}
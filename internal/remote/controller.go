@@ -0,0 +1,378 @@
+// Package remote implements the actions offered by the network APIs on top of
+// the automation package, so the REST and gRPC servers validate input and
+// convert coordinates the same way.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+)
+
+// InputError reports invalid input from a client, as opposed to a failure of the automation itself
+type InputError struct {
+	err error
+}
+
+func (e *InputError) Error() string { return e.err.Error() }
+func (e *InputError) Unwrap() error { return e.err }
+
+// invalid returns an InputError
+func invalid(format string, args ...any) error {
+	return &InputError{err: fmt.Errorf(format, args...)}
+}
+
+// IsInputError reports whether err was caused by invalid client input
+func IsInputError(err error) bool {
+	var inputErr *InputError
+	return errors.As(err, &inputErr)
+}
+
+// TargetRequest selects a position either by x and y or by a target expression
+type TargetRequest struct {
+	X          *int   `json:"x,omitempty" doc:"X coordinate, required unless target is given"`
+	Y          *int   `json:"y,omitempty" doc:"Y coordinate, required unless target is given"`
+	Target     string `json:"target,omitempty" doc:"Target expression used instead of x and y: 'X,Y' where each coordinate is absolute (100), relative to the cursor (+50, -20) or a percentage of the frame (50%), or an anchor such as '@center' or '@cursor'"`
+	RelativeTo string `json:"relative_to,omitempty" enum:"screen,window" doc:"Frame for percentages and anchors (default: screen)"`
+}
+
+// MoveRequest describes a mouse movement
+type MoveRequest struct {
+	TargetRequest
+	Smooth    bool    `json:"smooth,omitempty" doc:"Animate the movement; implied by path"`
+	Duration  float64 `json:"duration,omitempty" doc:"Duration of smooth movement in seconds (default: 1.0)"`
+	Path      string  `json:"path,omitempty" enum:"linear,bezier,wind" doc:"Trajectory shape (default: linear)"`
+	Easing    string  `json:"easing,omitempty" enum:"in-out,out,none" doc:"Velocity profile (default: in-out)"`
+	Overshoot bool    `json:"overshoot,omitempty" doc:"Overshoot the target slightly and correct back"`
+	Jitter    float64 `json:"jitter,omitempty" doc:"Standard deviation in pixels of random noise added to each step"`
+	Seed      int64   `json:"seed,omitempty" doc:"Seed for the randomized trajectory (default: random)"`
+}
+
+// ClickRequest describes a mouse click
+type ClickRequest struct {
+	TargetRequest
+	Button string `json:"button,omitempty" enum:"left,right" doc:"Mouse button (default: left)"`
+	Double bool   `json:"double,omitempty" doc:"Double click instead of a single click"`
+}
+
+// TypeRequest describes text to type
+type TypeRequest struct {
+	Text    string  `json:"text" doc:"Text to type"`
+	DelayMs int     `json:"delay_ms,omitempty" doc:"Fixed delay in milliseconds between characters"`
+	Profile string  `json:"profile,omitempty" enum:"fast,hesitant,natural,uniform" doc:"Human-like typing cadence profile"`
+	WPM     float64 `json:"wpm,omitempty" doc:"Typing speed in words per minute for the profile"`
+	Seed    int64   `json:"seed,omitempty" doc:"Seed for reproducible typing cadence (default: random)"`
+}
+
+// KeyRequest describes a key press
+type KeyRequest struct {
+	Key    string `json:"key" doc:"Key or combination joined with '+', e.g. 'enter' or 'ctrl+shift+t'"`
+	Action string `json:"action,omitempty" enum:"press,down,up" doc:"Press and release (default), hold down or release the keys"`
+}
+
+// ScrollRequest describes a scroll of the mouse wheel
+type ScrollRequest struct {
+	DX int `json:"dx,omitempty" doc:"Horizontal steps; positive scrolls right"`
+	DY int `json:"dy,omitempty" doc:"Vertical steps; positive scrolls down"`
+}
+
+// Region is a rectangle on the screen; an empty region means the full screen
+type Region struct {
+	X      int `json:"x,omitempty" doc:"Left edge of the region"`
+	Y      int `json:"y,omitempty" doc:"Top edge of the region"`
+	Width  int `json:"width,omitempty" doc:"Width of the region (default: full screen)"`
+	Height int `json:"height,omitempty" doc:"Height of the region (default: full screen)"`
+}
+
+// Position is a point on the screen
+type Position struct {
+	X int `json:"x" doc:"X coordinate"`
+	Y int `json:"y" doc:"Y coordinate"`
+}
+
+// ScreenInfo describes the main display
+type ScreenInfo struct {
+	Width  int     `json:"width" doc:"Screen width in the server's coordinate space"`
+	Height int     `json:"height" doc:"Screen height in the server's coordinate space"`
+	Scale  float64 `json:"scale" doc:"Physical pixels per logical unit"`
+	Coords string  `json:"coords" doc:"Coordinate space of all coordinates in the API"`
+}
+
+// Controller performs remote requests with coordinates in a fixed coordinate
+// space. It serializes all actions so input from concurrent clients, including
+// clients of different servers sharing the controller, never interleaves.
+type Controller struct {
+	coords automation.CoordSystem
+	mu     sync.Mutex
+}
+
+// NewController creates a controller taking and returning coordinates in the space of coords
+func NewController(coords automation.CoordSystem) *Controller {
+	return &Controller{coords: coords}
+}
+
+// Coords returns the coordinate system of the controller
+func (c *Controller) Coords() automation.CoordSystem {
+	return c.coords
+}
+
+// resolve returns the logical coordinates selected by a target request
+func (c *Controller) resolve(req TargetRequest) (int, int, error) {
+	frame := automation.FrameScreen
+	if req.RelativeTo != "" {
+		var err error
+		if frame, err = automation.ParseFrame(req.RelativeTo); err != nil {
+			return 0, 0, invalid("%v", err)
+		}
+	}
+
+	var target automation.Target
+	var err error
+	switch {
+	case req.Target != "":
+		target, err = automation.ParseTarget(req.Target)
+	case req.X == nil || req.Y == nil:
+		return 0, 0, invalid("either target or both x and y are required")
+	case *req.X < 0:
+		return 0, 0, invalid("x coordinate cannot be negative: %d", *req.X)
+	case *req.Y < 0:
+		return 0, 0, invalid("y coordinate cannot be negative: %d", *req.Y)
+	default:
+		target, err = automation.ParseTarget(strconv.Itoa(*req.X), strconv.Itoa(*req.Y))
+	}
+	if err != nil {
+		return 0, 0, invalid("%v", err)
+	}
+
+	x, y, err := target.Resolve(c.coords, frame)
+	if err != nil {
+		return 0, 0, err
+	}
+	if x < 0 || y < 0 {
+		return 0, 0, invalid("target resolves to negative coordinates (%d, %d)", x, y)
+	}
+	return x, y, nil
+}
+
+// cursorPosition returns the cursor position in the controller's coordinate space
+func (c *Controller) cursorPosition() Position {
+	x, y := c.coords.FromLogical(automation.GetPosition())
+	return Position{X: x, Y: y}
+}
+
+// Move moves the cursor and returns its new position
+func (c *Controller) Move(ctx context.Context, req MoveRequest) (Position, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	x, y, err := c.resolve(req.TargetRequest)
+	if err != nil {
+		return Position{}, err
+	}
+
+	if !req.Smooth && req.Path == "" {
+		if err := automation.Move(ctx, x, y); err != nil {
+			return Position{}, err
+		}
+		return c.cursorPosition(), nil
+	}
+
+	opts := automation.TrajectoryOptions{
+		Duration:  req.Duration,
+		Overshoot: req.Overshoot,
+		Jitter:    req.Jitter,
+		Seed:      req.Seed,
+	}
+	if opts.Duration == 0 {
+		opts.Duration = 1.0
+	}
+	if req.Path != "" {
+		if opts.Path, err = automation.ParsePathKind(req.Path); err != nil {
+			return Position{}, invalid("%v", err)
+		}
+	}
+	if req.Easing != "" {
+		if opts.Easing, err = automation.ParseEasing(req.Easing); err != nil {
+			return Position{}, invalid("%v", err)
+		}
+	}
+
+	if err := automation.MoveAlongPath(ctx, x, y, opts); err != nil {
+		return Position{}, err
+	}
+	return c.cursorPosition(), nil
+}
+
+// Click clicks at a position and returns the cursor position afterwards
+func (c *Controller) Click(ctx context.Context, req ClickRequest) (Position, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	x, y, err := c.resolve(req.TargetRequest)
+	if err != nil {
+		return Position{}, err
+	}
+
+	switch {
+	case req.Button == "right" && req.Double:
+		return Position{}, invalid("double click is only supported with the left button")
+	case req.Button == "right":
+		err = automation.RightClick(ctx, x, y)
+	case req.Button == "" || req.Button == "left":
+		if req.Double {
+			err = automation.DoubleClick(ctx, x, y)
+		} else {
+			err = automation.Click(ctx, x, y)
+		}
+	default:
+		return Position{}, invalid("invalid button '%s': must be left or right", req.Button)
+	}
+	if err != nil {
+		return Position{}, err
+	}
+	return c.cursorPosition(), nil
+}
+
+// Type types text with a fixed delay, a typing profile or instantly
+func (c *Controller) Type(ctx context.Context, req TypeRequest) error {
+	if strings.TrimSpace(req.Text) == "" {
+		return invalid("text cannot be empty or contain only whitespace")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if req.Profile == "" && req.WPM == 0 {
+		if req.DelayMs > 0 {
+			return automation.TypeStringWithDelay(ctx, req.Text, req.DelayMs)
+		}
+		return automation.TypeString(ctx, req.Text)
+	}
+
+	if req.DelayMs > 0 {
+		return invalid("delay_ms cannot be combined with profile or wpm")
+	}
+	name := req.Profile
+	if name == "" {
+		name = "natural"
+	}
+	profile, err := automation.TypingProfileByName(name)
+	if err != nil {
+		return invalid("%v", err)
+	}
+	if req.WPM < 0 {
+		return invalid("wpm must be positive: %.1f", req.WPM)
+	}
+	if req.WPM > 0 {
+		profile.WPM = req.WPM
+	}
+	profile.Seed = req.Seed
+	return automation.TypeWithProfile(ctx, req.Text, profile)
+}
+
+// Key presses, holds or releases a key combination
+func (c *Controller) Key(ctx context.Context, req KeyRequest) error {
+	keys := strings.Split(strings.ToLower(req.Key), "+")
+	for _, key := range keys {
+		if strings.TrimSpace(key) == "" {
+			return invalid("invalid key combination '%s': empty key name", req.Key)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch req.Action {
+	case "", "press":
+		return automation.PressKeyCombo(ctx, keys...)
+	case "down":
+		for _, key := range keys {
+			if err := automation.HoldKey(ctx, key); err != nil {
+				return err
+			}
+		}
+	case "up":
+		// Release in reverse order so modifiers are released last
+		for i := len(keys) - 1; i >= 0; i-- {
+			if err := automation.ReleaseKey(ctx, keys[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		return invalid("invalid action '%s': must be press, down or up", req.Action)
+	}
+	return nil
+}
+
+// Scroll scrolls the mouse wheel
+func (c *Controller) Scroll(ctx context.Context, req ScrollRequest) error {
+	if req.DX == 0 && req.DY == 0 {
+		return invalid("dx or dy must be non-zero")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return automation.Scroll(ctx, req.DX, req.DY)
+}
+
+// Capture captures a region of the screen given in the controller's
+// coordinate space. The image is in physical pixels.
+func (c *Controller) Capture(ctx context.Context, region Region) (image.Image, error) {
+	if region.X < 0 || region.Y < 0 || region.Width < 0 || region.Height < 0 {
+		return nil, invalid("region cannot have negative coordinates or size")
+	}
+
+	var rect image.Rectangle
+	if region.Width > 0 && region.Height > 0 {
+		x0, y0 := c.coords.ToLogical(region.X, region.Y)
+		x1, y1 := c.coords.ToLogical(region.X+region.Width, region.Y+region.Height)
+		rect = image.Rect(x0, y0, x1, y1)
+	} else if region.Width > 0 || region.Height > 0 {
+		return nil, invalid("width and height must be given together")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return automation.CaptureImage(ctx, rect)
+}
+
+// Cursor returns the cursor position
+func (c *Controller) Cursor() Position {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cursorPosition()
+}
+
+// Screen describes the main display
+func (c *Controller) Screen() ScreenInfo {
+	width, height := c.coords.Size()
+	return ScreenInfo{
+		Width:  width,
+		Height: height,
+		Scale:  c.coords.Scale,
+		Coords: c.coords.Space.String(),
+	}
+}
+
+// ActiveWindow returns the bounds of the focused window in the controller's coordinate space
+func (c *Controller) ActiveWindow(ctx context.Context) (Region, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bounds, err := automation.CurrentBackend().ActiveWindowBounds(ctx)
+	if err != nil {
+		return Region{}, err
+	}
+	x0, y0 := c.coords.FromLogical(bounds.Min.X, bounds.Min.Y)
+	x1, y1 := c.coords.FromLogical(bounds.Max.X, bounds.Max.Y)
+	return Region{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}, nil
+}
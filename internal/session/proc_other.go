@@ -0,0 +1,19 @@
+//go:build !unix
+
+package session
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// detach is a no-op, sessions need Xvfb and Unix process groups
+func detach(cmd *exec.Cmd) {}
+
+func signalGroup(pid int, kill bool) error {
+	return fmt.Errorf("sessions are only supported on Unix")
+}
+
+func processAlive(pid int) bool {
+	return false
+}
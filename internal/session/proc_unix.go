@@ -0,0 +1,34 @@
+//go:build unix
+
+package session
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// detach starts cmd in a new session and process group, so it does not receive
+// the terminal's signals and can be stopped together with its children
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// signalGroup sends SIGTERM, or SIGKILL if kill is set, to the process group
+// led by pid
+func signalGroup(pid int, kill bool) error {
+	sig := syscall.SIGTERM
+	if kill {
+		sig = syscall.SIGKILL
+	}
+	if err := syscall.Kill(-pid, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	return nil
+}
+
+// processAlive reports whether a process exists
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
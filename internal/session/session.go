@@ -0,0 +1,380 @@
+// Package session runs headless X11 sessions: an Xvfb server with an optional
+// window manager and startup application. Sessions are recorded in a state
+// directory so they can be listed and stopped from other processes.
+package session
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How long to wait for Xvfb to accept connections and for processes to exit
+const (
+	startTimeout = 10 * time.Second
+	stopTimeout  = 5 * time.Second
+)
+
+// Options configures a new session
+type Options struct {
+	// Name identifies the session; defaults to the display number
+	Name string
+	// Display is the X display such as ":99"; Xvfb picks a free one if empty
+	Display string
+	Width   int
+	Height  int
+	Depth   int
+	// WindowManager and App are shell command lines started on the display
+	WindowManager string
+	App           string
+}
+
+// Session is a running Xvfb server and the processes started on its display
+type Session struct {
+	Name    string    `json:"name"`
+	Display string    `json:"display"`
+	Width   int       `json:"width"`
+	Height  int       `json:"height"`
+	Depth   int       `json:"depth"`
+	XvfbPID int       `json:"xvfbPid"`
+	WMPID   int       `json:"wmPid,omitempty"`
+	AppPID  int       `json:"appPid,omitempty"`
+	LogFile string    `json:"logFile"`
+	Started time.Time `json:"started"`
+
+	// exited holds a channel per process started by this process, closed when
+	// the process has exited and been reaped
+	exited     map[int]chan struct{}
+	xvfbExited chan struct{}
+}
+
+// StateDir returns the directory session state and logs are kept in
+func StateDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "desktop-automation", "sessions")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("desktop-automation-%d", os.Getuid()), "sessions")
+}
+
+// Start launches Xvfb, waits until it accepts connections and then starts the
+// window manager and application. The processes run in their own process
+// groups, so they outlive this process until the session is stopped.
+func Start(ctx context.Context, opts Options) (*Session, error) {
+	if _, err := exec.LookPath("Xvfb"); err != nil {
+		return nil, fmt.Errorf("Xvfb is not installed: %w", err)
+	}
+	if opts.Width <= 0 || opts.Height <= 0 {
+		return nil, fmt.Errorf("invalid resolution %dx%d", opts.Width, opts.Height)
+	}
+	switch opts.Depth {
+	case 8, 16, 24:
+	default:
+		return nil, fmt.Errorf("invalid depth %d: must be 8, 16 or 24", opts.Depth)
+	}
+	if opts.Display != "" {
+		if _, err := displayNumber(opts.Display); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Name != "" {
+		if err := validateName(opts.Name); err != nil {
+			return nil, err
+		}
+		if s, err := Load(opts.Name); err == nil && s.Running() {
+			return nil, fmt.Errorf("session '%s' is already running on display %s", opts.Name, s.Display)
+		}
+	}
+
+	dir := StateDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	log, err := os.CreateTemp(dir, "session-*.log")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	defer log.Close()
+
+	s := &Session{
+		Width:   opts.Width,
+		Height:  opts.Height,
+		Depth:   opts.Depth,
+		LogFile: log.Name(),
+		Started: time.Now(),
+		exited:  make(map[int]chan struct{}),
+	}
+
+	display, err := s.startXvfb(ctx, opts.Display, log)
+	if err != nil {
+		return nil, err
+	}
+	s.Display = display
+	s.Name = opts.Name
+	if s.Name == "" {
+		s.Name = strings.TrimPrefix(display, ":")
+	}
+
+	if opts.WindowManager != "" {
+		if s.WMPID, err = s.startShell(opts.WindowManager, log); err != nil {
+			s.Stop()
+			return nil, fmt.Errorf("failed to start window manager: %w", err)
+		}
+	}
+	if opts.App != "" {
+		if s.AppPID, err = s.startShell(opts.App, log); err != nil {
+			s.Stop()
+			return nil, fmt.Errorf("failed to start application: %w", err)
+		}
+	}
+
+	if err := s.save(); err != nil {
+		s.Stop()
+		return nil, err
+	}
+	return s, nil
+}
+
+// startXvfb starts Xvfb and returns its display once it accepts connections
+func (s *Session) startXvfb(ctx context.Context, display string, log *os.File) (string, error) {
+	// Xvfb writes the display number to -displayfd when it is ready, choosing
+	// a free display itself if none is given
+	r, w, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	var args []string
+	if display != "" {
+		args = append(args, display)
+	}
+	args = append(args,
+		"-screen", "0", fmt.Sprintf("%dx%dx%d", s.Width, s.Height, s.Depth),
+		"-nolisten", "tcp",
+		"-displayfd", "3")
+
+	cmd := exec.Command("Xvfb", args...)
+	cmd.Stdout, cmd.Stderr = log, log
+	cmd.ExtraFiles = []*os.File{w}
+	err = s.start(cmd)
+	w.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to start Xvfb: %w", err)
+	}
+	s.XvfbPID = cmd.Process.Pid
+	s.xvfbExited = s.exited[s.XvfbPID]
+
+	ready := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(r).ReadString('\n')
+		ready <- strings.TrimSpace(line)
+	}()
+
+	select {
+	case n := <-ready:
+		if n == "" {
+			break
+		}
+		return ":" + n, nil
+	case <-time.After(startTimeout):
+	case <-ctx.Done():
+		s.Stop()
+		return "", ctx.Err()
+	}
+	s.Stop()
+	return "", fmt.Errorf("Xvfb did not start, see %s", s.LogFile)
+}
+
+// startShell runs a shell command line on the session's display
+func (s *Session) startShell(command string, log *os.File) (int, error) {
+	cmd := exec.Command("/bin/sh", "-c", command)
+	cmd.Env = s.Environ()
+	cmd.Stdout, cmd.Stderr = log, log
+	if err := s.start(cmd); err != nil {
+		return 0, err
+	}
+	return cmd.Process.Pid, nil
+}
+
+// start starts a command in its own process group and reaps it in the background
+func (s *Session) start(cmd *exec.Cmd) error {
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	s.exited[cmd.Process.Pid] = done
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+	return nil
+}
+
+// Environ returns the environment of this process with DISPLAY set to the
+// session's display
+func (s *Session) Environ() []string {
+	env := []string{"DISPLAY=" + s.Display}
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "DISPLAY=") {
+			env = append(env, kv)
+		}
+	}
+	return env
+}
+
+// Done returns a channel that is closed when the Xvfb server started by this
+// process exits; it is nil for sessions loaded from the state directory
+func (s *Session) Done() <-chan struct{} {
+	return s.xvfbExited
+}
+
+// Running reports whether the session's Xvfb server is still running
+func (s *Session) Running() bool {
+	return s.alive(s.XvfbPID)
+}
+
+// alive reports whether a process of the session is running
+func (s *Session) alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	if done, ok := s.exited[pid]; ok {
+		select {
+		case <-done:
+			return false
+		default:
+			return true
+		}
+	}
+	return processAlive(pid)
+}
+
+// Stop terminates the application, window manager and Xvfb in that order and
+// removes the session and its log from the state directory
+func (s *Session) Stop() error {
+	var errs []error
+	for _, pid := range []int{s.AppPID, s.WMPID, s.XvfbPID} {
+		if err := s.terminate(pid); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.Name != "" {
+		for _, path := range []string{s.stateFile(), s.LogFile} {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// terminate stops a process group, killing it if it does not exit in time
+func (s *Session) terminate(pid int) error {
+	if !s.alive(pid) {
+		return nil
+	}
+	if err := signalGroup(pid, false); err != nil {
+		return fmt.Errorf("failed to stop process %d: %w", pid, err)
+	}
+
+	deadline := time.Now().Add(stopTimeout)
+	for time.Now().Before(deadline) {
+		if !s.alive(pid) {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err := signalGroup(pid, true); err != nil {
+		return fmt.Errorf("failed to kill process %d: %w", pid, err)
+	}
+	return nil
+}
+
+// stateFile returns the path of the session's state file
+func (s *Session) stateFile() string {
+	return filepath.Join(StateDir(), s.Name+".json")
+}
+
+// save writes the session to the state directory
+func (s *Session) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.stateFile(), data, 0o600); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// Load reads a session from the state directory by name or display
+func Load(name string) (*Session, error) {
+	sessions, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		if s.Name == name || s.Display == name {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("no session named '%s'", name)
+}
+
+// List returns all sessions recorded in the state directory, including ones
+// whose Xvfb server has exited, sorted by name
+func List() ([]*Session, error) {
+	entries, err := os.ReadDir(StateDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []*Session
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(StateDir(), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var s Session
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("invalid session file %s: %w", entry.Name(), err)
+		}
+		sessions = append(sessions, &s)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Name < sessions[j].Name })
+	return sessions, nil
+}
+
+// validateName checks that a session name can be used as a state file name
+func validateName(name string) error {
+	if strings.Contains(name, "..") || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid session name '%s': must not contain path separators or \"..\"", name)
+	}
+	return nil
+}
+
+// displayNumber parses a display such as ":99"
+func displayNumber(display string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(display, ":"))
+	if err != nil || !strings.HasPrefix(display, ":") || n < 0 {
+		return 0, fmt.Errorf("invalid display '%s': must be :N", display)
+	}
+	return n, nil
+}
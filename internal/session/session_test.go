@@ -0,0 +1,53 @@
+package session
+
+import "testing"
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"ci", false},
+		{"99", false},
+		{"my-session.1", false},
+		{"../evil", true},
+		{"..", true},
+		{"a/b", true},
+		{`a\b`, true},
+		{"/tmp/x", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDisplayNumber(t *testing.T) {
+	tests := []struct {
+		display string
+		want    int
+		wantErr bool
+	}{
+		{":0", 0, false},
+		{":99", 99, false},
+		{"99", 0, true},
+		{":", 0, true},
+		{":-1", 0, true},
+		{":x", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.display, func(t *testing.T) {
+			got, err := displayNumber(tt.display)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("displayNumber(%q) error = %v, wantErr %v", tt.display, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("displayNumber(%q) = %d, want %d", tt.display, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,60 +1,171 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/dmahlow/desktop-automation/internal/automation"
 )
 
+const (
+	// statusInterval is how often the status bar reads the cursor position
+	statusInterval = 200 * time.Millisecond
+	// focusDelay is how long typing and key presses wait, so the target window
+	// can be focused instead of the terminal running the TUI
+	focusDelay = 2 * time.Second
+)
+
+// Menu entries
+const (
+	actionMove = iota
+	actionClick
+	actionType
+	actionKey
+	actionScroll
+	actionScreenshot
+	actionQuit
+)
+
+var (
+	statusStyle = lipgloss.NewStyle().Reverse(true).Padding(0, 1)
+	errorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+// positionMsg carries the cursor position read for the status bar
+type positionMsg struct {
+	x, y int
+	err  error
+}
+
+// resultMsg carries the outcome of an action that ran in the background
+type resultMsg struct {
+	text string
+	err  error
+}
+
 type model struct {
-	choices  []string
-	cursor   int
-	selected map[int]struct{}
-	state    string
-	input    string
-	result   string
+	ctx     context.Context
+	cs      automation.CoordSystem
+	choices []string
+	prompts []string
+	cursor  int
+	state   string
+	input   textinput.Model
+	running bool
+	width   int
+	x, y    int
+	posErr  error
+	display string
+	result  string
+	failed  bool
 }
 
-func initialModel() model {
+func initialModel(ctx context.Context, cs automation.CoordSystem) model {
+	input := textinput.New()
+	input.Prompt = "> "
+
+	w, h := cs.Size()
+	display := fmt.Sprintf("Screen %dx%d (%s), scale %g", w, h, cs.Space, cs.Scale)
+	if monitors, err := automation.Monitors(ctx); err == nil && len(monitors) > 1 {
+		display += fmt.Sprintf(", %d monitors", len(monitors))
+	}
+
 	return model{
+		ctx: ctx,
+		cs:  cs,
 		choices: []string{
 			"Move Mouse",
 			"Click Mouse",
 			"Type Text",
-			"Get Mouse Position",
-			"Get Screen Size",
+			"Press Key",
+			"Scroll",
+			"Take Screenshot",
 			"Quit",
 		},
-		selected: make(map[int]struct{}),
-		state:    "menu",
+		prompts: []string{
+			"Enter X Y coordinates (e.g., 100 200):",
+			"Enter X Y coordinates (e.g., 100 200):",
+			"Enter text to type:",
+			"Enter a key or combination (e.g., enter, ctrl+s):",
+			"Enter DX DY wheel steps (e.g., 0 5 to scroll down):",
+		},
+		state:   "menu",
+		input:   input,
+		display: display,
 	}
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	return m.readPosition
+}
+
+// readPosition reads the cursor position in the coordinate system's space.
+// It runs as a command, so a slow backend does not block the UI.
+func (m model) readPosition() tea.Msg {
+	x, y, err := automation.CurrentBackend().MousePosition(m.ctx)
+	if err != nil {
+		return positionMsg{err: err}
+	}
+	x, y = m.cs.FromLogical(x, y)
+	return positionMsg{x: x, y: y}
+}
+
+// tick schedules the next refresh of the status bar
+func (m model) tick() tea.Cmd {
+	return tea.Tick(statusInterval, func(time.Time) tea.Msg {
+		return m.readPosition()
+	})
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case positionMsg:
+		m.x, m.y, m.posErr = msg.x, msg.y, msg.err
+		return m, m.tick()
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+	case resultMsg:
+		m.running = false
+		m.result, m.failed = msg.text, msg.err != nil
+		if msg.err != nil {
+			m.result = "Error: " + msg.err.Error()
+		}
+		return m, nil
 	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		// Keys typed or pressed by a running action may land in the terminal
+		if m.running {
+			return m, nil
+		}
 		switch m.state {
 		case "menu":
 			return m.updateMenu(msg)
 		case "input":
 			return m.updateInput(msg)
-		case "result":
-			return m.updateResult(msg)
 		}
 	}
+
+	// Other messages, such as the cursor blink, belong to the text input
+	if m.state == "input" {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
 	return m, nil
 }
 
 func (m model) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
-	case "ctrl+c", "q":
+	case "q":
 		return m, tea.Quit
 	case "up", "k":
 		if m.cursor > 0 {
@@ -66,103 +177,155 @@ func (m model) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "enter", " ":
 		switch m.cursor {
-		case 5: // Quit
+		case actionQuit:
 			return m, tea.Quit
-		case 3: // Get Mouse Position
-			x, y := automation.GetMousePos()
-			m.result = fmt.Sprintf("Mouse position: %d, %d", x, y)
-			m.state = "result"
-		case 4: // Get Screen Size
-			w, h := automation.GetScreenSize()
-			m.result = fmt.Sprintf("Screen size: %dx%d", w, h)
-			m.state = "result"
+		case actionScreenshot:
+			return m.run("")
 		default:
 			m.state = "input"
-			m.input = ""
+			m.input.Reset()
+			return m, m.input.Focus()
 		}
 	}
 	return m, nil
 }
 
 func (m model) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c":
-		return m, tea.Quit
-	case "esc":
+	switch msg.Type {
+	case tea.KeyEsc:
 		m.state = "menu"
-		m.input = ""
-	case "enter":
-		m.result = m.executeAction()
-		m.state = "result"
-	case "backspace":
-		if len(m.input) > 0 {
-			m.input = m.input[:len(m.input)-1]
-		}
-	default:
-		m.input += msg.String()
+		m.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		m.state = "menu"
+		m.input.Blur()
+		return m.run(m.input.Value())
 	}
-	return m, nil
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
 }
 
-func (m model) updateResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c":
-		return m, tea.Quit
-	case "enter", "esc", " ":
-		m.state = "menu"
-		m.result = ""
+// run starts the selected action in the background; its result arrives as a
+// resultMsg and is shown in the status bar
+func (m model) run(input string) (tea.Model, tea.Cmd) {
+	m.running = true
+	m.failed = false
+	m.result = "Running " + m.choices[m.cursor] + "..."
+	if m.cursor == actionType || m.cursor == actionKey {
+		m.result = fmt.Sprintf("Focus the target window, %s in %gs...", strings.ToLower(m.choices[m.cursor]), focusDelay.Seconds())
+	}
+
+	ctx, cs, action := m.ctx, m.cs, m.cursor
+	return m, func() tea.Msg {
+		text, err := executeAction(ctx, cs, action, input)
+		return resultMsg{text: text, err: err}
 	}
-	return m, nil
 }
 
-func (m model) executeAction() string {
-	switch m.cursor {
-	case 0: // Move Mouse
-		coords := strings.Fields(m.input)
-		if len(coords) != 2 {
-			return "Error: Enter X Y coordinates (e.g., 100 200)"
+// executeAction performs a menu action with the text entered for it;
+// coordinates are in the coordinate system's space
+func executeAction(ctx context.Context, cs automation.CoordSystem, action int, input string) (string, error) {
+	switch action {
+	case actionMove, actionClick:
+		x, y, err := parsePair(input)
+		if err != nil {
+			return "", fmt.Errorf("enter X Y coordinates (e.g., 100 200)")
+		}
+		lx, ly := cs.ToLogical(x, y)
+		if action == actionMove {
+			if err := automation.MoveMouse(ctx, lx, ly); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Moved mouse to %d, %d", x, y), nil
+		}
+		if err := automation.Click(ctx, lx, ly); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Clicked at %d, %d", x, y), nil
+
+	case actionType:
+		if input == "" {
+			return "", fmt.Errorf("enter text to type")
+		}
+		if err := wait(ctx, focusDelay); err != nil {
+			return "", err
+		}
+		if err := automation.TypeText(ctx, input); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Typed: %s", input), nil
+
+	case actionKey:
+		combo := strings.ToLower(strings.TrimSpace(input))
+		if combo == "" {
+			return "", fmt.Errorf("enter a key such as enter or ctrl+s")
+		}
+		if err := wait(ctx, focusDelay); err != nil {
+			return "", err
 		}
-		x, err1 := strconv.Atoi(coords[0])
-		y, err2 := strconv.Atoi(coords[1])
-		if err1 != nil || err2 != nil {
-			return "Error: Invalid coordinates"
+		if err := automation.PressKeyCombo(ctx, strings.Split(combo, "+")...); err != nil {
+			return "", err
 		}
-		automation.MoveMouse(x, y)
-		return fmt.Sprintf("Moved mouse to %d, %d", x, y)
+		return fmt.Sprintf("Pressed key: %s", combo), nil
 
-	case 1: // Click Mouse
-		coords := strings.Fields(m.input)
-		if len(coords) != 2 {
-			return "Error: Enter X Y coordinates (e.g., 100 200)"
+	case actionScroll:
+		dx, dy, err := parsePair(input)
+		if err != nil {
+			return "", fmt.Errorf("enter DX DY wheel steps (e.g., 0 5)")
 		}
-		x, err1 := strconv.Atoi(coords[0])
-		y, err2 := strconv.Atoi(coords[1])
-		if err1 != nil || err2 != nil {
-			return "Error: Invalid coordinates"
+		if err := automation.Scroll(ctx, dx, dy); err != nil {
+			return "", err
 		}
-		automation.Click(x, y)
-		return fmt.Sprintf("Clicked at %d, %d", x, y)
+		return fmt.Sprintf("Scrolled by %d, %d", dx, dy), nil
 
-	case 2: // Type Text
-		if m.input == "" {
-			return "Error: Enter text to type"
+	case actionScreenshot:
+		path, err := automation.CaptureScreenshot(ctx)
+		if err != nil {
+			return "", err
 		}
-		automation.TypeText(m.input)
-		return fmt.Sprintf("Typed: %s", m.input)
+		return fmt.Sprintf("Saved screenshot to %s", path), nil
+	}
+	return "", fmt.Errorf("unknown action")
+}
+
+// parsePair parses two integers separated by spaces or a comma
+func parsePair(s string) (int, int, error) {
+	fields := strings.Fields(strings.ReplaceAll(s, ",", " "))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("expected two numbers")
+	}
+	a, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	b, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return a, b, nil
+}
+
+// wait pauses for d or until ctx is done
+func wait(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return "Unknown action"
 }
 
 func (m model) View() string {
+	var s string
 	switch m.state {
 	case "menu":
-		return m.viewMenu()
+		s = m.viewMenu()
 	case "input":
-		return m.viewInput()
-	case "result":
-		return m.viewResult()
+		s = m.viewInput()
 	}
-	return ""
+	return s + "\n" + m.viewStatus()
 }
 
 func (m model) viewMenu() string {
@@ -183,29 +346,39 @@ func (m model) viewMenu() string {
 func (m model) viewInput() string {
 	action := m.choices[m.cursor]
 	s := fmt.Sprintf("Selected: %s\n\n", action)
-
-	switch m.cursor {
-	case 0, 1: // Move/Click Mouse
-		s += "Enter X Y coordinates (e.g., 100 200):\n"
-	case 2: // Type Text
-		s += "Enter text to type:\n"
-	}
-
-	s += fmt.Sprintf("> %s\n\n", m.input)
+	s += m.prompts[m.cursor] + "\n"
+	s += m.input.View() + "\n\n"
 	s += "Press Enter to execute, Esc to cancel, Ctrl+C to quit.\n"
 	return s
 }
 
-func (m model) viewResult() string {
-	s := "Result:\n\n"
-	s += m.result + "\n\n"
-	s += "Press Enter or Space to continue, Ctrl+C to quit.\n"
-	return s
+// viewStatus renders the status bar with the cursor position, the display and
+// the result of the last action
+func (m model) viewStatus() string {
+	status := fmt.Sprintf("Cursor %d, %d │ %s", m.x, m.y, m.display)
+	if m.posErr != nil {
+		status = fmt.Sprintf("Cursor unknown: %v │ %s", m.posErr, m.display)
+	}
+	style := statusStyle
+	if m.width > 0 {
+		style = style.Width(m.width)
+	}
+	s := style.Render(status)
+
+	if m.result != "" {
+		result := m.result
+		if m.failed {
+			result = errorStyle.Render(result)
+		}
+		s += "\n" + result
+	}
+	return s + "\n"
 }
 
-// StartTUI starts the terminal user interface
-func StartTUI() error {
-	p := tea.NewProgram(initialModel())
+// StartTUI starts the terminal user interface. Positions are entered and shown
+// in the coordinate system's space; ctx cancels running actions.
+func StartTUI(ctx context.Context, cs automation.CoordSystem) error {
+	p := tea.NewProgram(initialModel(ctx, cs), tea.WithContext(ctx))
 	_, err := p.Run()
 	return err
 }
@@ -0,0 +1,89 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"image/jpeg"
+)
+
+// tileSize is the edge length in pixels of the tiles frames are compared in
+const tileSize = 64
+
+// fullFrameRatio is the fraction of dirty tiles above which a whole frame is
+// sent instead of individual rectangles
+const fullFrameRatio = 0.5
+
+// toRGBA returns img as *image.RGBA with its origin at (0, 0)
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Rect, img, b.Min, draw.Src)
+	return rgba
+}
+
+// dirtyRects compares two frames of the same size tile by tile and returns the
+// rectangles that changed, merging horizontally adjacent dirty tiles. A nil
+// previous frame or a size change makes the whole frame dirty.
+func dirtyRects(prev, cur *image.RGBA) []image.Rectangle {
+	if prev == nil || prev.Rect != cur.Rect {
+		return []image.Rectangle{cur.Rect}
+	}
+
+	var rects []image.Rectangle
+	tiles, dirty := 0, 0
+	for y := 0; y < cur.Rect.Dy(); y += tileSize {
+		run := image.Rectangle{}
+		for x := 0; x < cur.Rect.Dx(); x += tileSize {
+			tiles++
+			tile := image.Rect(x, y, x+tileSize, y+tileSize).Intersect(cur.Rect)
+			if !tileChanged(prev, cur, tile) {
+				if !run.Empty() {
+					rects = append(rects, run)
+					run = image.Rectangle{}
+				}
+				continue
+			}
+			dirty++
+			run = run.Union(tile)
+		}
+		if !run.Empty() {
+			rects = append(rects, run)
+		}
+	}
+
+	if float64(dirty) > fullFrameRatio*float64(tiles) {
+		return []image.Rectangle{cur.Rect}
+	}
+	return rects
+}
+
+// tileChanged reports whether any pixel in r differs between a and b
+func tileChanged(a, b *image.RGBA, r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		start := a.PixOffset(r.Min.X, y)
+		end := a.PixOffset(r.Max.X, y)
+		if !bytes.Equal(a.Pix[start:end], b.Pix[start:end]) {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeRect encodes a rectangle of a frame as an update message: x, y, width
+// and height as big-endian uint32 followed by the JPEG encoded pixels
+func encodeRect(img *image.RGBA, r image.Rectangle, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	header := [4]uint32{uint32(r.Min.X), uint32(r.Min.Y), uint32(r.Dx()), uint32(r.Dy())}
+	if err := binary.Write(&buf, binary.BigEndian, header); err != nil {
+		return nil, err
+	}
+	if err := jpeg.Encode(&buf, img.SubImage(r), &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
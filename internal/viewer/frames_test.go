@@ -0,0 +1,154 @@
+package viewer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"slices"
+	"testing"
+)
+
+func TestDirtyRects(t *testing.T) {
+	// 4x2 tiles, the last column only 16 pixels wide
+	bounds := image.Rect(0, 0, 3*tileSize+16, 2*tileSize)
+
+	tests := []struct {
+		name    string
+		prev    *image.RGBA
+		changed []image.Point
+		want    []image.Rectangle
+	}{
+		{
+			name: "no previous frame",
+			want: []image.Rectangle{bounds},
+		},
+		{
+			name: "size change",
+			prev: image.NewRGBA(image.Rect(0, 0, 10, 10)),
+			want: []image.Rectangle{bounds},
+		},
+		{
+			name: "unchanged",
+			prev: image.NewRGBA(bounds),
+		},
+		{
+			name:    "single tile",
+			prev:    image.NewRGBA(bounds),
+			changed: []image.Point{{70, 10}},
+			want:    []image.Rectangle{image.Rect(tileSize, 0, 2*tileSize, tileSize)},
+		},
+		{
+			name:    "adjacent tiles are merged",
+			prev:    image.NewRGBA(bounds),
+			changed: []image.Point{{0, 0}, {tileSize, 5}},
+			want:    []image.Rectangle{image.Rect(0, 0, 2*tileSize, tileSize)},
+		},
+		{
+			name:    "separate tiles in a row",
+			prev:    image.NewRGBA(bounds),
+			changed: []image.Point{{0, 0}, {2 * tileSize, 0}},
+			want: []image.Rectangle{
+				image.Rect(0, 0, tileSize, tileSize),
+				image.Rect(2*tileSize, 0, 3*tileSize, tileSize),
+			},
+		},
+		{
+			name:    "tiles in different rows are not merged",
+			prev:    image.NewRGBA(bounds),
+			changed: []image.Point{{0, 0}, {0, tileSize}},
+			want: []image.Rectangle{
+				image.Rect(0, 0, tileSize, tileSize),
+				image.Rect(0, tileSize, tileSize, 2*tileSize),
+			},
+		},
+		{
+			name:    "partial edge tile",
+			prev:    image.NewRGBA(bounds),
+			changed: []image.Point{{bounds.Max.X - 1, bounds.Max.Y - 1}},
+			want:    []image.Rectangle{image.Rect(3*tileSize, tileSize, bounds.Max.X, bounds.Max.Y)},
+		},
+		{
+			name:    "mostly dirty sends the whole frame",
+			prev:    image.NewRGBA(bounds),
+			changed: []image.Point{{0, 0}, {tileSize, 0}, {2 * tileSize, 0}, {3 * tileSize, 0}, {0, tileSize}},
+			want:    []image.Rectangle{bounds},
+		},
+		{
+			name:    "half dirty sends rectangles",
+			prev:    image.NewRGBA(bounds),
+			changed: []image.Point{{0, 0}, {tileSize, 0}, {2 * tileSize, 0}, {3 * tileSize, 0}},
+			want:    []image.Rectangle{image.Rect(0, 0, bounds.Max.X, tileSize)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur := image.NewRGBA(bounds)
+			for _, p := range tt.changed {
+				cur.SetRGBA(p.X, p.Y, color.RGBA{255, 255, 255, 255})
+			}
+			if got := dirtyRects(tt.prev, cur); !slices.Equal(got, tt.want) {
+				t.Errorf("dirtyRects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToRGBA(t *testing.T) {
+	src := image.NewRGBA(image.Rect(10, 20, 30, 40))
+	src.SetRGBA(10, 20, color.RGBA{1, 2, 3, 255})
+
+	got := toRGBA(src)
+	if got.Rect != image.Rect(0, 0, 20, 20) {
+		t.Fatalf("bounds = %v, want origin at (0, 0)", got.Rect)
+	}
+	if c := got.RGBAAt(0, 0); c != (color.RGBA{1, 2, 3, 255}) {
+		t.Errorf("pixel at (0, 0) = %v, want the pixel at the source origin", c)
+	}
+
+	same := image.NewRGBA(image.Rect(0, 0, 5, 5))
+	if toRGBA(same) != same {
+		t.Error("an RGBA image at the origin is copied")
+	}
+}
+
+func TestEncodeRect(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 200; x++ {
+			img.SetRGBA(x, y, color.RGBA{200, 50, 50, 255})
+		}
+	}
+
+	tests := []image.Rectangle{
+		image.Rect(0, 0, 200, 100),
+		image.Rect(64, 0, 128, 64),
+		image.Rect(192, 64, 200, 100),
+	}
+	for _, r := range tests {
+		t.Run(r.String(), func(t *testing.T) {
+			data, err := encodeRect(img, r, 80)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var header [4]uint32
+			if err := binary.Read(bytes.NewReader(data[:16]), binary.BigEndian, &header); err != nil {
+				t.Fatal(err)
+			}
+			want := [4]uint32{uint32(r.Min.X), uint32(r.Min.Y), uint32(r.Dx()), uint32(r.Dy())}
+			if header != want {
+				t.Errorf("header = %v, want %v", header, want)
+			}
+
+			decoded, err := jpeg.Decode(bytes.NewReader(data[16:]))
+			if err != nil {
+				t.Fatalf("invalid JPEG: %v", err)
+			}
+			if size := decoded.Bounds().Size(); size != r.Size() {
+				t.Errorf("JPEG size = %v, want %v", size, r.Size())
+			}
+		})
+	}
+}
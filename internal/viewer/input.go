@@ -0,0 +1,72 @@
+package viewer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+)
+
+// inputEvent is an input event sent by the browser page. Coordinates are in
+// pixels of the streamed frames.
+type inputEvent struct {
+	Type      string   `json:"type"` // move, down, up, scroll, key or type
+	X         int      `json:"x"`
+	Y         int      `json:"y"`
+	Button    string   `json:"button"`
+	DX        int      `json:"dx"`
+	DY        int      `json:"dy"`
+	Key       string   `json:"key"`
+	Modifiers []string `json:"modifiers"`
+	Text      string   `json:"text"`
+}
+
+// mouseButtons are the buttons the page may press
+var mouseButtons = map[string]bool{"left": true, "right": true, "middle": true}
+
+// inject performs an input event. cs converts frame pixels to logical
+// coordinates and held tracks the mouse buttons pressed by the connection.
+func inject(ctx context.Context, cs automation.CoordSystem, held map[string]bool, ev inputEvent) error {
+	switch ev.Type {
+	case "move", "down", "up":
+		if ev.X < 0 || ev.Y < 0 {
+			return fmt.Errorf("coordinates cannot be negative: (%d, %d)", ev.X, ev.Y)
+		}
+		x, y := cs.ToLogical(ev.X, ev.Y)
+		if err := automation.MoveMouse(ctx, x, y); err != nil {
+			return err
+		}
+		if ev.Type == "move" {
+			return nil
+		}
+
+		if !mouseButtons[ev.Button] {
+			return fmt.Errorf("invalid mouse button '%s'", ev.Button)
+		}
+		if ev.Type == "down" {
+			held[ev.Button] = true
+			return automation.MouseDown(ctx, ev.Button)
+		}
+		delete(held, ev.Button)
+		return automation.MouseUp(ctx, ev.Button)
+	case "scroll":
+		return automation.Scroll(ctx, ev.DX, ev.DY)
+	case "key":
+		if strings.TrimSpace(ev.Key) == "" {
+			return fmt.Errorf("key cannot be empty")
+		}
+		return automation.PressKeyCombo(ctx, append(ev.Modifiers, ev.Key)...)
+	case "type":
+		return automation.TypeString(ctx, ev.Text)
+	}
+	return fmt.Errorf("unknown event type '%s'", ev.Type)
+}
+
+// releaseButtons releases all mouse buttons still held by a connection
+func releaseButtons(ctx context.Context, held map[string]bool) {
+	for button := range held {
+		automation.MouseUp(ctx, button)
+		delete(held, button)
+	}
+}
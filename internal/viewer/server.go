@@ -0,0 +1,205 @@
+// Package viewer streams the screen to a bundled browser page over WebSocket
+// and injects the mouse and keyboard events the page sends back, for watching
+// and taking over headless machines without a VNC stack.
+//
+// Frames are compared in tiles and only changed rectangles are sent, each as a
+// binary message holding its position and size followed by JPEG data.
+package viewer
+
+import (
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+	"golang.org/x/net/websocket"
+)
+
+//go:embed page.html
+var page []byte
+
+// Options configure the viewer
+type Options struct {
+	// FPS is the maximum number of frames captured per second
+	FPS float64
+	// Quality is the JPEG quality from 1 to 100
+	Quality int
+	// ViewOnly ignores input events from viewers
+	ViewOnly bool
+	// Token must be passed as the token query parameter by viewers
+	Token string
+}
+
+// Server streams the screen to connected viewers
+type Server struct {
+	opts Options
+
+	// inputMu serializes input events so events of concurrent viewers never interleave
+	inputMu sync.Mutex
+}
+
+// NewServer creates a viewer server
+func NewServer(opts Options) (*Server, error) {
+	if opts.FPS <= 0 || opts.FPS > 60 {
+		return nil, fmt.Errorf("fps must be between 0 and 60: %g", opts.FPS)
+	}
+	if opts.Quality < 1 || opts.Quality > 100 {
+		return nil, fmt.Errorf("quality must be between 1 and 100: %d", opts.Quality)
+	}
+	return &Server{opts: opts}, nil
+}
+
+// sizeMessage tells the page the size of the frames and whether input is accepted
+type sizeMessage struct {
+	Type     string `json:"type"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	ViewOnly bool   `json:"viewOnly"`
+}
+
+// Handler returns the HTTP handler serving the page at / and the stream at /ws
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.authorized(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	}))
+	mux.Handle("GET /ws", s.authorized(websocket.Server{Handler: s.serveConn}.ServeHTTP))
+	return mux
+}
+
+// authorized rejects requests without the server's token
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+			http.Error(w, "missing or invalid token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// serveConn streams frames to one viewer and injects its input
+func (s *Server) serveConn(ws *websocket.Conn) {
+	defer ws.Close()
+
+	ctx, cancel := context.WithCancel(ws.Request().Context())
+	defer cancel()
+
+	// The coordinate system maps frame pixels to logical coordinates; it is
+	// replaced whenever the frame size changes
+	var csMu sync.Mutex
+	var cs automation.CoordSystem
+
+	go func() {
+		// Reading fails once the viewer disconnects, which ends the stream
+		defer cancel()
+
+		held := map[string]bool{}
+		defer func() {
+			s.inputMu.Lock()
+			releaseButtons(context.Background(), held)
+			s.inputMu.Unlock()
+		}()
+
+		for {
+			var ev inputEvent
+			if err := websocket.JSON.Receive(ws, &ev); err != nil {
+				return
+			}
+			if s.opts.ViewOnly {
+				continue
+			}
+
+			csMu.Lock()
+			frameCS := cs
+			csMu.Unlock()
+
+			s.inputMu.Lock()
+			err := inject(ctx, frameCS, held, ev)
+			s.inputMu.Unlock()
+			if err != nil && ctx.Err() == nil {
+				log.Printf("viewer %s: %s event failed: %v", ws.Request().RemoteAddr, ev.Type, err)
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / s.opts.FPS))
+	defer ticker.Stop()
+
+	var prev *image.RGBA
+	for {
+		img, err := automation.CaptureImage(ctx, image.Rectangle{})
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("viewer %s: capture failed: %v", ws.Request().RemoteAddr, err)
+			}
+			return
+		}
+		frame := toRGBA(img)
+
+		if prev == nil || prev.Rect != frame.Rect {
+			csMu.Lock()
+			cs = automation.NewCoordSystem(automation.CoordScreenshot)
+			cs.ScreenshotWidth, cs.ScreenshotHeight = frame.Rect.Dx(), frame.Rect.Dy()
+			csMu.Unlock()
+
+			msg := sizeMessage{Type: "size", Width: frame.Rect.Dx(), Height: frame.Rect.Dy(), ViewOnly: s.opts.ViewOnly}
+			if err := websocket.JSON.Send(ws, msg); err != nil {
+				return
+			}
+		}
+
+		for _, r := range dirtyRects(prev, frame) {
+			data, err := encodeRect(frame, r, s.opts.Quality)
+			if err != nil {
+				log.Printf("viewer %s: encoding failed: %v", ws.Request().RemoteAddr, err)
+				return
+			}
+			if err := websocket.Message.Send(ws, data); err != nil {
+				return
+			}
+		}
+		prev = frame
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListenAndServe serves the viewer on addr until ctx is cancelled
+func ListenAndServe(ctx context.Context, addr string, s *Server) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+		// Streams never finish on their own, so close them instead of waiting
+		server.Close()
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package viewer
+
+import (
+	"context"
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dmahlow/desktop-automation/internal/automation"
+	"golang.org/x/net/websocket"
+)
+
+// fakeBackend is a blank 100x50 screen
+type fakeBackend struct {
+	automation.Backend
+}
+
+func (fakeBackend) ScreenSize(ctx context.Context) (int, int, error) {
+	return 100, 50, nil
+}
+
+func (fakeBackend) ScaleFactor(ctx context.Context) (float64, error) {
+	return 1, nil
+}
+
+func (fakeBackend) CaptureScreen(ctx context.Context, region image.Rectangle) (image.Image, error) {
+	return image.NewRGBA(image.Rect(0, 0, 100, 50)), nil
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	old := automation.CurrentBackend()
+	automation.SetBackend(fakeBackend{})
+	t.Cleanup(func() { automation.SetBackend(old) })
+
+	s, err := NewServer(Options{FPS: 10, Quality: 80, Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAuthorization(t *testing.T) {
+	srv := newTestServer(t)
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"empty token", "?token=", http.StatusUnauthorized},
+		{"wrong token", "?token=wrong", http.StatusUnauthorized},
+		{"correct token", "?token=secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + "/" + tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("GET /%s status = %d, want %d", tt.query, resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebSocketAuthorization(t *testing.T) {
+	srv := newTestServer(t)
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{"missing token", "", true},
+		{"wrong token", "?token=wrong", true},
+		{"correct token", "?token=secret", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, err := websocket.Dial(wsURL+tt.query, "", srv.URL)
+			if tt.wantErr {
+				if err == nil {
+					ws.Close()
+					t.Fatal("upgrade succeeded without a valid token")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("upgrade failed: %v", err)
+			}
+			defer ws.Close()
+
+			var msg sizeMessage
+			if err := websocket.JSON.Receive(ws, &msg); err != nil {
+				t.Fatal(err)
+			}
+			if msg.Type != "size" || msg.Width != 100 || msg.Height != 50 {
+				t.Errorf("first message = %+v, want the 100x50 frame size", msg)
+			}
+		})
+	}
+}
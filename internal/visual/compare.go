@@ -0,0 +1,146 @@
+// Package visual compares screen captures to golden images for visual
+// regression tests.
+package visual
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+
+	"github.com/dmahlow/desktop-automation/internal/imaging"
+)
+
+// Tolerance is the number of differing pixels a comparison allows, either as
+// a count or as a percentage of the compared pixels
+type Tolerance struct {
+	Value   float64
+	Percent bool
+}
+
+// ParseTolerance parses a tolerance such as "0.5%" or "20" (pixels)
+func ParseTolerance(s string) (Tolerance, error) {
+	s = strings.TrimSpace(s)
+	percent := strings.HasSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil || v < 0 || (percent && v > 100) {
+		return Tolerance{}, fmt.Errorf("invalid tolerance '%s': must be a pixel count or a percentage such as 0.5%%", s)
+	}
+	if !percent && v != float64(int(v)) {
+		return Tolerance{}, fmt.Errorf("invalid tolerance '%s': a pixel count must be a whole number", s)
+	}
+	return Tolerance{Value: v, Percent: percent}, nil
+}
+
+// String formats the tolerance the way ParseTolerance reads it
+func (t Tolerance) String() string {
+	if t.Percent {
+		return strconv.FormatFloat(t.Value, 'g', -1, 64) + "%"
+	}
+	return strconv.Itoa(int(t.Value))
+}
+
+// Result is the outcome of comparing a capture to a golden image
+type Result struct {
+	// Compared is the number of pixels outside the mask
+	Compared int
+	// Different is the number of compared pixels that differ
+	Different int
+	// Bounds encloses all differing pixels
+	Bounds image.Rectangle
+	// Diff shows the capture faded out, masked regions in blue and
+	// differing pixels in red
+	Diff *image.RGBA
+}
+
+// Ratio returns the fraction of compared pixels that differ
+func (r Result) Ratio() float64 {
+	if r.Compared == 0 {
+		return 0
+	}
+	return float64(r.Different) / float64(r.Compared)
+}
+
+// Passed reports whether the differences are within the tolerance
+func (r Result) Passed(t Tolerance) bool {
+	if t.Percent {
+		return r.Ratio()*100 <= t.Value
+	}
+	return float64(r.Different) <= t.Value
+}
+
+// Colours of the diff image
+var (
+	diffColor = color.RGBA{255, 0, 0, 255}
+	maskColor = color.RGBA{64, 128, 255, 255}
+)
+
+// Compare compares a capture to a golden image of the same size. Pixels in
+// the mask are ignored and pixels whose colour channels all differ by at most
+// threshold count as equal, which absorbs font anti-aliasing noise.
+func Compare(golden, actual image.Image, mask []MaskRegion, threshold uint8) (Result, error) {
+	gs, as := golden.Bounds().Size(), actual.Bounds().Size()
+	if gs != as {
+		return Result{}, fmt.Errorf("capture is %dx%d but the golden image is %dx%d", as.X, as.Y, gs.X, gs.Y)
+	}
+	g, a := toRGBA(golden), toRGBA(actual)
+
+	masked := image.NewAlpha(g.Rect)
+	for _, m := range mask {
+		draw.Draw(masked, m.Rect(), image.Opaque, image.Point{}, draw.Src)
+	}
+
+	result := Result{Diff: image.NewRGBA(g.Rect)}
+	for y := 0; y < gs.Y; y++ {
+		for x := 0; x < gs.X; x++ {
+			o := g.PixOffset(x, y)
+			gp, ap := g.Pix[o:o+4:o+4], a.Pix[o:o+4:o+4]
+
+			// Fade the capture to grey so the marked pixels stand out
+			lum := imaging.Blend(uint8((299*int(ap[0])+587*int(ap[1])+114*int(ap[2]))/1000), 255, 0.6)
+			faded := color.RGBA{lum, lum, lum, 255}
+
+			switch {
+			case masked.AlphaAt(x, y).A != 0:
+				result.Diff.SetRGBA(x, y, color.RGBA{imaging.Blend(lum, maskColor.R, 0.4), imaging.Blend(lum, maskColor.G, 0.4), imaging.Blend(lum, maskColor.B, 0.4), 255})
+				continue
+			case differs(gp, ap, threshold):
+				result.Different++
+				result.Bounds = result.Bounds.Union(image.Rect(x, y, x+1, y+1))
+				result.Diff.SetRGBA(x, y, diffColor)
+			default:
+				result.Diff.SetRGBA(x, y, faded)
+			}
+			result.Compared++
+		}
+	}
+	return result, nil
+}
+
+// differs reports whether any channel of two RGBA pixels differs by more
+// than threshold
+func differs(p, q []uint8, threshold uint8) bool {
+	for i := range p {
+		d := int(p[i]) - int(q[i])
+		if d < 0 {
+			d = -d
+		}
+		if d > int(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// toRGBA converts an image to RGBA with its origin at (0, 0)
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	if rgba, ok := img.(*image.RGBA); ok && b.Min == (image.Point{}) {
+		return rgba
+	}
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Rect, img, b.Min, draw.Src)
+	return rgba
+}
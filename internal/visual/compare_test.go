@@ -0,0 +1,139 @@
+package visual
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestParseTolerance(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Tolerance
+		wantErr bool
+	}{
+		{"0", Tolerance{Value: 0}, false},
+		{"20", Tolerance{Value: 20}, false},
+		{" 15 ", Tolerance{Value: 15}, false},
+		{"0.5%", Tolerance{Value: 0.5, Percent: true}, false},
+		{"100%", Tolerance{Value: 100, Percent: true}, false},
+		{"101%", Tolerance{}, true},
+		{"-1", Tolerance{}, true},
+		{"1.5", Tolerance{}, true},
+		{"abc", Tolerance{}, true},
+		{"", Tolerance{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseTolerance(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTolerance(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTolerance(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if !tt.wantErr {
+				if back, err := ParseTolerance(got.String()); err != nil || back != got {
+					t.Errorf("ParseTolerance(%q) = %+v, %v, want %+v", got.String(), back, err, got)
+				}
+			}
+		})
+	}
+}
+
+// solid returns a 10x10 image of a single colour
+func solid(c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCompare(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	golden := solid(white)
+
+	changed := solid(white)
+	for y := 2; y < 4; y++ {
+		for x := 5; x < 8; x++ {
+			changed.SetRGBA(x, y, color.RGBA{0, 0, 0, 255})
+		}
+	}
+	noisy := solid(color.RGBA{250, 252, 255, 255})
+
+	tests := []struct {
+		name          string
+		actual        image.Image
+		mask          []MaskRegion
+		threshold     uint8
+		wantCompared  int
+		wantDifferent int
+		wantBounds    image.Rectangle
+	}{
+		{"identical", solid(white), nil, 0, 100, 0, image.Rectangle{}},
+		{"changed", changed, nil, 0, 100, 6, image.Rect(5, 2, 8, 4)},
+		{"masked", changed, []MaskRegion{{X: 4, Y: 0, Width: 6, Height: 5}}, 0, 70, 0, image.Rectangle{}},
+		{"partly masked", changed, []MaskRegion{{X: 7, Y: 0, Width: 3, Height: 10}}, 0, 70, 4, image.Rect(5, 2, 7, 4)},
+		{"noise above threshold", noisy, nil, 4, 100, 100, image.Rect(0, 0, 10, 10)},
+		{"noise within threshold", noisy, nil, 5, 100, 0, image.Rectangle{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compare(golden, tt.actual, tt.mask, tt.threshold)
+			if err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+			if got.Compared != tt.wantCompared || got.Different != tt.wantDifferent || got.Bounds != tt.wantBounds {
+				t.Errorf("Compare() = %d compared, %d different in %v, want %d, %d in %v",
+					got.Compared, got.Different, got.Bounds, tt.wantCompared, tt.wantDifferent, tt.wantBounds)
+			}
+			if got.Diff.Rect != golden.Rect {
+				t.Errorf("diff bounds = %v, want %v", got.Diff.Rect, golden.Rect)
+			}
+		})
+	}
+}
+
+func TestCompareSizeMismatch(t *testing.T) {
+	small := image.NewRGBA(image.Rect(0, 0, 5, 5))
+	if _, err := Compare(solid(color.RGBA{}), small, nil, 0); err == nil {
+		t.Error("Compare() error = nil, want an error")
+	}
+}
+
+func TestCompareOffsetImage(t *testing.T) {
+	// Captures of a region keep their screen origin
+	actual := solid(color.RGBA{255, 255, 255, 255}).SubImage(image.Rect(2, 2, 7, 7))
+	golden := image.NewRGBA(image.Rect(0, 0, 5, 5))
+	for i := range golden.Pix {
+		golden.Pix[i] = 255
+	}
+	got, err := Compare(golden, actual, nil, 0)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if got.Different != 0 || got.Compared != 25 {
+		t.Errorf("Compare() = %d different of %d, want 0 of 25", got.Different, got.Compared)
+	}
+}
+
+func TestResultPassed(t *testing.T) {
+	r := Result{Compared: 1000, Different: 5}
+	tests := []struct {
+		tolerance Tolerance
+		want      bool
+	}{
+		{Tolerance{Value: 5}, true},
+		{Tolerance{Value: 4}, false},
+		{Tolerance{Value: 0.5, Percent: true}, true},
+		{Tolerance{Value: 0.4, Percent: true}, false},
+	}
+	for _, tt := range tests {
+		if got := r.Passed(tt.tolerance); got != tt.want {
+			t.Errorf("Passed(%v) = %v, want %v", tt.tolerance, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package visual
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+)
+
+// MaskRegion is a rectangle of the golden image that is ignored when
+// comparing, such as a clock or an avatar. Coordinates are pixels of the
+// golden image.
+type MaskRegion struct {
+	Name   string `json:"name,omitempty"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Rect returns the region as an image rectangle
+func (m MaskRegion) Rect() image.Rectangle {
+	return image.Rect(m.X, m.Y, m.X+m.Width, m.Y+m.Height)
+}
+
+// LoadMask reads mask regions from a JSON file holding an array of regions
+func LoadMask(path string) ([]MaskRegion, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mask: %w", err)
+	}
+
+	var regions []MaskRegion
+	if err := json.Unmarshal(data, &regions); err != nil {
+		return nil, fmt.Errorf("invalid mask %s: %w", path, err)
+	}
+	for i, r := range regions {
+		if r.Width <= 0 || r.Height <= 0 {
+			return nil, fmt.Errorf("invalid mask %s: region %d must have a positive width and height", path, i)
+		}
+	}
+	return regions, nil
+}